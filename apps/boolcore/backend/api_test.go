@@ -2,31 +2,41 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hui-cyber/BoolCore/backend/internal/api"
 )
 
-// 设置测试环境
-func setupRouter() *gin.Engine {
+// 设置测试环境，测试结束时关闭任务管理器以停止 worker
+func setupRouter(t testing.TB) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
-	api.RegisterRoutes(router)
+	jobs := api.NewJobManager(runtime.GOMAXPROCS(0), 0)
+	t.Cleanup(jobs.Close)
+	api.RegisterRoutes(router, jobs)
 	return router
 }
 
 // 测试请求结构
 type TestRequest struct {
-	Type          string `json:"type"`
-	N             int    `json:"n,omitempty"`
-	TruthTable    []byte `json:"truthTable,omitempty"`
-	HexValue      string `json:"hexValue,omitempty"`
-	IntValue      uint64 `json:"intValue,omitempty"`
-	ANFExpression string `json:"anfExpression,omitempty"`
+	Type          string   `json:"type"`
+	N             int      `json:"n,omitempty"`
+	TruthTable    []byte   `json:"truthTable,omitempty"`
+	HexValue      string   `json:"hexValue,omitempty"`
+	IntValue      uint64   `json:"intValue,omitempty"`
+	ANFExpression string   `json:"anfExpression,omitempty"`
+	Expression    string   `json:"expression,omitempty"`
+	Properties    []string `json:"properties,omitempty"`
 }
 
 // 测试响应结构
@@ -70,7 +80,7 @@ func performAPITest(t *testing.T, router *gin.Engine, req TestRequest) *TestResp
 
 // TestPingEndpoint 测试ping接口
 func TestPingEndpoint(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/api/ping", nil)
@@ -90,7 +100,7 @@ func TestPingEndpoint(t *testing.T) {
 
 // TestTruthTableInput 测试真值表输入
 func TestTruthTableInput(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	testCases := []struct {
 		name             string
@@ -156,7 +166,7 @@ func TestTruthTableInput(t *testing.T) {
 
 // TestHexInput 测试十六进制输入
 func TestHexInput(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	testCases := []struct {
 		name       string
@@ -193,7 +203,7 @@ func TestHexInput(t *testing.T) {
 
 // TestIntInput 测试整数输入
 func TestIntInput(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	testCases := []struct {
 		name       string
@@ -230,7 +240,7 @@ func TestIntInput(t *testing.T) {
 
 // TestANFInput 测试ANF输入
 func TestANFInput(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	testCases := []struct {
 		name           string
@@ -266,9 +276,594 @@ func TestANFInput(t *testing.T) {
 	}
 }
 
+// TestExprInput 测试布尔表达式输入
+func TestExprInput(t *testing.T) {
+	router := setupRouter(t)
+
+	testCases := []struct {
+		name           string
+		expression     string
+		n              int
+		expectedHW     int
+		expectedDegree int
+	}{
+		{"文档示例", "(x0 and not x1) xor x2", 3, 4, 2},
+		{"符号写法", "x0 & x1 | x2", 3, 5, 3},
+		{"常数函数", "1", 3, 8, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := TestRequest{
+				Type:       "expr",
+				N:          tc.n,
+				Expression: tc.expression,
+			}
+
+			response := performAPITest(t, router, request)
+
+			if response.Error != "" {
+				t.Errorf("意外错误: %s", response.Error)
+				return
+			}
+
+			if response.HammingWeight != tc.expectedHW {
+				t.Errorf("汉明重量不匹配: 期望 %d, 实际 %d", tc.expectedHW, response.HammingWeight)
+			}
+
+			if response.AlgebraicDegree != tc.expectedDegree {
+				t.Errorf("代数次数不匹配: 期望 %d, 实际 %d", tc.expectedDegree, response.AlgebraicDegree)
+			}
+		})
+	}
+
+	t.Run("语法错误", func(t *testing.T) {
+		response := performAPITest(t, router, TestRequest{Type: "expr", N: 3, Expression: "x0 and (x1"})
+		if response.Error == "" {
+			t.Error("语法错误应返回error字段")
+		}
+	})
+}
+
+// performRawAPITest 执行请求并返回状态码与原始 JSON 对象，便于检查字段是否存在
+func performRawAPITest(t *testing.T, router *gin.Engine, method, path string, body interface{}) (int, map[string]interface{}) {
+	var reader *bytes.Buffer
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("JSON序列化失败: %v", err)
+		}
+		reader = bytes.NewBuffer(jsonData)
+	} else {
+		reader = bytes.NewBuffer(nil)
+	}
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(method, path, reader)
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("响应JSON解析失败: %v", err)
+	}
+	return w.Code, response
+}
+
+// TestSelectiveProperties 测试按需计算属性
+func TestSelectiveProperties(t *testing.T) {
+	router := setupRouter(t)
+
+	t.Run("单个属性", func(t *testing.T) {
+		code, response := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "hex", N: 4, HexValue: "6996", Properties: []string{"nonlinearity"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", code)
+		}
+		if nl, ok := response["nonlinearity"]; !ok || nl.(float64) != 0 {
+			t.Errorf("应返回 nonlinearity=0, 实际 %v", response["nonlinearity"])
+		}
+		for _, key := range []string{"walshSpectrum", "algebraicImmunity", "fai", "hammingWeight"} {
+			if _, ok := response[key]; ok {
+				t.Errorf("未请求的字段 %s 不应出现在响应中", key)
+			}
+		}
+	})
+
+	t.Run("预设", func(t *testing.T) {
+		code, response := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "int", N: 4, IntValue: 0x6ac0, Properties: []string{"spectral"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", code)
+		}
+		for _, key := range []string{"walshSpectrum", "nonlinearity", "isBent", "absoluteIndicator", "spectralClass"} {
+			if _, ok := response[key]; !ok {
+				t.Errorf("spectral 预设应包含字段 %s", key)
+			}
+		}
+		for _, key := range []string{"algebraicImmunity", "faa", "anf"} {
+			if _, ok := response[key]; ok {
+				t.Errorf("spectral 预设不应包含字段 %s", key)
+			}
+		}
+	})
+
+	t.Run("扩散与雪崩", func(t *testing.T) {
+		code, response := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3",
+			Properties: []string{"propagationCriterion", "isSAC", "avalancheProfile"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", code)
+		}
+		if response["propagationCriterion"] != float64(4) || response["isSAC"] != true {
+			t.Errorf("bent 函数应满足 PC(4) 与 SAC, 实际 %v %v", response["propagationCriterion"], response["isSAC"])
+		}
+		profile, _ := response["avalancheProfile"].([]interface{})
+		if len(profile) != 4 {
+			t.Fatalf("雪崩表应有 4 行, 实际 %v", response["avalancheProfile"])
+		}
+		if row, _ := profile[1].(map[string]interface{}); row["weight"] != float64(2) || row["directions"] != float64(6) {
+			t.Errorf("雪崩表第 2 行应为重量 2 的 6 个方向, 实际 %v", row)
+		}
+	})
+
+	t.Run("未知属性", func(t *testing.T) {
+		code, response := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "int", N: 3, IntValue: 150, Properties: []string{"noSuchProperty"},
+		})
+		if code != http.StatusBadRequest {
+			t.Errorf("期望状态码 400, 实际得到 %d", code)
+		}
+		if response["error"] == nil {
+			t.Error("错误响应中应包含error字段")
+		}
+	})
+
+	t.Run("属性计算失败", func(t *testing.T) {
+		// 零函数的 FAA 无定义：该属性只出现在 errors 中，其余属性照常返回
+		code, response := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "int", N: 4, IntValue: 0, Properties: []string{"faa", "nonlinearity"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", code)
+		}
+		if _, ok := response["faa"]; ok {
+			t.Errorf("计算失败的字段不应出现在响应中, 实际 %v", response["faa"])
+		}
+		errs, _ := response["errors"].(map[string]interface{})
+		if msg, _ := errs["faa"].(string); msg == "" {
+			t.Errorf("errors 中应包含 faa 的失败原因, 实际 %v", response["errors"])
+		}
+		if _, ok := response["nonlinearity"]; !ok {
+			t.Error("其他属性应正常返回")
+		}
+	})
+}
+
+// TestPropertiesEndpoint 测试属性元数据接口
+func TestPropertiesEndpoint(t *testing.T) {
+	router := setupRouter(t)
+
+	code, body := performRawAPITest(t, router, "GET", "/api/properties", nil)
+	if code != http.StatusOK {
+		t.Fatalf("期望状态码 200, 实际得到 %d", code)
+	}
+	props, _ := body["properties"].([]interface{})
+	if len(props) == 0 {
+		t.Fatal("响应中缺少 properties")
+	}
+	keys := make(map[string]bool)
+	for _, p := range props {
+		prop := p.(map[string]interface{})
+		key, _ := prop["key"].(string)
+		keys[key] = true
+		for _, field := range []string{"label", "category", "type", "cost"} {
+			if v, _ := prop[field].(string); v == "" {
+				t.Errorf("属性 %s 缺少字段 %s", key, field)
+			}
+		}
+	}
+
+	// 注册表中的每个属性都能在 /api/analyze 中单独请求
+	for key := range keys {
+		code, resp := performRawAPITest(t, router, "POST", "/api/analyze", TestRequest{
+			Type: "hex", N: 4, HexValue: "6ac0", Properties: []string{key},
+		})
+		if code != http.StatusOK {
+			t.Errorf("请求属性 %s 期望状态码 200, 实际得到 %d", key, code)
+			continue
+		}
+		if _, ok := resp[key]; !ok {
+			t.Errorf("请求属性 %s 的响应中缺少该字段", key)
+		}
+	}
+
+	if _, ok := body["categories"].([]interface{}); !ok {
+		t.Error("响应中缺少 categories")
+	}
+	presets, _ := body["presets"].(map[string]interface{})
+	if _, ok := presets["fast"]; !ok {
+		t.Error("响应中缺少 fast 预设")
+	}
+}
+
+// TestEquivalenceEndpoint 测试仿射等价判定接口
+func TestEquivalenceEndpoint(t *testing.T) {
+	router := setupRouter(t)
+
+	t.Run("扩展仿射等价", func(t *testing.T) {
+		code, body := performRawAPITest(t, router, "POST", "/api/equivalence", gin.H{
+			"f":        TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3"},
+			"g":        TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x2 + x1*x3 + x0 + 1"},
+			"extended": true,
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d: %v", code, body)
+		}
+		if body["equivalent"] != true || body["decided"] != true {
+			t.Errorf("应判定为等价, 实际 %v", body)
+		}
+		m, _ := body["map"].(map[string]interface{})
+		if rows, _ := m["matrix"].([]interface{}); len(rows) != 4 {
+			t.Errorf("应返回 4x4 的变换矩阵, 实际 %v", body["map"])
+		}
+		if fmt.Sprint(body["canonicalF"]) != fmt.Sprint(body["canonicalG"]) {
+			t.Errorf("等价函数的规范形式应相同: %v vs %v", body["canonicalF"], body["canonicalG"])
+		}
+	})
+
+	t.Run("不等价", func(t *testing.T) {
+		code, body := performRawAPITest(t, router, "POST", "/api/equivalence", gin.H{
+			"f": TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x1"},
+			"g": TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", code)
+		}
+		if body["equivalent"] != false || body["decided"] != true || body["reason"] == nil {
+			t.Errorf("仿射意义下应判定为不等价并给出原因, 实际 %v", body)
+		}
+	})
+
+	t.Run("输入非法", func(t *testing.T) {
+		code, body := performRawAPITest(t, router, "POST", "/api/equivalence", gin.H{
+			"f": TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x1"},
+			"g": TestRequest{Type: "hex"},
+		})
+		if code != http.StatusBadRequest || body["error"] == nil {
+			t.Errorf("期望状态码 400 与 error 字段, 实际 %d %v", code, body)
+		}
+	})
+}
+
+// TestGenerateEndpoint 测试按构造族生成函数的接口
+func TestGenerateEndpoint(t *testing.T) {
+	router := setupRouter(t)
+
+	cases := []struct {
+		name  string
+		body  gin.H
+		n     float64
+		check map[string]interface{}
+	}{
+		{"MM", gin.H{"family": "mm", "m": 2, "permutation": []int{2, 0, 3, 1}, "g": []int{0, 1, 1, 0}}, 4,
+			map[string]interface{}{"isBent": true}},
+		{"PS_ap 默认 g", gin.H{"family": "psap", "m": 3}, 6, map[string]interface{}{"isBent": true}},
+		{"Tu-Deng", gin.H{"family": "tuDeng", "m": 3, "shift": 2}, 6,
+			map[string]interface{}{"isBent": true, "algebraicImmunity": float64(3)}},
+		{"Rothaus", gin.H{"family": "rothaus", "functions": []TestRequest{
+			{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3"},
+			{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3 + x0"},
+			{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3 + x1*x2"},
+		}}, 6, map[string]interface{}{"isBent": true}},
+		{"择多", gin.H{"family": "majority", "n": 5}, 5,
+			map[string]interface{}{"isBalanced": true, "algebraicImmunity": float64(3)}},
+		{"Carlet-Feng", gin.H{"family": "carletFeng", "n": 6, "shift": 1}, 6,
+			map[string]interface{}{"isBalanced": true, "algebraicImmunity": float64(3), "algebraicDegree": float64(5)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body := gin.H{"properties": []string{"isBent", "isBalanced", "algebraicImmunity", "algebraicDegree"}}
+			for k, v := range tc.body {
+				body[k] = v
+			}
+			code, resp := performRawAPITest(t, router, "POST", "/api/generate", body)
+			if code != http.StatusOK {
+				t.Fatalf("期望状态码 200, 实际得到 %d: %v", code, resp)
+			}
+			if resp["n"] != tc.n {
+				t.Errorf("期望 n=%v, 实际 %v", tc.n, resp["n"])
+			}
+			for key, want := range tc.check {
+				if resp[key] != want {
+					t.Errorf("期望 %s=%v, 实际 %v", key, want, resp[key])
+				}
+			}
+		})
+	}
+
+	// 弹性函数构造附带理论值与实测值的对比
+	for _, body := range []gin.H{
+		{"family": "resilientMM", "n": 7, "m": 2},
+		{"family": "tarannikov", "n": 9, "m": 4},
+	} {
+		body["properties"] = []string{"resiliencyOrder", "nonlinearity"}
+		code, resp := performRawAPITest(t, router, "POST", "/api/generate", body)
+		if code != http.StatusOK {
+			t.Fatalf("%v: 期望状态码 200, 实际得到 %d: %v", body["family"], code, resp)
+		}
+		predictions, _ := resp["predictions"].([]interface{})
+		if resp["method"] == nil || len(predictions) == 0 {
+			t.Fatalf("%v: 响应缺少 method 或 predictions: %v", body["family"], resp)
+		}
+		for _, p := range predictions {
+			if check := p.(map[string]interface{}); check["holds"] != true {
+				t.Errorf("%v: 理论值不成立: %v", body["family"], check)
+			}
+		}
+		if resp["resiliencyOrder"] != float64(body["m"].(int)) {
+			t.Errorf("%v: 期望 resiliencyOrder=%v, 实际 %v", body["family"], body["m"], resp["resiliencyOrder"])
+		}
+	}
+	if _, resp := performRawAPITest(t, router, "POST", "/api/generate", gin.H{"family": "mm", "m": 2}); resp["predictions"] != nil {
+		t.Error("没有理论值的构造族不应返回 predictions")
+	}
+
+	for _, body := range []gin.H{
+		{"family": "noSuchFamily"},
+		{"family": "mm"},
+		{"family": "tarannikov", "m": 2},
+		{"family": "resilientMM", "n": 4, "m": 4},
+		{"family": "psap", "m": 2, "g": []int{1, 0, 0, 1}},
+		{"family": "rothaus", "functions": []TestRequest{{Type: "anf", N: 4, ANFExpression: "x0"}}},
+	} {
+		if code, resp := performRawAPITest(t, router, "POST", "/api/generate", body); code != http.StatusBadRequest || resp["error"] == nil {
+			t.Errorf("%v: 期望状态码 400 与 error 字段, 实际 %d %v", body, code, resp)
+		}
+	}
+}
+
+// randomTruthTable 生成确定性的伪随机 n 元真值表
+func randomTruthTable(n int, seed int64) []byte {
+	rng := rand.New(rand.NewSource(seed))
+	tt := make([]byte, 1<<n)
+	for i := range tt {
+		tt[i] = byte(rng.Intn(2))
+	}
+	return tt
+}
+
+// waitForJob 轮询任务直到进入终态或超时
+func waitForJob(t *testing.T, router *gin.Engine, id string, timeout time.Duration) map[string]interface{} {
+	deadline := time.Now().Add(timeout)
+	for {
+		code, view := performRawAPITest(t, router, "GET", "/api/jobs/"+id, nil)
+		if code != http.StatusOK {
+			t.Fatalf("查询任务期望状态码 200, 实际得到 %d", code)
+		}
+		switch view["status"] {
+		case "succeeded", "failed", "cancelled":
+			return view
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("任务 %s 在 %v 内未结束, 当前状态 %v", id, timeout, view["status"])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestAnalysisJobs 测试异步分析任务的提交、查询与取消
+func TestAnalysisJobs(t *testing.T) {
+	router := setupRouter(t)
+
+	t.Run("提交并完成", func(t *testing.T) {
+		code, view := performRawAPITest(t, router, "POST", "/api/jobs", TestRequest{
+			Type: "hex", N: 4, HexValue: "6ac0", Properties: []string{"nonlinearity", "algebraicImmunity"},
+		})
+		if code != http.StatusAccepted {
+			t.Fatalf("期望状态码 202, 实际得到 %d", code)
+		}
+		id, _ := view["id"].(string)
+		if id == "" {
+			t.Fatal("响应中缺少任务 id")
+		}
+
+		final := waitForJob(t, router, id, 5*time.Second)
+		if final["status"] != "succeeded" {
+			t.Fatalf("任务应成功, 实际状态 %v (%v)", final["status"], final["error"])
+		}
+		result, _ := final["result"].(map[string]interface{})
+		if result["nonlinearity"] != float64(6) {
+			t.Errorf("非线性度应为 6, 实际 %v", result["nonlinearity"])
+		}
+		if _, ok := result["walshSpectrum"]; ok {
+			t.Error("未请求的字段 walshSpectrum 不应出现在结果中")
+		}
+	})
+
+	t.Run("取消耗时任务", func(t *testing.T) {
+		code, view := performRawAPITest(t, router, "POST", "/api/jobs", TestRequest{
+			Type: "truthTable", TruthTable: randomTruthTable(14, 1), Properties: []string{"algebraic"},
+		})
+		if code != http.StatusAccepted {
+			t.Fatalf("期望状态码 202, 实际得到 %d", code)
+		}
+		id := view["id"].(string)
+
+		code, _ = performRawAPITest(t, router, "DELETE", "/api/jobs/"+id, nil)
+		if code != http.StatusOK {
+			t.Fatalf("取消任务期望状态码 200, 实际得到 %d", code)
+		}
+		final := waitForJob(t, router, id, 5*time.Second)
+		if final["status"] != "cancelled" {
+			t.Errorf("任务应被取消, 实际状态 %v", final["status"])
+		}
+	})
+
+	t.Run("事件流", func(t *testing.T) {
+		code, view := performRawAPITest(t, router, "POST", "/api/jobs", TestRequest{
+			Type: "truthTable", TruthTable: randomTruthTable(12, 2), Properties: []string{"algebraicImmunity"},
+		})
+		if code != http.StatusAccepted {
+			t.Fatalf("期望状态码 202, 实际得到 %d", code)
+		}
+		id := view["id"].(string)
+
+		// 任务结束后事件流才会关闭，因此这里的请求会一直读到最终状态
+		req, _ := http.NewRequest("GET", "/api/jobs/"+id+"/events", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("期望状态码 200, 实际得到 %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+			t.Errorf("Content-Type 应为 text/event-stream, 实际 %q", ct)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "event:status") {
+			t.Errorf("事件流中缺少 status 事件:\n%s", body)
+		}
+		if !strings.Contains(body, `"status":"succeeded"`) {
+			t.Errorf("事件流应以成功状态结束:\n%s", body)
+		}
+
+		code, _ = performRawAPITest(t, router, "GET", "/api/jobs/unknown/events", nil)
+		if code != http.StatusNotFound {
+			t.Errorf("未知任务期望状态码 404, 实际得到 %d", code)
+		}
+	})
+
+	t.Run("未知任务", func(t *testing.T) {
+		code, _ := performRawAPITest(t, router, "GET", "/api/jobs/unknown", nil)
+		if code != http.StatusNotFound {
+			t.Errorf("期望状态码 404, 实际得到 %d", code)
+		}
+	})
+}
+
+// TestJobManagerClose 测试关闭任务管理器会取消运行中的任务并拒绝新的提交
+func TestJobManagerClose(t *testing.T) {
+	jobs := api.NewJobManager(1, 0)
+	started := make(chan struct{})
+	running, err := jobs.Submit(func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	queued, _ := jobs.Submit(func(ctx context.Context) (interface{}, error) { return nil, nil })
+	<-started
+	jobs.Close() // 返回时 worker 已退出
+	for _, id := range []string{running.ID, queued.ID} {
+		if view, _ := jobs.Get(id); view.Status != api.JobCancelled {
+			t.Errorf("关闭后任务 %s 应为已取消, 实际 %v", id, view.Status)
+		}
+	}
+	if _, err := jobs.Submit(func(ctx context.Context) (interface{}, error) { return nil, nil }); err == nil {
+		t.Error("关闭后提交应返回错误")
+	}
+	jobs.Close() // 重复关闭无副作用
+}
+
+// TestSearchJobs 测试启发式搜索任务的提交、结果与取消
+func TestSearchJobs(t *testing.T) {
+	router := setupRouter(t)
+
+	submit := func(t *testing.T, body gin.H) map[string]interface{} {
+		t.Helper()
+		code, view := performRawAPITest(t, router, "POST", "/api/search", body)
+		if code != http.StatusAccepted {
+			t.Fatalf("期望状态码 202, 实际得到 %d (%v)", code, view["error"])
+		}
+		return view
+	}
+
+	t.Run("退火搜索", func(t *testing.T) {
+		body := gin.H{"n": 7, "algorithm": "annealing", "seed": 3, "balanced": true, "iterations": 2000,
+			"cost": gin.H{"nonlinearity": 1, "absoluteIndicator": 0.5}, "properties": []string{"nonlinearity", "isBalanced"}}
+		var tables []interface{}
+		for i := 0; i < 2; i++ {
+			final := waitForJob(t, router, submit(t, body)["id"].(string), 10*time.Second)
+			if final["status"] != "succeeded" {
+				t.Fatalf("任务应成功, 实际状态 %v (%v)", final["status"], final["error"])
+			}
+			result, _ := final["result"].(map[string]interface{})
+			search, _ := result["search"].(map[string]interface{})
+			if search["algorithm"] != "annealing" || search["iterations"] != float64(2000) {
+				t.Errorf("搜索统计错误: %v", search)
+			}
+			if result["isBalanced"] != true || result["nonlinearity"] != search["nonlinearity"] {
+				t.Errorf("结果应平衡且非线性度一致: %v / %v", result["nonlinearity"], search["nonlinearity"])
+			}
+			if nl, _ := result["nonlinearity"].(float64); nl < 50 {
+				t.Errorf("7 元搜索结果的非线性度过低: %v", nl)
+			}
+			if _, ok := result["walshSpectrum"]; ok {
+				t.Error("未请求的字段 walshSpectrum 不应出现在结果中")
+			}
+			tables = append(tables, result["truthTable"])
+		}
+		if fmt.Sprint(tables[0]) != fmt.Sprint(tables[1]) {
+			t.Error("相同种子的两次搜索应得到相同的函数")
+		}
+	})
+
+	t.Run("从初始函数出发", func(t *testing.T) {
+		final := waitForJob(t, router, submit(t, gin.H{"n": 4, "algorithm": "genetic", "iterations": 20,
+			"initial":    TestRequest{Type: "anf", N: 4, ANFExpression: "x0*x1 + x2*x3"},
+			"properties": []string{"isBent"}})["id"].(string), 10*time.Second)
+		result, _ := final["result"].(map[string]interface{})
+		if final["status"] != "succeeded" || result["isBent"] != true {
+			t.Errorf("以 bent 函数为初始个体时结果应为 bent 函数: %v %v", final["status"], result["isBent"])
+		}
+	})
+
+	t.Run("旋转对称搜索", func(t *testing.T) {
+		final := waitForJob(t, router, submit(t, gin.H{"n": 9, "algorithm": "hillClimbing", "seed": 1, "balanced": true,
+			"rotationSymmetric": true, "iterations": 2000,
+			"properties": []string{"isRotationSymmetric", "isBalanced"}})["id"].(string), 10*time.Second)
+		result, _ := final["result"].(map[string]interface{})
+		if final["status"] != "succeeded" || result["isRotationSymmetric"] != true || result["isBalanced"] != true {
+			t.Errorf("结果应为平衡的旋转对称函数: %v %v %v", final["status"], result["isRotationSymmetric"], result["isBalanced"])
+		}
+	})
+
+	t.Run("取消", func(t *testing.T) {
+		id := submit(t, gin.H{"n": 12, "algorithm": "hillClimbing", "iterations": 1000000})["id"].(string)
+		code, _ := performRawAPITest(t, router, "DELETE", "/api/jobs/"+id, nil)
+		if code != http.StatusOK {
+			t.Fatalf("取消任务期望状态码 200, 实际得到 %d", code)
+		}
+		if final := waitForJob(t, router, id, 5*time.Second); final["status"] != "cancelled" {
+			t.Errorf("任务应被取消, 实际状态 %v", final["status"])
+		}
+	})
+
+	t.Run("参数错误", func(t *testing.T) {
+		for _, body := range []gin.H{
+			{"n": 1},
+			{"n": 6, "algorithm": "tabu"},
+			{"n": 6, "balanced": true, "initial": TestRequest{Type: "hex", N: 6, HexValue: "1"}},
+			{"n": 6, "initial": TestRequest{Type: "hex"}},
+			{"n": 6, "properties": []string{"unknown"}},
+			{"n": 17, "rotationSymmetric": true},
+		} {
+			code, resp := performRawAPITest(t, router, "POST", "/api/search", body)
+			if code != http.StatusBadRequest || resp["error"] == nil {
+				t.Errorf("%v: 期望状态码 400 与错误信息, 实际得到 %d", body, code)
+			}
+		}
+	})
+}
+
 // TestSpecialFunctions 测试特殊函数类型
 func TestSpecialFunctions(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	// Bent函数测试 (n=4, hex=6996)
 	t.Run("Bent函数测试", func(t *testing.T) {
@@ -301,7 +896,7 @@ func TestSpecialFunctions(t *testing.T) {
 
 // TestErrorHandling 测试错误处理
 func TestErrorHandling(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	errorCases := []struct {
 		name    string
@@ -362,7 +957,7 @@ func TestErrorHandling(t *testing.T) {
 
 // TestInputFormatConsistency 测试不同输入格式的一致性
 func TestInputFormatConsistency(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(t)
 
 	// 测试相同函数的不同表示方式应产生相同结果
 	// 例: 真值表[0,1,1,0,1,0,0,1] = hex 96 = int 150
@@ -408,7 +1003,7 @@ func TestInputFormatConsistency(t *testing.T) {
 
 // BenchmarkAnalyzeFunction 性能基准测试
 func BenchmarkAnalyzeFunction(b *testing.B) {
-	router := setupRouter()
+	router := setupRouter(b)
 
 	request := TestRequest{
 		Type:     "int",
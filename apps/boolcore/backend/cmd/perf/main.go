@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -17,31 +18,13 @@ type stepTiming struct {
 }
 
 type perfResult struct {
-	N          int          `json:"n"`
-	Input      string       `json:"input"`
-	Repeat     int          `json:"repeat"`
-	TotalUs    float64      `json:"total_us"`
-	Steps      []stepTiming `json:"steps"`
-	Properties struct {
-		HammingWeight          int     `json:"hammingWeight"`
-		IsBalanced             bool    `json:"isBalanced"`
-		ANFLen                 int     `json:"anfLen"`
-		AlgebraicDegree        int     `json:"algebraicDegree"`
-		TransparencyOrder      float64 `json:"transparencyOrder"`
-		WalshLen               int     `json:"walshLen"`
-		AutocorrLen            int     `json:"autocorrelationLen"`
-		Nonlinearity           int64   `json:"nonlinearity"`
-		CorrelationImmunity    int     `json:"correlationImmunity"`
-		ResiliencyOrder        int     `json:"resiliencyOrder"`
-		IsBent                 bool    `json:"isBent"`
-		SumOfSquareIndicator   int64   `json:"sumOfSquareIndicator"`
-		IsRotationSymmetric    bool    `json:"isRotationSymmetric"`
-		AbsoluteWalshKinds     int     `json:"absoluteWalshKinds"`
-		AbsoluteAutocorrKinds  int     `json:"absoluteAutocorrKinds"`
-		AbsoluteIndicator      int64   `json:"absoluteIndicator"`
-		DifferentialUniformity int64   `json:"differentialUniformity"`
-		AlgebraicImmunity      int     `json:"algebraicImmunity"`
-	} `json:"properties"`
+	N          int                    `json:"n"`
+	Input      string                 `json:"input"`
+	Repeat     int                    `json:"repeat"`
+	TotalUs    float64                `json:"total_us"`
+	Steps      []stepTiming           `json:"steps"`
+	Properties map[string]interface{} `json:"properties"`
+	Errors     map[string]string      `json:"errors,omitempty"`
 }
 
 func main() {
@@ -51,15 +34,17 @@ func main() {
 		intValue uint64
 		hexValue string
 		anf      string
+		expr     string
 		repeat   int
 		format   string
 	)
 
-	flag.StringVar(&inType, "type", "int", "input type: int|hex|anf|truth (truth not implemented in CLI)")
+	flag.StringVar(&inType, "type", "int", "input type: int|hex|anf|expr|truth (truth not implemented in CLI)")
 	flag.IntVar(&n, "n", 6, "number of variables")
 	flag.Uint64Var(&intValue, "int", 123456, "integer value for truth table (low bit = index 0)")
 	flag.StringVar(&hexValue, "hex", "", "hex value for truth table")
 	flag.StringVar(&anf, "anf", "", "ANF expression, e.g. 'x0 + x1*x2 + 1'")
+	flag.StringVar(&expr, "expr", "", "boolean expression, e.g. '(x0 and not x1) xor x2'")
 	flag.IntVar(&repeat, "repeat", 1, "repeat runs to warm cache and average")
 	flag.StringVar(&format, "format", "json", "output format: json|text")
 	flag.Parse()
@@ -78,6 +63,9 @@ func main() {
 	case "anf":
 		bf, err = booleancore.NewFromANF(n, anf)
 		inputDesc = fmt.Sprintf("anf:%s", anf)
+	case "expr":
+		bf, err = booleancore.NewFromExpr(n, expr)
+		inputDesc = fmt.Sprintf("expr:%s", expr)
 	default:
 		fmt.Fprintf(os.Stderr, "unsupported type: %s\n", inType)
 		os.Exit(2)
@@ -99,44 +87,37 @@ func main() {
 			bf, err = booleancore.NewFromHex(hexValue, n)
 		case "anf":
 			bf, err = booleancore.NewFromANF(n, anf)
+		case "expr":
+			bf, err = booleancore.NewFromExpr(n, expr)
 		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "construct run %d error: %v\n", r, err)
 			os.Exit(2)
 		}
 
-		res := perfResult{N: n, Input: inputDesc, Repeat: repeat}
-		_, timings, _ := booleancore.AnalyzeAllTimed(bf)
-
-		// 重新计算一次用于取属性输出（避免复制大切片），这里用 AnalyzeAll 更简洁
-		props := booleancore.AnalyzeAll(bf)
-
-		// 基本属性
-		res.Properties.HammingWeight = props.HammingWeight
-		res.Properties.IsBalanced = props.IsBalanced
-		res.Properties.ANFLen = len(props.ANF)
-		res.Properties.AlgebraicDegree = props.AlgebraicDegree
-		res.Properties.TransparencyOrder = props.TransparencyOrder
-		res.Properties.Nonlinearity = props.Nonlinearity
-		res.Properties.CorrelationImmunity = props.CorrelationImmunity
-		res.Properties.ResiliencyOrder = props.ResiliencyOrder
-		res.Properties.IsBent = props.IsBent
-		res.Properties.SumOfSquareIndicator = props.SumOfSquareIndicator
-		res.Properties.IsRotationSymmetric = props.IsRotationSymmetric
-		res.Properties.AbsoluteWalshKinds = len(props.AbsoluteWalshSpectrum)
-		res.Properties.AbsoluteAutocorrKinds = len(props.AbsoluteAutocorrelationSpectrum)
-		res.Properties.AbsoluteIndicator = props.AbsoluteIndicator
-		res.Properties.DifferentialUniformity = props.DifferentialUniformity
-		res.Properties.AlgebraicImmunity = props.AlgebraicImmunity
-
-		// 长度信息
-		res.Properties.WalshLen = len(props.WalshSpectrum)
-		res.Properties.AutocorrLen = len(props.AutocorrelationSpectrum)
-
-		// 步骤时间
-		for name, dur := range timings {
-			// 使用纳秒转微秒(浮点)以保留子微秒级的小数部分
-			res.Steps = append(res.Steps, stepTiming{Name: name, Micros: float64(dur.Nanoseconds()) / 1000.0})
+		res := perfResult{N: n, Input: inputDesc, Repeat: repeat, Properties: make(map[string]interface{})}
+		props, timings, _ := booleancore.AnalyzeAllTimed(bf)
+
+		// 属性与步骤都按注册表的计算顺序输出，新注册的属性自动包含在内；
+		// 自由文本（如 anf）、数组、映射类的结果只输出长度，避免打印大块数据，键名见 lengthKey
+		for _, info := range booleancore.Properties() {
+			if err, failed := props.Errors[info.Key]; failed {
+				if res.Errors == nil {
+					res.Errors = make(map[string]string)
+				}
+				res.Errors[info.Key] = err.Error()
+			} else if v, ok := props.Value(info.Key); ok && v != nil { // nil 表示结果不存在，如非 plateaued 函数的阶数
+				if n, ok := valueLen(info.ResultType, v); ok {
+					res.Properties[lengthKey(info.Key)] = n
+				} else {
+					res.Properties[info.Key] = v
+				}
+			}
+
+			if dur, ok := timings[info.Key]; ok {
+				// 使用纳秒转微秒(浮点)以保留子微秒级的小数部分
+				res.Steps = append(res.Steps, stepTiming{Name: info.Key, Micros: float64(dur.Nanoseconds()) / 1000.0})
+			}
 		}
 
 		last = res
@@ -157,3 +138,39 @@ func main() {
 		}
 	}
 }
+
+// legacyLengthKeys 保留改为按注册表输出之前的长度键名，已有脚本按这些键读取.
+var legacyLengthKeys = map[string]string{
+	booleancore.PropWalshSpectrum:                   "walshLen",
+	booleancore.PropAutocorrelationSpectrum:         "autocorrelationLen",
+	booleancore.PropAbsoluteWalshSpectrum:           "absoluteWalshKinds",
+	booleancore.PropAbsoluteAutocorrelationSpectrum: "absoluteAutocorrKinds",
+}
+
+// lengthKey 返回只输出长度的属性的键名：旧键名优先，其余为属性键加 Len 后缀（如 anfLen）.
+func lengthKey(key string) string {
+	if legacy, ok := legacyLengthKeys[key]; ok {
+		return legacy
+	}
+	return key + "Len"
+}
+
+// valueLen 返回自由文本、数组、映射类结果的长度；标量与枚举类文本（如 spectralClass）原样输出，返回 false.
+// 枚举类文本的值是具名的字符串类型，自由文本（如 anf，n 较大时可达数 MB）是 string.
+func valueLen(resultType string, v interface{}) (int, bool) {
+	switch resultType {
+	case booleancore.ResultText, booleancore.ResultArray, booleancore.ResultObject:
+	default:
+		return 0, false
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.String:
+		if rv.Type() != reflect.TypeOf("") {
+			return 0, false
+		}
+		return rv.Len(), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/hui-cyber/BoolCore/backend/pkg/booleancore"
+)
+
+// 启发式搜索 CLI：在命令行运行 booleancore.Search，例如
+//
+//	go run ./cmd/search -n 9 -algorithm annealing -seed 1 -balanced -iterations 200000 -format text
+//	go run ./cmd/search -n 11 -algorithm annealing -seed 1 -balanced -rotation -iterations 100000 -format text
+//
+// 结果的十六进制与 NewFromHex 的约定一致（第 i 位为 f(i)），可直接作为 -init 或 /api/analyze 的输入。
+
+type searchOutput struct {
+	*booleancore.SearchResult
+	N       int     `json:"n"`
+	Hex     string  `json:"hex"`
+	Seconds float64 `json:"seconds"`
+}
+
+func main() {
+	var (
+		opts     booleancore.SearchOptions
+		alg      string
+		initHex  string
+		format   string
+		progress bool
+		timeout  time.Duration
+	)
+
+	flag.IntVar(&opts.N, "n", 8, "number of variables")
+	flag.StringVar(&alg, "algorithm", "hillClimbing", "search algorithm: hillClimbing|annealing|genetic")
+	flag.Int64Var(&opts.Seed, "seed", 1, "random seed, the same seed reproduces the same result")
+	flag.BoolVar(&opts.Balanced, "balanced", false, "search balanced functions only")
+	flag.BoolVar(&opts.RotationSymmetric, "rotation", false, "search rotation symmetric functions only (n <= 16)")
+	flag.IntVar(&opts.Iterations, "iterations", 0, "steps for hillClimbing/annealing, generations for genetic (0 = default)")
+	flag.Float64Var(&opts.Cost.Nonlinearity, "w-nl", booleancore.DefaultSearchCost.Nonlinearity, "cost weight of -nonlinearity")
+	flag.Float64Var(&opts.Cost.AbsoluteIndicator, "w-ac", booleancore.DefaultSearchCost.AbsoluteIndicator, "cost weight of absolute indicator")
+	flag.Float64Var(&opts.Cost.TransparencyOrder, "w-to", booleancore.DefaultSearchCost.TransparencyOrder, "cost weight of -transparency order")
+	flag.Float64Var(&opts.Cost.Imbalance, "w-imbalance", booleancore.DefaultSearchCost.Imbalance, "cost weight of |wt - 2^(n-1)|")
+	flag.Float64Var(&opts.Cost.Spectral, "w-spectral", booleancore.DefaultSearchCost.Spectral, "cost weight of the Clark-Jacob spectral cost")
+	flag.Float64Var(&opts.Temperature, "temperature", 0, "initial temperature for annealing (0 = default)")
+	flag.Float64Var(&opts.CoolingRate, "cooling", 0, "cooling rate per step for annealing (0 = derived from iterations)")
+	flag.IntVar(&opts.Population, "population", 0, "population size for genetic (0 = default)")
+	flag.IntVar(&opts.Mutations, "mutations", 0, "neighbour moves per child for genetic (0 = 1)")
+	flag.StringVar(&initHex, "init", "", "hex truth table of the initial function, spaces allowed")
+	flag.StringVar(&format, "format", "json", "output format: json|text")
+	flag.BoolVar(&progress, "progress", false, "print progress to stderr")
+	flag.DurationVar(&timeout, "timeout", 0, "stop after this duration (0 = no limit)")
+	flag.Parse()
+
+	opts.Algorithm = booleancore.SearchAlgorithm(alg)
+	if initHex != "" {
+		f, err := booleancore.NewFromHex(strings.Join(strings.Fields(initHex), ""), opts.N)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -init: %v\n", err)
+			os.Exit(2)
+		}
+		opts.Initial = f
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid options: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Ctrl-C 与 -timeout 都通过 context 停止搜索
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if progress {
+		ctx = booleancore.WithProgress(ctx, func(ev booleancore.ProgressEvent) {
+			fmt.Fprintf(os.Stderr, "\r%s %d/%d cost=%.4f", ev.Metric, ev.Row, ev.Rows, *ev.Cost)
+			if ev.Done {
+				fmt.Fprintln(os.Stderr)
+			}
+		})
+	}
+
+	start := time.Now()
+	result, err := booleancore.Search(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "search failed: %v\n", err)
+		os.Exit(1)
+	}
+	out := searchOutput{
+		SearchResult: result,
+		N:            opts.N,
+		Hex:          hexTruthTable(result.Function),
+		Seconds:      time.Since(start).Seconds(),
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+	default:
+		fmt.Printf("%d-variable, nl(f) = %d, ac(f) = %d, To(f) = %.4f, Balanced: %v\n",
+			out.N, out.Nonlinearity, out.AbsoluteIndicator, out.TransparencyOrder, out.IsBalanced)
+		fmt.Printf("algorithm=%s seed=%d cost=%.4f iterations=%d evaluations=%d time=%.3fs\n",
+			out.Algorithm, out.Seed, out.Cost, out.Iterations, out.Evaluations, out.Seconds)
+		fmt.Printf("HEX: %s\n", out.Hex)
+	}
+}
+
+// hexTruthTable 返回真值表的十六进制表示（第 i 位为 f(i)，按 2^n/4 位补零），是 NewFromHex 的逆.
+func hexTruthTable(f *booleancore.BooleanFunction) string {
+	val := new(big.Int)
+	for i, v := range f.TruthTable() {
+		if v == 1 {
+			val.SetBit(val, i, 1)
+		}
+	}
+	digits := (1<<uint(f.N()) + 3) / 4
+	return fmt.Sprintf("%0*x", digits, val)
+}
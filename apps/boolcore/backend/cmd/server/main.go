@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/gin-contrib/cors"
@@ -16,8 +17,11 @@ func main() {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = corsOrigins()
 	router.Use(cors.New(config))
+	// 异步任务的 worker 数与可用 CPU 数一致，每个任务单线程运行
+	jobs := api.NewJobManager(runtime.GOMAXPROCS(0), 0)
+	defer jobs.Close()
 	// 注册我们定义的所有路由
-	api.RegisterRoutes(router)
+	api.RegisterRoutes(router, jobs)
 
 	addr := serverAddress()
 	if err := router.Run(addr); err != nil {
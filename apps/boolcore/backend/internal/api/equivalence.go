@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hui-cyber/BoolCore/backend/pkg/booleancore"
+)
+
+// EquivalenceRequest 是 POST /api/equivalence 的请求，f、g 的输入方式与 /api/analyze 相同（properties 被忽略）.
+type EquivalenceRequest struct {
+	F        AnalyzeRequest `json:"f" binding:"required"`
+	G        AnalyzeRequest `json:"g" binding:"required"`
+	Extended bool           `json:"extended"` // 是否允许加上线性函数（扩展仿射等价）
+}
+
+// AffineMapResponse 描述变换 g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d，向量的第 i 位对应 x_i.
+type AffineMapResponse struct {
+	Matrix [][]int `json:"matrix"` // A 按行给出
+	B      int     `json:"b"`
+	C      int     `json:"c"`
+	D      int     `json:"d"`
+}
+
+// EquivalenceResponse 是 POST /api/equivalence 的响应.
+type EquivalenceResponse struct {
+	N          int                `json:"n"`
+	Equivalent bool               `json:"equivalent"`
+	Decided    bool               `json:"decided"`          // n > 6 且所有不变量都相同时为 false
+	Reason     string             `json:"reason,omitempty"` // 不等价的原因或未判定的说明
+	Map        *AffineMapResponse `json:"map,omitempty"`    // 等价时把 f 变为 g 的变换
+	CanonicalF []int              `json:"canonicalF,omitempty"`
+	CanonicalG []int              `json:"canonicalG,omitempty"` // n ≤ 6 时给出两者的规范代表元真值表
+}
+
+// EquivalenceHandler 是 POST /api/equivalence 的处理函数，判断两个函数是否（扩展）仿射等价.
+func EquivalenceHandler(c *gin.Context) {
+	var req EquivalenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f, err := newBooleanFunction(&req.F)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "f: " + err.Error()})
+		return
+	}
+	g, err := newBooleanFunction(&req.G)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "g: " + err.Error()})
+		return
+	}
+
+	// 客户端断开连接时请求上下文被取消，搜索随之停止
+	ctx := c.Request.Context()
+	opts := booleancore.EquivalenceOptions{Extended: req.Extended}
+	res, err := booleancore.AffineEquivalent(ctx, f, g, opts)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := EquivalenceResponse{N: f.N(), Equivalent: res.Equivalent, Decided: res.Decided, Reason: res.Reason}
+	if res.Map != nil {
+		resp.Map = newAffineMapResponse(res.Map)
+	}
+	if f.N() == g.N() && f.N() <= 6 {
+		for _, item := range []struct {
+			bf  *booleancore.BooleanFunction
+			out *[]int
+		}{{f, &resp.CanonicalF}, {g, &resp.CanonicalG}} {
+			canon, _, err := item.bf.AffineCanonicalForm(ctx, opts)
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			*item.out = bytesToInts(canon.TruthTable())
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// newAffineMapResponse 把核心库的变换转换为响应结构.
+func newAffineMapResponse(m *booleancore.AffineMap) *AffineMapResponse {
+	matrix := make([][]int, m.A.Rows())
+	for r := range matrix {
+		matrix[r] = make([]int, m.A.Cols())
+		for j := range matrix[r] {
+			matrix[r][j] = int(m.A.Get(r, j))
+		}
+	}
+	return &AffineMapResponse{Matrix: matrix, B: m.B, C: m.C, D: int(m.D)}
+}
+
+// bytesToInts 把真值表转换为 []int，避免 JSON 编码为 base64.
+func bytesToInts(tt []byte) []int {
+	out := make([]int, len(tt))
+	for i, v := range tt {
+		out[i] = int(v)
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hui-cyber/BoolCore/backend/pkg/booleancore"
+)
+
+// GenerateRequest 是 POST /api/generate 的请求：按 family 调用核心库中的构造，并像 /api/analyze 一样计算所需属性.
+type GenerateRequest struct {
+	// Family 取 mm、psap、tuDeng、rothaus、majority、carletFeng、resilientMM、tarannikov 之一
+	Family      string           `json:"family" binding:"required"`
+	N           int              `json:"n"`           // majority、carletFeng、resilientMM、tarannikov 的变量个数
+	M           int              `json:"m"`           // mm、psap、tuDeng 的半长 m（结果有 2m 个变量）；resilientMM、tarannikov 的弹性阶
+	Permutation []int            `json:"permutation"` // mm 的置换 π（2^m 项），为空时取恒等置换
+	G           []byte           `json:"g"`           // mm、psap 中 g 的真值表（2^m 项）；mm 为空时 g = 0，psap 为空时 g = Tr
+	Shift       int              `json:"shift"`       // tuDeng、carletFeng 中支撑的起始指数 s
+	Functions   []AnalyzeRequest `json:"functions"`   // rothaus 的三个 bent 函数 A、B、C，输入方式同 /api/analyze
+
+	// Properties 同 /api/analyze，为空时计算全部属性
+	Properties []string `json:"properties"`
+}
+
+// GenerateResponse 是 POST /api/generate 的响应：字段同 /api/analyze，
+// 对给出理论值的构造族（resilientMM、tarannikov）另附所用方法与理论值、实测值的对比。
+type GenerateResponse struct {
+	AnalyzeResponse
+	Method      string                      `json:"method,omitempty"`
+	Predictions []booleancore.PropertyCheck `json:"predictions,omitempty"`
+}
+
+// GenerateHandler 是 POST /api/generate 的处理函数，返回构造出的函数及其属性.
+func GenerateHandler(c *gin.Context) {
+	var req GenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var construction *booleancore.ResilientConstruction
+	var bf *booleancore.BooleanFunction
+	var err error
+	switch req.Family {
+	case "resilientMM", "tarannikov":
+		construction, err = generateResilient(&req)
+		if construction != nil {
+			bf = construction.Function
+		}
+	default:
+		bf, err = generateBooleanFunction(&req)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	wanted, err := booleancore.ExpandProperties(req.Properties)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	analysis, err := analyzeBooleanFunction(c.Request.Context(), bf, &AnalyzeRequest{Properties: req.Properties}, wanted)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	resp := GenerateResponse{AnalyzeResponse: analysis}
+	if construction != nil {
+		resp.Method = construction.Method
+		if resp.Predictions, err = construction.Verify(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// generateResilient 按目标 (n, m) 构造弹性函数，m 缺省为 0.
+func generateResilient(req *GenerateRequest) (*booleancore.ResilientConstruction, error) {
+	if req.N == 0 {
+		return nil, fmt.Errorf("parameter 'n' is required for family '%s'", req.Family)
+	}
+	if req.Family == "tarannikov" {
+		return booleancore.TarannikovResilient(req.N, req.M)
+	}
+	return booleancore.ResilientMM(req.N, req.M)
+}
+
+// generateBooleanFunction 根据请求的构造族与参数生成布尔函数，参数缺失或非法时返回错误.
+func generateBooleanFunction(req *GenerateRequest) (*booleancore.BooleanFunction, error) {
+	switch req.Family {
+	case "mm":
+		if req.M == 0 {
+			return nil, errors.New("parameter 'm' is required for family 'mm'")
+		}
+		perm := req.Permutation
+		if perm == nil && req.M > 0 && 2*req.M <= booleancore.MaxConstructedN {
+			perm = make([]int, 1<<req.M)
+			for i := range perm {
+				perm[i] = i
+			}
+		}
+		g, err := optionalSubfunction(req.G)
+		if err != nil {
+			return nil, err
+		}
+		return booleancore.MaioranaMcFarland(req.M, perm, g)
+	case "psap":
+		if req.M == 0 {
+			return nil, errors.New("parameter 'm' is required for family 'psap'")
+		}
+		g, err := optionalSubfunction(req.G)
+		if err != nil {
+			return nil, err
+		}
+		if g == nil {
+			if g, err = traceFunction(req.M); err != nil {
+				return nil, err
+			}
+		}
+		return booleancore.PartialSpreadAP(req.M, g)
+	case "tuDeng":
+		if req.M == 0 {
+			return nil, errors.New("parameter 'm' is required for family 'tuDeng'")
+		}
+		return booleancore.TuDeng(req.M, req.Shift)
+	case "rothaus":
+		if len(req.Functions) != 3 {
+			return nil, fmt.Errorf("family 'rothaus' requires exactly 3 functions, got %d", len(req.Functions))
+		}
+		var abc [3]*booleancore.BooleanFunction
+		for i := range abc {
+			bf, err := newBooleanFunction(&req.Functions[i])
+			if err != nil {
+				return nil, fmt.Errorf("functions[%d]: %w", i, err)
+			}
+			abc[i] = bf
+		}
+		return booleancore.Rothaus(abc[0], abc[1], abc[2])
+	case "majority":
+		if req.N == 0 {
+			return nil, errors.New("parameter 'n' is required for family 'majority'")
+		}
+		return booleancore.Majority(req.N)
+	case "carletFeng":
+		if req.N == 0 {
+			return nil, errors.New("parameter 'n' is required for family 'carletFeng'")
+		}
+		return booleancore.CarletFeng(req.N, req.Shift)
+	default:
+		return nil, errors.New("invalid 'family' specified, must be one of [mm, psap, tuDeng, rothaus, majority, carletFeng, resilientMM, tarannikov]")
+	}
+}
+
+// optionalSubfunction 把可选的 g 真值表转换为布尔函数，为空时返回 nil.
+func optionalSubfunction(tt []byte) (*booleancore.BooleanFunction, error) {
+	if tt == nil {
+		return nil, nil
+	}
+	g, err := booleancore.NewFromTruthTable(tt)
+	if err != nil {
+		return nil, fmt.Errorf("g: %w", err)
+	}
+	return g, nil
+}
+
+// traceFunction 返回 GF(2^m) 上的绝对迹函数 Tr(z)，是 PS_ap 构造中 g 的默认值.
+func traceFunction(m int) (*booleancore.BooleanFunction, error) {
+	if 2*m > booleancore.MaxConstructedN {
+		return nil, fmt.Errorf("m must be in [1, %d], got %d", booleancore.MaxConstructedN/2, m)
+	}
+	F, err := booleancore.NewGF2m(m)
+	if err != nil {
+		return nil, err
+	}
+	tt := make([]byte, F.Size())
+	for z := range tt {
+		tt[z] = F.Trace(z)
+	}
+	return booleancore.NewFromTruthTable(tt)
+}
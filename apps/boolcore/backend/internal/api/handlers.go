@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -20,37 +22,57 @@ type AnalyzeRequest struct {
 	HexValue      string `json:"hexValue"`
 	IntValue      uint64 `json:"intValue"`
 	ANFExpression string `json:"anfExpression"` // ANF 代数正规式表达式
+	Expression    string `json:"expression"`    // 布尔表达式，如 "(x0 and not x1) xor x2"
 	// TODO: 或者其他的输入方式
+
+	// Properties 指定需要计算的属性键或预设名（如 "spectral"、"algebraic"、"fast"），为空时计算全部属性
+	Properties []string `json:"properties"`
 }
 
 // AnalyzeResponse 定义了返回给前端的 JSON 结构.
+// 除 n 与 truthTable 外的字段都是指针并带 omitempty：未请求的属性不会出现在响应中，
+// 而已计算的零值（如非线性度 0、false）仍会正常返回。
 type AnalyzeResponse struct {
-	// “当转 JSON 时，这个字段用 algebraicDegree 作为键名，
-	// 这是因为加上了omitempty，如果是空，则不返回这个字段”
-	N                               int           `json:"n"`                               // n元布尔函数
-	TruthTable                      []int         `json:"truthTable"`                      // 将传入的[]byte修改为[]int，避免json转换为base64
-	HammingWeight                   int           `json:"hammingWeight"`                   // 汉明重量
-	IsBalanced                      bool          `json:"isBalanced"`                      // 平衡
-	WalshSpectrum                   []int64       `json:"walshSpectrum"`                   // 输出Walsh谱
-	ANF                             string        `json:"anf,omitempty"`                   // 代数标准型
-	AlgebraicDegree                 int           `json:"algebraicDegree,omitempty"`       // 代数次数
-	Nonlinearity                    int64         `json:"nonlinearity,omitempty"`          // 非线性度
-	AutocorrelationSpectrum         []int64       `json:"autocorrelationSpectrum"`         // 自相关谱
-	CorrelationImmunity             int           `json:"correlationImmunity"`             // 相关免疫度
-	ResiliencyOrder                 int           `json:"resiliencyOrder"`                 // 弹性阶数
-	TransparencyOrder               float64       `json:"transparencyOrder"`               // 透明度阶
-	IsBent                          bool          `json:"isBent"`                          // 是否 bent
-	SumOfSquareIndicator            int64         `json:"sumOfSquareIndicator"`            // 平方和指标
-	IsRotationSymmetric             bool          `json:"isRotationSymmetric"`             // 是否旋转对称
-	AbsoluteWalshSpectrum           map[int64]int `json:"absoluteWalshSpectrum"`           // 绝对walsh谱分布
-	AbsoluteAutocorrelationSpectrum map[int64]int `json:"absoluteAutocorrelationSpectrum"` // 绝对自相关谱分布
-	AbsoluteIndicator               int64         `json:"absoluteIndicator"`               // 绝对指标
-	DifferentialUniformity          int64         `json:"differentialUniformity"`          // 差分均匀度
-	AlgebraicImmunity               int           `json:"algebraicImmunity"`               // 代数免疫度
-	FAA                             int           `json:"faa"`                             // 抵抗快速代数攻击能力（早期定义）
-	FAAWithPositiveDegree           int           `json:"faaWithPositiveDegree"`           // 抵抗快速代数攻击能力（限制 1<=deg(g)<n/2）
-	FAI                             int           `json:"fai"`                             // 快速代数免疫（标准定义）
-	Annihilator                     string        `json:"annihilator,omitempty"`           // 零化因子ANF表达式
+	N                               int           `json:"n"`                                         // n元布尔函数
+	TruthTable                      []int         `json:"truthTable"`                                // 将传入的[]byte修改为[]int，避免json转换为base64
+	HammingWeight                   *int          `json:"hammingWeight,omitempty"`                   // 汉明重量
+	IsBalanced                      *bool         `json:"isBalanced,omitempty"`                      // 平衡
+	WalshSpectrum                   []int64       `json:"walshSpectrum,omitempty"`                   // 输出Walsh谱
+	ANF                             *string       `json:"anf,omitempty"`                             // 代数标准型
+	AlgebraicDegree                 *int          `json:"algebraicDegree,omitempty"`                 // 代数次数
+	Nonlinearity                    *int64        `json:"nonlinearity,omitempty"`                    // 非线性度
+	AutocorrelationSpectrum         []int64       `json:"autocorrelationSpectrum,omitempty"`         // 自相关谱
+	CorrelationImmunity             *int          `json:"correlationImmunity,omitempty"`             // 相关免疫度
+	ResiliencyOrder                 *int          `json:"resiliencyOrder,omitempty"`                 // 弹性阶数
+	TransparencyOrder               *float64      `json:"transparencyOrder,omitempty"`               // 透明度阶
+	IsBent                          *bool         `json:"isBent,omitempty"`                          // 是否 bent
+	SumOfSquareIndicator            *int64        `json:"sumOfSquareIndicator,omitempty"`            // 平方和指标
+	IsRotationSymmetric             *bool         `json:"isRotationSymmetric,omitempty"`             // 是否旋转对称
+	IsDihedralSymmetric             *bool         `json:"isDihedralSymmetric,omitempty"`             // 是否二面体对称
+	AbsoluteWalshSpectrum           map[int64]int `json:"absoluteWalshSpectrum,omitempty"`           // 绝对walsh谱分布
+	AbsoluteAutocorrelationSpectrum map[int64]int `json:"absoluteAutocorrelationSpectrum,omitempty"` // 绝对自相关谱分布
+	AbsoluteIndicator               *int64        `json:"absoluteIndicator,omitempty"`               // 绝对指标
+	DifferentialUniformity          *int64        `json:"differentialUniformity,omitempty"`          // 差分均匀度
+	AlgebraicImmunity               *int          `json:"algebraicImmunity,omitempty"`               // 代数免疫度
+	FAA                             *int          `json:"faa,omitempty"`                             // 抵抗快速代数攻击能力（早期定义）
+	FAAWithPositiveDegree           *int          `json:"faaWithPositiveDegree,omitempty"`           // 抵抗快速代数攻击能力（限制 1<=deg(g)<n/2）
+	FAI                             *int          `json:"fai,omitempty"`                             // 快速代数免疫（标准定义）
+	Annihilator                     string        `json:"annihilator,omitempty"`                     // 零化因子ANF表达式
+
+	// 扩散与雪崩类判据
+	PropagationCriterion *int                          `json:"propagationCriterion,omitempty"` // 满足 PC(l) 的最大 l
+	IsSAC                *bool                         `json:"isSAC,omitempty"`                // 是否满足严格雪崩准则
+	AvalancheProfile     []booleancore.AvalancheWeight `json:"avalancheProfile,omitempty"`     // 按翻转位数汇总的雪崩表
+
+	// Walsh 谱形状分类
+	IsPlateaued    *bool                      `json:"isPlateaued,omitempty"`    // 非零 Walsh 值的绝对值是否都相同
+	PlateauedOrder *int                       `json:"plateauedOrder,omitempty"` // s-plateaued 的阶 s，不是 plateaued 函数时省略
+	IsThreeValued  *bool                      `json:"isThreeValued,omitempty"`  // Walsh 谱是否恰好取三个值
+	WalshSupport   []int                      `json:"walshSupport,omitempty"`   // W(ω) ≠ 0 的 ω
+	SpectralClass  *booleancore.SpectralClass `json:"spectralClass,omitempty"`  // affine/bent/semi-bent/near-bent/plateaued/general
+
+	Errors     map[string]string      `json:"errors,omitempty"`     // 计算失败的属性键及原因
+	Extensions map[string]interface{} `json:"extensions,omitempty"` // 通过 Analyzer.Register 注册的自定义属性
 	// TODO: 添加更多字段
 }
 
@@ -62,146 +84,225 @@ func AnalyzeFunctionHandler(c *gin.Context) {
 		return
 	}
 
-	var bf *booleancore.BooleanFunction
-	var err error
+	// 1. 使用核心库创建一个布尔函数实例
+	bf, err := newBooleanFunction(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 2. 确定需要计算的属性（展开预设名），未知属性名直接返回 400
+	wanted, err := booleancore.ExpandProperties(req.Properties)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// 1. 使用核心库创建一个布尔函数实例，由于可以输入不同的类型，输出真值表啊，十六进制啊，整数啊，所以这里根据不同的type 进行不同的处理
+	// 3. 同步计算，客户端断开连接时请求上下文被取消，计算随之停止
+	resp, err := analyzeBooleanFunction(c.Request.Context(), bf, &req, wanted)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// newBooleanFunction 根据请求的输入类型构造布尔函数，参数缺失或非法时返回错误.
+func newBooleanFunction(req *AnalyzeRequest) (*booleancore.BooleanFunction, error) {
+	// 由于可以输入不同的类型，输出真值表啊，十六进制啊，整数啊，所以这里根据不同的type 进行不同的处理
 	switch req.Type {
 	case "truthTable":
 		if req.TruthTable == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'truthTable' is required for type 'truthTable'"})
-			return
+			return nil, errors.New("parameter 'truthTable' is required for type 'truthTable'")
 		}
-		bf, err = booleancore.NewFromTruthTable(req.TruthTable)
+		return booleancore.NewFromTruthTable(req.TruthTable)
 	case "hex":
 		if req.N == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'n' is required for type 'hex'"})
-			return
+			return nil, errors.New("parameter 'n' is required for type 'hex'")
 		}
 		if req.HexValue == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'hexValue' is required for type 'hex'"})
-			return
+			return nil, errors.New("parameter 'hexValue' is required for type 'hex'")
 		}
-		bf, err = booleancore.NewFromHex(req.HexValue, req.N)
+		return booleancore.NewFromHex(req.HexValue, req.N)
 	case "int":
 		if req.N == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'n' is required for type 'int'"})
-			return
+			return nil, errors.New("parameter 'n' is required for type 'int'")
 		}
 		// 对于int整数类型，intValue 为 0 是一个有效值, 所以我们只检查 n
-		bf, err = booleancore.NewFromInt(req.IntValue, req.N)
+		return booleancore.NewFromInt(req.IntValue, req.N)
 	case "anf":
 		if req.N == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'n' is required for type 'anf'"})
-			return
+			return nil, errors.New("parameter 'n' is required for type 'anf'")
 		}
 		if req.ANFExpression == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "parameter 'anfExpression' is required for type 'anf'"})
-			return
+			return nil, errors.New("parameter 'anfExpression' is required for type 'anf'")
+		}
+		return booleancore.NewFromANF(req.N, req.ANFExpression)
+	case "expr":
+		if req.N == 0 {
+			return nil, errors.New("parameter 'n' is required for type 'expr'")
+		}
+		if req.Expression == "" {
+			return nil, errors.New("parameter 'expression' is required for type 'expr'")
 		}
-		bf, err = booleancore.NewFromANF(req.N, req.ANFExpression)
+		return booleancore.NewFromExpr(req.N, req.Expression)
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'type' specified, must be one of [truthTable, hex, int, anf]"})
-		return
-	}
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return nil, errors.New("invalid 'type' specified, must be one of [truthTable, hex, int, anf, expr]")
 	}
+}
 
-	// 2. 调用核心库进行性性质分析
-	// 【解决 Base64 问题】将 []byte 转换为 []int
-	originalTT := bf.TruthTable()
-	ttAsInt := make([]int, len(originalTT))
-	for i, v := range originalTT {
-		ttAsInt[i] = int(v)
+// analyzeBooleanFunction 计算 wanted 中的属性并组装响应，同步接口与异步任务共用.
+// ctx 被取消时返回 ctx.Err()。
+func analyzeBooleanFunction(ctx context.Context, bf *booleancore.BooleanFunction, req *AnalyzeRequest, wanted []string) (AnalyzeResponse, error) {
+	wantedSet := make(map[string]bool, len(wanted))
+	for _, key := range wanted {
+		wantedSet[key] = true
 	}
 
-	// 3. 调用计算分析函数返回结果
-	// 优化：如果输入是ANF，直接使用原始ANF，避免重复计算
-	var anfString string
-	var algebraicDegree int
-
+	// 优化：如果输入是ANF，直接使用原始ANF和从字符串得到的次数，核心库无需再计算这两项
+	coreKeys := wanted
 	if req.Type == "anf" {
-		anfString = req.ANFExpression // 直接使用输入的ANF
-		// 直接从输入的ANF字符串计算代数次数，避免重复计算ANF系数
-		algebraicDegree = calculateDegreeFromANF(req.ANFExpression)
-	} else {
-		anfString = bf.AlgebraicNormalForm()   // 其他输入类型需要计算ANF
-		algebraicDegree = bf.AlgebraicDegree() // 正常计算代数次数
-	}
-
-	// 计算代数免疫度（快速版本，不计算零化因子）
-	var algebraicImmunity int
-	ai, _, err := bf.AlgebraicImmunity(false) // false = 只计算代数免疫度值，不求解零化因子
-	if err != nil {
-		algebraicImmunity = -1
-	} else {
-		algebraicImmunity = ai
-	}
-
-	faa, err := bf.FAA()
-	if err != nil {
-		faa = -1
+		coreKeys = make([]string, 0, len(wanted))
+		for _, key := range wanted {
+			if key != booleancore.PropANF && key != booleancore.PropAlgebraicDegree {
+				coreKeys = append(coreKeys, key)
+			}
+		}
 	}
 
-	faaPositive, err := bf.FAAWithPositiveDegree()
-	if err != nil {
-		faaPositive = -1
+	// 调用核心库只计算所需属性及其依赖（代数免疫度使用快速版本，不计算零化因子）
+	res := booleancore.AnalyzeResult{N: bf.N(), TruthTable: bf.TruthTable()}
+	if len(coreKeys) > 0 {
+		var err error
+		res, err = booleancore.AnalyzeSelectedCtx(ctx, bf, coreKeys)
+		if err != nil {
+			return AnalyzeResponse{}, err
+		}
 	}
-
-	fai, err := bf.FAI()
-	if err != nil {
-		fai = -1
+	if req.Type == "anf" {
+		res.ANF = req.ANFExpression                                     // 直接使用输入的ANF
+		res.AlgebraicDegree = calculateDegreeFromANF(req.ANFExpression) // 直接从ANF字符串计算次数
+		if res.Computed == nil {
+			res.Computed = make(map[string]bool, 2)
+		}
+		res.Computed[booleancore.PropANF] = true
+		res.Computed[booleancore.PropAlgebraicDegree] = true
 	}
 
 	// 【可选功能 - 已注释】计算零化因子表达式（比较耗时）
-	// 如果需要零化因子，取消下面的注释并注释掉上面的快速版本
+	// 如果需要零化因子，可以单独调用 bf.AlgebraicImmunity(true) 并填入 Annihilator 字段
 	/*
-		var algebraicImmunity int
-		var annihilator string
 		findAnnihilator := bf.N() <= 8 // 可根据需要调整阈值
-		ai, ann, err := bf.AlgebraicImmunity(findAnnihilator)
-		if err != nil {
-			algebraicImmunity = -1
-			annihilator = "calculation error: " + err.Error()
-		} else {
-			algebraicImmunity = ai
-			annihilator = ann
+		if _, ann, err := bf.AlgebraicImmunity(findAnnihilator); err == nil {
+			resp.Annihilator = ann
 		}
 	*/
 
-	resp := AnalyzeResponse{
-		N:          bf.N(),
-		TruthTable: ttAsInt,
-
-		HammingWeight:   bf.HammingWeight(),
-		IsBalanced:      bf.IsBalanced(),
-		ANF:             anfString,       // 这进行个预处理，如果输入就是ANF就直接用
-		AlgebraicDegree: algebraicDegree, // 直接从ANF字符串计算次数
-
-		AutocorrelationSpectrum:         bf.Autocorrelation(),
-		WalshSpectrum:                   bf.WalshHadamardTransform(),
-		AbsoluteWalshSpectrum:           bf.AbsoluteWalshSpectrum(),
-		AbsoluteAutocorrelationSpectrum: bf.AbsoluteAutocorrelation(),
-
-		Nonlinearity:           bf.Nonlinearity(),
-		CorrelationImmunity:    bf.CorrelationImmunity(),
-		TransparencyOrder:      bf.TransparencyOrder(),
-		ResiliencyOrder:        bf.ResiliencyOrder(),
-		IsBent:                 bf.IsBent(),
-		SumOfSquareIndicator:   bf.SumOfSquareIndicator(),
-		IsRotationSymmetric:    bf.IsRotationSymmetric(),
-		AbsoluteIndicator:      bf.AbsoluteIndicator(),
-		DifferentialUniformity: bf.DifferentialUniformity(),
-		AlgebraicImmunity:      algebraicImmunity, // 使用预计算的值
-		FAA:                    faa,
-		FAAWithPositiveDegree:  faaPositive,
-		FAI:                    fai,
-		// Annihilator:                  annihilator,       // 【已禁用】如需启用，取消注释并启用上面的完整计算版本
+	return buildAnalyzeResponse(res, wantedSet), nil
+}
+
+// buildAnalyzeResponse 把核心库的结果转换为响应结构，只填充 wanted 中计算成功的属性，
+// 计算失败的属性不出现在对应字段中，原因放在 errors 里.
+func buildAnalyzeResponse(res booleancore.AnalyzeResult, wanted map[string]bool) AnalyzeResponse {
+	// 【解决 Base64 问题】将 []byte 转换为 []int
+	ttAsInt := make([]int, len(res.TruthTable))
+	for i, v := range res.TruthTable {
+		ttAsInt[i] = int(v)
 	}
 
-	c.JSON(http.StatusOK, resp)
+	resp := AnalyzeResponse{N: res.N, TruthTable: ttAsInt}
+	for key := range wanted {
+		if !res.Computed[key] {
+			continue
+		}
+		switch key {
+		case booleancore.PropHammingWeight:
+			resp.HammingWeight = ptr(res.HammingWeight)
+		case booleancore.PropIsBalanced:
+			resp.IsBalanced = ptr(res.IsBalanced)
+		case booleancore.PropANF:
+			resp.ANF = ptr(res.ANF)
+		case booleancore.PropAlgebraicDegree:
+			resp.AlgebraicDegree = ptr(res.AlgebraicDegree)
+		case booleancore.PropWalshSpectrum:
+			resp.WalshSpectrum = res.WalshSpectrum
+		case booleancore.PropAutocorrelationSpectrum:
+			resp.AutocorrelationSpectrum = res.AutocorrelationSpectrum
+		case booleancore.PropTransparencyOrder:
+			resp.TransparencyOrder = ptr(res.TransparencyOrder)
+		case booleancore.PropNonlinearity:
+			resp.Nonlinearity = ptr(res.Nonlinearity)
+		case booleancore.PropCorrelationImmunity:
+			resp.CorrelationImmunity = ptr(res.CorrelationImmunity)
+		case booleancore.PropResiliencyOrder:
+			resp.ResiliencyOrder = ptr(res.ResiliencyOrder)
+		case booleancore.PropIsBent:
+			resp.IsBent = ptr(res.IsBent)
+		case booleancore.PropSumOfSquareIndicator:
+			resp.SumOfSquareIndicator = ptr(res.SumOfSquareIndicator)
+		case booleancore.PropIsRotationSymmetric:
+			resp.IsRotationSymmetric = ptr(res.IsRotationSymmetric)
+		case booleancore.PropIsDihedralSymmetric:
+			resp.IsDihedralSymmetric = ptr(res.IsDihedralSymmetric)
+		case booleancore.PropAbsoluteWalshSpectrum:
+			resp.AbsoluteWalshSpectrum = res.AbsoluteWalshSpectrum
+		case booleancore.PropAbsoluteAutocorrelationSpectrum:
+			resp.AbsoluteAutocorrelationSpectrum = res.AbsoluteAutocorrelationSpectrum
+		case booleancore.PropAbsoluteIndicator:
+			resp.AbsoluteIndicator = ptr(res.AbsoluteIndicator)
+		case booleancore.PropDifferentialUniformity:
+			resp.DifferentialUniformity = ptr(res.DifferentialUniformity)
+		case booleancore.PropPropagationCriterion:
+			resp.PropagationCriterion = ptr(res.PropagationCriterion)
+		case booleancore.PropIsSAC:
+			resp.IsSAC = ptr(res.IsSAC)
+		case booleancore.PropAvalancheProfile:
+			resp.AvalancheProfile = res.AvalancheProfile
+		case booleancore.PropIsPlateaued:
+			resp.IsPlateaued = ptr(res.IsPlateaued)
+		case booleancore.PropPlateauedOrder:
+			if res.IsPlateaued {
+				resp.PlateauedOrder = ptr(res.PlateauedOrder)
+			}
+		case booleancore.PropIsThreeValued:
+			resp.IsThreeValued = ptr(res.IsThreeValued)
+		case booleancore.PropWalshSupport:
+			resp.WalshSupport = res.WalshSupport
+		case booleancore.PropSpectralClass:
+			resp.SpectralClass = ptr(res.SpectralClass)
+		case booleancore.PropAlgebraicImmunity:
+			resp.AlgebraicImmunity = ptr(res.AlgebraicImmunity)
+		case booleancore.PropFAA:
+			resp.FAA = ptr(res.FAA)
+		case booleancore.PropFAAWithPositiveDegree:
+			resp.FAAWithPositiveDegree = ptr(res.FAAWithPositiveDegree)
+		case booleancore.PropFAI:
+			resp.FAI = ptr(res.FAI)
+		default:
+			if v, ok := res.Extensions[key]; ok {
+				if resp.Extensions == nil {
+					resp.Extensions = make(map[string]interface{})
+				}
+				resp.Extensions[key] = v
+			}
+		}
+	}
+	for key, err := range res.Errors {
+		if !wanted[key] {
+			continue // 未请求的依赖失败时，原因已体现在依赖它的属性上
+		}
+		if resp.Errors == nil {
+			resp.Errors = make(map[string]string)
+		}
+		resp.Errors[key] = err.Error()
+	}
+	return resp
+}
+
+// ptr 返回值的指针，用于填充响应中的可选字段
+func ptr[T any](v T) *T {
+	return &v
 }
 
 // calculateDegreeFromANF 直接从ANF字符串计算代数次数，避免重新解析
@@ -247,6 +348,22 @@ func calculateDegreeFromANF(anfString string) int {
 	return maxDegree
 }
 
+// PropertiesResponse 是 GET /api/properties 的响应，前端据此动态渲染属性.
+type PropertiesResponse struct {
+	Properties []booleancore.PropertyInfo `json:"properties"` // 按计算顺序排列
+	Categories []booleancore.CategoryInfo `json:"categories"` // 按展示顺序排列
+	Presets    map[string][]string        `json:"presets"`    // 可在 properties 请求参数中使用的预设名
+}
+
+// PropertiesHandler 是 GET /api/properties 的处理函数，返回属性注册表中的元数据.
+func PropertiesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, PropertiesResponse{
+		Properties: booleancore.Properties(),
+		Categories: booleancore.PropertyCategories(),
+		Presets:    booleancore.Presets(),
+	})
+}
+
 // PingHandler 是 /api/ping 的处理器
 func PingHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "pong from BoolCore backend单独!"}) //这个StatusOK 是一个常量，表示 HTTP 状态码 200
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hui-cyber/BoolCore/backend/pkg/booleancore"
+)
+
+// 异步分析任务：大 n 的分析（代数免疫度、FAA/FAI 等）可能持续数分钟，
+// 通过 POST /api/jobs 提交后立即返回任务 ID，由有界的 worker 池在后台执行，
+// 客户端轮询 GET /api/jobs/:id 获取状态与结果，DELETE /api/jobs/:id 取消任务。
+// 取消会通过 context 传递到核心库，真正停止正在进行的计算。
+// GET /api/jobs/:id/events 以 server-sent events 推送任务的状态变化与计算进度。
+// 启发式搜索（POST /api/search，见 search.go）同样作为任务提交，共用查询、取消与事件接口。
+
+// JobStatus 表示任务状态.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+const (
+	defaultJobQueueSize   = 64  // 排队任务上限，超过后提交返回 503
+	defaultMaxRetainedJob = 256 // 保留的任务记录上限，超过后淘汰最早结束的任务
+	jobEventBuffer        = 64  // 每个订阅者的事件缓冲，消费过慢时丢弃中间的进度事件
+)
+
+// SSE 事件名.
+const (
+	jobEventStatus   = "status"   // 数据为 JobView
+	jobEventProgress = "progress" // 数据为 booleancore.ProgressEvent
+)
+
+// errJobQueueFull 表示任务队列已满.
+var errJobQueueFull = errors.New("job queue is full, try again later")
+
+// errJobManagerClosed 表示任务管理器已关闭.
+var errJobManagerClosed = errors.New("job manager is closed")
+
+// JobView 是任务对外展示的 JSON 结构.
+type JobView struct {
+	ID         string      `json:"id"`
+	Status     JobStatus   `json:"status"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	StartedAt  *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Result     interface{} `json:"result,omitempty"` // 分析任务为 *AnalyzeResponse，搜索任务为 *SearchResponse
+
+	Progress *booleancore.ProgressEvent `json:"progress,omitempty"` // 最近一次进度事件，仅运行中的任务
+}
+
+// jobEvent 是推送给订阅者的一条事件.
+type jobEvent struct {
+	name string
+	data interface{}
+}
+
+// job 是任务的内部状态，所有字段由 JobManager.mu 保护.
+type job struct {
+	view   JobView
+	run    func(ctx context.Context) (interface{}, error)
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	subscribers map[chan jobEvent]struct{}
+}
+
+// JobManager 管理异步任务及其 worker 池.
+type JobManager struct {
+	mu          sync.Mutex
+	jobs        map[string]*job
+	order       []string // 按提交顺序记录任务 ID，用于淘汰旧记录
+	queue       chan *job
+	maxRetained int
+	closed      bool
+	workers     sync.WaitGroup
+}
+
+// NewJobManager 创建任务管理器并启动 workers 个后台 worker.
+func NewJobManager(workers, queueSize int) *JobManager {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = defaultJobQueueSize
+	}
+	m := &JobManager{
+		jobs:        make(map[string]*job),
+		queue:       make(chan *job, queueSize),
+		maxRetained: defaultMaxRetainedJob,
+	}
+	m.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go m.worker()
+	}
+	return m
+}
+
+// Close 取消全部未结束的任务并停止 worker，返回时所有 worker 均已退出；之后的提交返回错误.
+func (m *JobManager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	for _, j := range m.jobs {
+		m.cancelLocked(j)
+	}
+	close(m.queue)
+	m.mu.Unlock()
+	m.workers.Wait()
+}
+
+// Submit 提交一个任务，队列已满时返回 errJobQueueFull，已关闭时返回 errJobManagerClosed.
+func (m *JobManager) Submit(run func(ctx context.Context) (interface{}, error)) (JobView, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		view:   JobView{ID: newJobID(), Status: JobQueued, CreatedAt: time.Now()},
+		run:    run,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		cancel()
+		return JobView{}, errJobManagerClosed
+	}
+	select {
+	case m.queue <- j:
+	default:
+		cancel()
+		return JobView{}, errJobQueueFull
+	}
+	m.jobs[j.view.ID] = j
+	m.order = append(m.order, j.view.ID)
+	m.evictLocked()
+	return j.view, nil
+}
+
+// Get 返回任务当前状态.
+func (m *JobManager) Get(id string) (JobView, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobView{}, false
+	}
+	return j.view, true
+}
+
+// Cancel 取消任务：排队中的任务直接标记为已取消，运行中的任务通过 context 通知核心库停止.
+func (m *JobManager) Cancel(id string) (JobView, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobView{}, false
+	}
+	m.cancelLocked(j)
+	return j.view, true
+}
+
+func (m *JobManager) cancelLocked(j *job) {
+	if j.view.Status == JobQueued {
+		now := time.Now()
+		j.view.Status = JobCancelled
+		j.view.FinishedAt = &now
+		m.finishLocked(j)
+	}
+	j.cancel()
+}
+
+func (m *JobManager) worker() {
+	defer m.workers.Done()
+	for j := range m.queue {
+		m.mu.Lock()
+		if j.view.Status != JobQueued { // 排队期间已被取消
+			m.mu.Unlock()
+			continue
+		}
+		now := time.Now()
+		j.view.Status = JobRunning
+		j.view.StartedAt = &now
+		m.publishLocked(j, jobEvent{name: jobEventStatus, data: j.view})
+		m.mu.Unlock()
+
+		ctx := booleancore.WithProgress(j.ctx, func(ev booleancore.ProgressEvent) {
+			m.mu.Lock()
+			j.view.Progress = &ev
+			m.publishLocked(j, jobEvent{name: jobEventProgress, data: ev})
+			m.mu.Unlock()
+		})
+		result, err := j.run(ctx)
+
+		m.mu.Lock()
+		finished := time.Now()
+		j.view.FinishedAt = &finished
+		j.view.Progress = nil
+		switch {
+		case err == nil:
+			j.view.Status = JobSucceeded
+			j.view.Result = result
+		case errors.Is(err, context.Canceled):
+			j.view.Status = JobCancelled
+		default:
+			j.view.Status = JobFailed
+			j.view.Error = err.Error()
+		}
+		m.finishLocked(j)
+		m.mu.Unlock()
+		j.cancel() // 释放 context 资源
+	}
+}
+
+// Subscribe 订阅任务事件，返回当前状态与事件通道.
+// 任务结束时通道在推送最终状态后关闭；若任务已结束，返回的通道已关闭.
+// 调用方不再读取时必须调用 unsubscribe.
+func (m *JobManager) Subscribe(id string) (view JobView, events <-chan jobEvent, unsubscribe func(), ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobView{}, nil, nil, false
+	}
+	ch := make(chan jobEvent, jobEventBuffer)
+	if isTerminal(j.view.Status) {
+		close(ch)
+		return j.view, ch, func() {}, true
+	}
+	if j.subscribers == nil {
+		j.subscribers = make(map[chan jobEvent]struct{})
+	}
+	j.subscribers[ch] = struct{}{}
+	unsubscribe = func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := j.subscribers[ch]; ok {
+			delete(j.subscribers, ch)
+			close(ch)
+		}
+	}
+	return j.view, ch, unsubscribe, true
+}
+
+// publishLocked 向任务的所有订阅者推送事件，缓冲已满的订阅者会丢失该事件.
+func (m *JobManager) publishLocked(j *job, ev jobEvent) {
+	for ch := range j.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// finishLocked 推送最终状态并关闭所有订阅者的通道.
+func (m *JobManager) finishLocked(j *job) {
+	for ch := range j.subscribers {
+		// 最终状态不能丢失：缓冲已满时先丢弃旧事件再重试
+		final := jobEvent{name: jobEventStatus, data: j.view}
+		for sent := false; !sent; {
+			select {
+			case ch <- final:
+				sent = true
+			default:
+				select {
+				case <-ch:
+				default:
+				}
+			}
+		}
+		close(ch)
+	}
+	j.subscribers = nil
+}
+
+func isTerminal(status JobStatus) bool {
+	return status == JobSucceeded || status == JobFailed || status == JobCancelled
+}
+
+// evictLocked 在任务记录过多时淘汰最早提交且已结束的任务.
+func (m *JobManager) evictLocked() {
+	for len(m.jobs) > m.maxRetained {
+		evicted := false
+		for i, id := range m.order {
+			j := m.jobs[id]
+			if !isTerminal(j.view.Status) {
+				continue
+			}
+			delete(m.jobs, id)
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			evicted = true
+			break
+		}
+		if !evicted {
+			return // 全部是未结束的任务，暂不淘汰
+		}
+	}
+}
+
+func newJobID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// --- HTTP handlers ---
+
+// CreateJobHandler 是 POST /api/jobs 的处理函数，请求体与 /api/analyze 相同.
+func (m *JobManager) CreateJobHandler(c *gin.Context) {
+	var req AnalyzeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// 输入与属性列表在提交时校验，避免无效任务占用队列
+	bf, err := newBooleanFunction(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	wanted, err := booleancore.ExpandProperties(req.Properties)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+		resp, err := analyzeBooleanFunction(ctx, bf, &req, wanted)
+		if err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, view)
+}
+
+// GetJobHandler 是 GET /api/jobs/:id 的处理函数.
+func (m *JobManager) GetJobHandler(c *gin.Context) {
+	view, ok := m.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// CancelJobHandler 是 DELETE /api/jobs/:id 的处理函数.
+func (m *JobManager) CancelJobHandler(c *gin.Context) {
+	view, ok := m.Cancel(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// JobEventsHandler 是 GET /api/jobs/:id/events 的处理函数.
+// 连接建立后先推送一次当前状态，之后推送 progress 与 status 事件，任务结束或客户端断开时返回.
+func (m *JobManager) JobEventsHandler(c *gin.Context) {
+	view, events, unsubscribe, ok := m.Subscribe(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.SSEvent(jobEventStatus, view)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, open := <-events:
+			if !open {
+				return
+			}
+			c.SSEvent(ev.name, ev.data)
+			c.Writer.Flush()
+		}
+	}
+}
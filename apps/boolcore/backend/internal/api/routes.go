@@ -4,13 +4,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// 路由注册
-func RegisterRoutes(router *gin.Engine) {
+// 路由注册，异步任务由 jobs 执行，其生命周期由调用方管理（退出前调用 jobs.Close）
+func RegisterRoutes(router *gin.Engine, jobs *JobManager) {
 	api := router.Group("/api")
 	{
 		// 用于健康检查的 ping 接口
 		api.GET("/ping", PingHandler) // 我们需要定义一个 PingHandler
 		// 用于分析布尔函数性质的接口
 		api.POST("/analyze", AnalyzeFunctionHandler)
+		// 可计算属性的元数据（显示名、分类、代价、依赖与预设）
+		api.GET("/properties", PropertiesHandler)
+		// 两个函数的（扩展）仿射等价判定
+		api.POST("/equivalence", EquivalenceHandler)
+		// 按经典构造族（MM、PS_ap、Tu–Deng、Rothaus、择多、Carlet–Feng）生成函数并分析
+		api.POST("/generate", GenerateHandler)
+		// 异步分析任务：提交、查询、取消
+		api.POST("/jobs", jobs.CreateJobHandler)
+		api.GET("/jobs/:id", jobs.GetJobHandler)
+		api.GET("/jobs/:id/events", jobs.JobEventsHandler)
+		api.DELETE("/jobs/:id", jobs.CancelJobHandler)
+		// 启发式搜索（爬山、模拟退火、遗传算法），作为异步任务执行
+		api.POST("/search", jobs.SearchHandler)
 	}
 }
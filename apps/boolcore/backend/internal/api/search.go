@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hui-cyber/BoolCore/backend/pkg/booleancore"
+)
+
+// 启发式搜索任务：POST /api/search 校验参数后提交异步任务并返回 202 与任务信息，
+// 之后与分析任务一样通过 /api/jobs/:id 查询、取消或订阅进度（stage 为 search）。
+
+// SearchRequest 是 POST /api/search 的请求：搜索参数同 booleancore.SearchOptions，
+// 如 {"n": 9, "algorithm": "annealing", "seed": 1, "balanced": true, "cost": {"nonlinearity": 1, "absoluteIndicator": 0.25}}.
+type SearchRequest struct {
+	booleancore.SearchOptions
+
+	// Initial 是可选的初始函数，输入方式同 /api/analyze
+	Initial *AnalyzeRequest `json:"initial"`
+	// Properties 是对找到的函数计算的属性，同 /api/analyze，为空时计算全部属性
+	Properties []string `json:"properties"`
+}
+
+// SearchResponse 是搜索任务的结果：找到的函数的属性同 /api/analyze，search 字段是搜索本身的统计.
+type SearchResponse struct {
+	AnalyzeResponse
+	Search *booleancore.SearchResult `json:"search"`
+}
+
+// SearchHandler 是 POST /api/search 的处理函数.
+func (m *JobManager) SearchHandler(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts := req.SearchOptions
+	if req.Initial != nil {
+		bf, err := newBooleanFunction(req.Initial)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("initial: %v", err)})
+			return
+		}
+		opts.Initial = bf
+	}
+	// 参数与属性列表在提交时校验，避免无效任务占用队列
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	wanted, err := booleancore.ExpandProperties(req.Properties)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := m.Submit(func(ctx context.Context) (interface{}, error) {
+		result, err := booleancore.Search(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		analysis, err := analyzeBooleanFunction(ctx, result.Function, &AnalyzeRequest{Properties: req.Properties}, wanted)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResponse{AnalyzeResponse: analysis, Search: result}, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, view)
+}
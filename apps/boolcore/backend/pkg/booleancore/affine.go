@@ -0,0 +1,172 @@
+package booleancore
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// 变量的仿射变换 g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d，A 为 GF(2) 上的 n×n 可逆矩阵.
+// 向量与掩码一律用整数表示，第 i 位对应 x_i（与真值表索引规则一致），矩阵用 BitMatrix 存储，
+// 第 r 行第 j 列为 1 表示 (Ax)_r 含有 x_j。
+// 这类变换保持代数次数、非线性度、绝对 Walsh/自相关谱分布、代数免疫度等仿射不变量，
+// 常用于检查某个性质是否仿射不变，或生成等价类中的代表元。
+
+// IdentityMatrix 返回 n×n 单位矩阵.
+func IdentityMatrix(n int) *BitMatrix {
+	m := NewBitMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// PermutationMatrix 返回变量置换 perm 对应的矩阵 P，满足 (Px)_i = x_{perm[i]}.
+func PermutationMatrix(perm []int) (*BitMatrix, error) {
+	if err := validatePermutation(perm); err != nil {
+		return nil, err
+	}
+	m := NewBitMatrix(len(perm), len(perm))
+	for i, p := range perm {
+		m.Set(i, p, 1)
+	}
+	return m, nil
+}
+
+// ParsePermutation 解析形如 "(6,5,1,4,7,2,3,0,8)" 的置换（从 0 开始编号，括号与空白可省略）.
+func ParsePermutation(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	perm := make([]int, len(fields))
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permutation entry %q", field)
+		}
+		perm[i] = v
+	}
+	if err := validatePermutation(perm); err != nil {
+		return nil, err
+	}
+	return perm, nil
+}
+
+// InversePermutation 返回 perm 的逆置换.
+func InversePermutation(perm []int) ([]int, error) {
+	if err := validatePermutation(perm); err != nil {
+		return nil, err
+	}
+	inv := make([]int, len(perm))
+	for i, p := range perm {
+		inv[p] = i
+	}
+	return inv, nil
+}
+
+// Rows 返回矩阵的行数.
+func (m *BitMatrix) Rows() int { return m.rows }
+
+// Cols 返回矩阵的列数.
+func (m *BitMatrix) Cols() int { return m.cols }
+
+// IsInvertible 判断方阵在 GF(2) 上是否可逆.
+func (m *BitMatrix) IsInvertible() bool {
+	return m.rows == m.cols && computeRREF_GF2(m.Clone()) == m.rows
+}
+
+// MulVec 计算矩阵与向量 x 的乘积 Ax（列数不超过 64）.
+func (m *BitMatrix) MulVec(x int) int {
+	y := 0
+	for r := 0; r < m.rows; r++ {
+		y |= (bits.OnesCount64(m.data[r*m.wordsPerRow]&uint64(x)) & 1) << uint(r)
+	}
+	return y
+}
+
+// AffineTransform 返回 g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d.
+// A 必须是 n×n 可逆矩阵，b、c 是 [0, 2^n) 内的向量，d 为 0 或 1；复杂度 O(2^n)。
+func (f *BooleanFunction) AffineTransform(a *BitMatrix, b, c int, d byte) (*BooleanFunction, error) {
+	if a.rows != f.n || a.cols != f.n {
+		return nil, fmt.Errorf("matrix must be %dx%d, got %dx%d", f.n, f.n, a.rows, a.cols)
+	}
+	if !a.IsInvertible() {
+		return nil, errors.New("matrix is not invertible over GF(2)")
+	}
+	if b < 0 || b >= 1<<f.n {
+		return nil, fmt.Errorf("translation %d out of range (must be in [0, 2^%d))", b, f.n)
+	}
+	g := f.transformInputs(a, b)
+	if c == 0 && d == 0 {
+		return g, nil
+	}
+	return g.AddAffine(c, d)
+}
+
+// PermuteVariables 返回 g(x) = f(x_{perm[0]}, x_{perm[1]}, ..., x_{perm[n-1]}).
+// 例如论文数据中使用的置换 (6,5,1,4,7,2,3,0,8) 把 f 的第 0 个输入换成 x6、第 1 个换成 x5，依此类推。
+func (f *BooleanFunction) PermuteVariables(perm []int) (*BooleanFunction, error) {
+	if len(perm) != f.n {
+		return nil, fmt.Errorf("permutation length %d does not match n=%d", len(perm), f.n)
+	}
+	p, err := PermutationMatrix(perm)
+	if err != nil {
+		return nil, err
+	}
+	return f.transformInputs(p, 0), nil
+}
+
+// Translate 返回 g(x) = f(x ⊕ b)，按字重排，复杂度 O(2^n/64).
+func (f *BooleanFunction) Translate(b int) (*BooleanFunction, error) {
+	if b < 0 || b >= 1<<f.n {
+		return nil, fmt.Errorf("translation %d out of range (must be in [0, 2^%d))", b, f.n)
+	}
+	words := f.packedTruthTable
+	packed := make([]uint64, len(words))
+	wordShift, inWord := b>>6, b&63
+	for w := range packed {
+		packed[w] = permuteWordXor(words[w^wordShift], inWord)
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}, nil
+}
+
+// --- 私有实现 ---
+
+// transformInputs 返回 g(x) = f(Ax ⊕ b)，调用方保证 A 为 n×n 可逆矩阵.
+// 按格雷码顺序枚举 x，每步只有一位变化，Ax 只需异或上 A 的一列。
+func (f *BooleanFunction) transformInputs(a *BitMatrix, b int) *BooleanFunction {
+	columns := make([]int, f.n)
+	for j := range columns {
+		columns[j] = a.MulVec(1 << uint(j))
+	}
+	src := f.packedTruthTable
+	packed := make([]uint64, len(src))
+	y := b
+	packed[0] = (src[y>>6] >> uint(y&63)) & 1
+	for i := 1; i < 1<<f.n; i++ {
+		y ^= columns[bits.TrailingZeros(uint(i))]
+		x := i ^ (i >> 1)
+		packed[x>>6] |= ((src[y>>6] >> uint(y&63)) & 1) << uint(x&63)
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}
+}
+
+// validatePermutation 检查 perm 是 {0, ..., len(perm)-1} 上的置换.
+func validatePermutation(perm []int) error {
+	if len(perm) == 0 {
+		return errors.New("permutation must not be empty")
+	}
+	seen := make([]bool, len(perm))
+	for _, p := range perm {
+		if p < 0 || p >= len(perm) {
+			return fmt.Errorf("permutation entry %d out of range [0, %d)", p, len(perm))
+		}
+		if seen[p] {
+			return fmt.Errorf("permutation entry %d appears more than once", p)
+		}
+		seen[p] = true
+	}
+	return nil
+}
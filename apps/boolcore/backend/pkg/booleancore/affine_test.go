@@ -0,0 +1,92 @@
+package booleancore
+
+import (
+	"math/bits"
+	"testing"
+)
+
+// TestAffineTransform 将 f(Ax⊕b)⊕c·x⊕d 与逐点计算对比，并检查仿射不变量
+func TestAffineTransform(t *testing.T) {
+	f := randomFunction(t, 7, 5)
+	ft := f.TruthTable()
+
+	// 上三角加一个非零下三角元素的可逆矩阵
+	a := IdentityMatrix(7)
+	for r := 0; r < 7; r++ {
+		for c := r + 1; c < 7; c += 2 {
+			a.Set(r, c, 1)
+		}
+	}
+	a.Set(6, 0, 1)
+	if !a.IsInvertible() {
+		t.Fatal("测试矩阵应可逆")
+	}
+	b, c := 0x2d, 0x51
+	g, err := f.AffineTransform(a, b, c, 1)
+	if err != nil {
+		t.Fatalf("AffineTransform error: %v", err)
+	}
+	gt := g.TruthTable()
+	for x := range gt {
+		want := ft[a.MulVec(x)^b] ^ byte(bits.OnesCount(uint(c&x))&1) ^ 1
+		if gt[x] != want {
+			t.Fatalf("x=%d: g(x)=%d, 期望 %d", x, gt[x], want)
+		}
+	}
+
+	if g.AlgebraicDegree() != f.AlgebraicDegree() || g.Nonlinearity() != f.Nonlinearity() ||
+		g.AbsoluteIndicator() != f.AbsoluteIndicator() {
+		t.Error("仿射变换应保持代数次数、非线性度与绝对指标")
+	}
+
+	singular := NewBitMatrix(7, 7)
+	if _, err := f.AffineTransform(singular, 0, 0, 0); err == nil {
+		t.Error("不可逆矩阵应返回错误")
+	}
+	if _, err := f.AffineTransform(IdentityMatrix(6), 0, 0, 0); err == nil {
+		t.Error("维数不匹配应返回错误")
+	}
+}
+
+// TestPermuteVariables 使用论文中的置换验证变量置换、逆置换与平移
+func TestPermuteVariables(t *testing.T) {
+	perm, err := ParsePermutation("(6,5,1,4,7,2,3,0,8)")
+	if err != nil {
+		t.Fatalf("ParsePermutation error: %v", err)
+	}
+	f := randomFunction(t, 9, 11)
+	g, err := f.PermuteVariables(perm)
+	if err != nil {
+		t.Fatalf("PermuteVariables error: %v", err)
+	}
+	ft, gt := f.TruthTable(), g.TruthTable()
+	for x := range gt {
+		y := 0
+		for i, p := range perm {
+			y |= ((x >> uint(p)) & 1) << uint(i)
+		}
+		if gt[x] != ft[y] {
+			t.Fatalf("x=%d: g(x)=%d, 期望 f(%d)=%d", x, gt[x], y, ft[y])
+		}
+	}
+
+	inv, _ := InversePermutation(perm)
+	if back, _ := g.PermuteVariables(inv); !back.Equal(f) {
+		t.Error("应用逆置换后应还原原函数")
+	}
+
+	for _, bad := range []string{"(0,1,1)", "(0,3,1)", "(a,b)", "()"} {
+		if _, err := ParsePermutation(bad); err == nil {
+			t.Errorf("ParsePermutation(%q) 应返回错误", bad)
+		}
+	}
+
+	// Translate 与 AffineTransform(I, b) 一致
+	for _, b := range []int{0, 1, 37, 64, 300, 511} {
+		h1, _ := f.Translate(b)
+		h2, _ := f.AffineTransform(IdentityMatrix(9), b, 0, 0)
+		if !h1.Equal(h2) {
+			t.Errorf("Translate(%d) 与 AffineTransform 结果不一致", b)
+		}
+	}
+}
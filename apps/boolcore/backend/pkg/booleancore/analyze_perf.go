@@ -1,10 +1,12 @@
 package booleancore
 
 import (
+	"context"
 	"time"
 )
 
 // AnalyzeResult 汇总布尔函数的各项性质，便于一次性计算和比较。
+// 计算失败的属性字段保持零值，失败原因记录在 Errors 中（不再使用 -1 之类的哨兵值）。
 type AnalyzeResult struct {
 	N                               int
 	TruthTable                      []byte
@@ -21,121 +23,41 @@ type AnalyzeResult struct {
 	IsBent                          bool
 	SumOfSquareIndicator            int64
 	IsRotationSymmetric             bool
+	IsDihedralSymmetric             bool
 	AbsoluteWalshSpectrum           map[int64]int
 	AbsoluteAutocorrelationSpectrum map[int64]int
 	AbsoluteIndicator               int64
 	DifferentialUniformity          int64
+	PropagationCriterion            int
+	IsSAC                           bool
+	AvalancheProfile                []AvalancheWeight
+	IsPlateaued                     bool
+	PlateauedOrder                  int // 仅 IsPlateaued 为 true 时有意义
+	IsThreeValued                   bool
+	WalshSupport                    []int
+	SpectralClass                   SpectralClass
 	AlgebraicImmunity               int
 	FAA                             int
 	FAAWithPositiveDegree           int
 	FAI                             int
+	Computed                        map[string]bool // 实际计算过的属性键，见 analyze_select.go
+
+	Errors     map[string]error         // 计算失败的属性键及原因，包括因依赖失败而跳过的属性
+	Timings    map[string]time.Duration // 每个属性的耗时（AnalyzeOptions.Timing 为 true 时记录）
+	Extensions map[string]interface{}   // 通过 Analyzer.Register 注册的自定义属性的值
 }
 
 // AnalyzeAll 计算所有核心性质（快速版本：代数免疫度不求零化子表达式）。
 func AnalyzeAll(bf *BooleanFunction) AnalyzeResult {
 	// 注意：内部方法已经带有缓存（如WHT/自相关），多处复用不会重复计算。
-	res := AnalyzeResult{N: bf.N(), TruthTable: bf.TruthTable()}
-	res.HammingWeight = bf.HammingWeight()
-	res.IsBalanced = bf.IsBalanced()
-	res.ANF = bf.AlgebraicNormalForm()
-	res.AlgebraicDegree = bf.AlgebraicDegree()
-	res.WalshSpectrum = bf.WalshHadamardTransform()
-	res.AutocorrelationSpectrum = bf.Autocorrelation()
-	res.TransparencyOrder = bf.TransparencyOrder()
-	res.Nonlinearity = bf.Nonlinearity()
-	res.CorrelationImmunity = bf.CorrelationImmunity()
-	res.ResiliencyOrder = bf.ResiliencyOrder()
-	res.IsBent = bf.IsBent()
-	res.SumOfSquareIndicator = bf.SumOfSquareIndicator()
-	res.IsRotationSymmetric = bf.IsRotationSymmetric()
-	res.AbsoluteWalshSpectrum = bf.AbsoluteWalshSpectrum()
-	res.AbsoluteAutocorrelationSpectrum = bf.AbsoluteAutocorrelation()
-	res.AbsoluteIndicator = bf.AbsoluteIndicator()
-	res.DifferentialUniformity = bf.DifferentialUniformity()
-	if ai, _, err := bf.AlgebraicImmunity(false); err == nil {
-		res.AlgebraicImmunity = ai
-	} else {
-		res.AlgebraicImmunity = -1
-	}
-	if faa, err := bf.FAA(); err == nil {
-		res.FAA = faa
-	} else {
-		res.FAA = -1
-	}
-	if faaPositive, err := bf.FAAWithPositiveDegree(); err == nil {
-		res.FAAWithPositiveDegree = faaPositive
-	} else {
-		res.FAAWithPositiveDegree = -1
-	}
-	if fai, err := bf.FAI(); err == nil {
-		res.FAI = fai
-	} else {
-		res.FAI = -1
-	}
+	res, _ := AnalyzeSelected(bf, nil) // 空列表即全部属性，不会返回错误
 	return res
 }
 
 // AnalyzeAllTimed 在 AnalyzeAll 基础上返回每一步耗时与总耗时，方便与 SageMath 做时间对比。
+// 耗时的键即属性键（如 "walshSpectrum"），依赖的缓存计算计入最先用到它的属性。
 func AnalyzeAllTimed(bf *BooleanFunction) (AnalyzeResult, map[string]time.Duration, time.Duration) {
-	timings := make(map[string]time.Duration)
 	startTotal := time.Now()
-	step := func(name string, fn func()) {
-		s := time.Now()
-		fn()
-		timings[name] = time.Since(s)
-	}
-
-	var res AnalyzeResult
-	step("construct_result", func() {
-		res.N = bf.N()
-		res.TruthTable = bf.TruthTable()
-	})
-	step("hamming_weight", func() { res.HammingWeight = bf.HammingWeight() })
-	step("is_balanced", func() { res.IsBalanced = bf.IsBalanced() })
-	step("anf", func() { res.ANF = bf.AlgebraicNormalForm() })
-	step("algebraic_degree", func() { res.AlgebraicDegree = bf.AlgebraicDegree() })
-	step("walsh_hadamard", func() { res.WalshSpectrum = bf.WalshHadamardTransform() })
-	step("autocorrelation", func() { res.AutocorrelationSpectrum = bf.Autocorrelation() })
-	step("transparency_order", func() { res.TransparencyOrder = bf.TransparencyOrder() })
-	step("nonlinearity", func() { res.Nonlinearity = bf.Nonlinearity() })
-	step("correlation_immunity", func() { res.CorrelationImmunity = bf.CorrelationImmunity() })
-	step("resiliency_order", func() { res.ResiliencyOrder = bf.ResiliencyOrder() })
-	step("is_bent", func() { res.IsBent = bf.IsBent() })
-	step("sum_of_square_indicator", func() { res.SumOfSquareIndicator = bf.SumOfSquareIndicator() })
-	step("rotation_symmetric", func() { res.IsRotationSymmetric = bf.IsRotationSymmetric() })
-	step("absolute_walsh_spectrum", func() { res.AbsoluteWalshSpectrum = bf.AbsoluteWalshSpectrum() })
-	step("absolute_autocorr_spectrum", func() { res.AbsoluteAutocorrelationSpectrum = bf.AbsoluteAutocorrelation() })
-	step("absolute_indicator", func() { res.AbsoluteIndicator = bf.AbsoluteIndicator() })
-	step("differential_uniformity", func() { res.DifferentialUniformity = bf.DifferentialUniformity() })
-	step("algebraic_immunity_fast", func() {
-		if ai, _, err := bf.AlgebraicImmunity(false); err == nil {
-			res.AlgebraicImmunity = ai
-		} else {
-			res.AlgebraicImmunity = -1
-		}
-	})
-	step("faa", func() {
-		if faa, err := bf.FAA(); err == nil {
-			res.FAA = faa
-		} else {
-			res.FAA = -1
-		}
-	})
-	step("faa_positive_degree", func() {
-		if faaPositive, err := bf.FAAWithPositiveDegree(); err == nil {
-			res.FAAWithPositiveDegree = faaPositive
-		} else {
-			res.FAAWithPositiveDegree = -1
-		}
-	})
-	step("fai", func() {
-		if fai, err := bf.FAI(); err == nil {
-			res.FAI = fai
-		} else {
-			res.FAI = -1
-		}
-	})
-
-	total := time.Since(startTotal)
-	return res, timings, total
+	res, _ := defaultAnalyzer.Analyze(context.Background(), bf, nil, AnalyzeOptions{Timing: true})
+	return res, res.Timings, time.Since(startTotal)
 }
@@ -0,0 +1,110 @@
+package booleancore
+
+import (
+	"context"
+)
+
+// 属性键与 API 响应中的 JSON 字段名保持一致，便于前后端直接对应。
+const (
+	PropHammingWeight                   = "hammingWeight"
+	PropIsBalanced                      = "isBalanced"
+	PropANF                             = "anf"
+	PropAlgebraicDegree                 = "algebraicDegree"
+	PropWalshSpectrum                   = "walshSpectrum"
+	PropAutocorrelationSpectrum         = "autocorrelationSpectrum"
+	PropTransparencyOrder               = "transparencyOrder"
+	PropNonlinearity                    = "nonlinearity"
+	PropCorrelationImmunity             = "correlationImmunity"
+	PropResiliencyOrder                 = "resiliencyOrder"
+	PropIsBent                          = "isBent"
+	PropSumOfSquareIndicator            = "sumOfSquareIndicator"
+	PropIsRotationSymmetric             = "isRotationSymmetric"
+	PropIsDihedralSymmetric             = "isDihedralSymmetric"
+	PropAbsoluteWalshSpectrum           = "absoluteWalshSpectrum"
+	PropAbsoluteAutocorrelationSpectrum = "absoluteAutocorrelationSpectrum"
+	PropAbsoluteIndicator               = "absoluteIndicator"
+	PropDifferentialUniformity          = "differentialUniformity"
+	PropPropagationCriterion            = "propagationCriterion"
+	PropIsSAC                           = "isSAC"
+	PropAvalancheProfile                = "avalancheProfile"
+	PropIsPlateaued                     = "isPlateaued"
+	PropPlateauedOrder                  = "plateauedOrder"
+	PropIsThreeValued                   = "isThreeValued"
+	PropWalshSupport                    = "walshSupport"
+	PropSpectralClass                   = "spectralClass"
+	PropAlgebraicImmunity               = "algebraicImmunity"
+	PropFAA                             = "faa"
+	PropFAAWithPositiveDegree           = "faaWithPositiveDegree"
+	PropFAI                             = "fai"
+)
+
+// builtinPresets 是常用的属性组合，可以在属性列表中直接使用预设名.
+// 另有两个由 Analyzer 按当前注册的属性动态生成的预设：
+// "all" 为全部属性；"fast" 排除了代价为 CostExpensive 的属性
+// （代数免疫度与快速代数攻击相关的指标，它们在 n >= 12 时耗时占绝对主导）。
+var builtinPresets = map[string][]string{
+	"basic": {PropHammingWeight, PropIsBalanced, PropIsRotationSymmetric, PropIsDihedralSymmetric},
+	"spectral": {
+		PropWalshSpectrum, PropAutocorrelationSpectrum, PropAbsoluteWalshSpectrum,
+		PropAbsoluteAutocorrelationSpectrum, PropNonlinearity, PropCorrelationImmunity,
+		PropIsBent, PropSumOfSquareIndicator, PropAbsoluteIndicator,
+		PropTransparencyOrder, PropDifferentialUniformity,
+		PropPropagationCriterion, PropIsSAC, PropAvalancheProfile,
+		PropIsPlateaued, PropPlateauedOrder, PropIsThreeValued, PropWalshSupport, PropSpectralClass,
+	},
+	"algebraic": {
+		PropANF, PropAlgebraicDegree, PropAlgebraicImmunity,
+		PropFAA, PropFAAWithPositiveDegree, PropFAI,
+	},
+}
+
+// 以下包级函数作用于默认的 Analyzer（见 DefaultAnalyzer），API 层直接使用它们。
+
+// AllProperties 返回全部属性键（按计算顺序）.
+func AllProperties() []string {
+	return defaultAnalyzer.AllProperties()
+}
+
+// Properties 返回所有属性的元数据（按计算顺序）.
+func Properties() []PropertyInfo {
+	return defaultAnalyzer.Properties()
+}
+
+// LookupProperty 返回属性键对应的元数据.
+func LookupProperty(key string) (PropertyInfo, bool) {
+	return defaultAnalyzer.Lookup(key)
+}
+
+// Presets 返回所有预设及其包含的属性键.
+func Presets() map[string][]string {
+	return defaultAnalyzer.Presets()
+}
+
+// PresetNames 返回所有预设名（按字母序）.
+func PresetNames() []string {
+	return defaultAnalyzer.PresetNames()
+}
+
+// ExpandProperties 展开属性列表中的预设名并去重，返回按计算顺序排列的属性键.
+// 空列表表示全部属性；未知的属性或预设名会返回错误。
+func ExpandProperties(names []string) ([]string, error) {
+	return defaultAnalyzer.Expand(names)
+}
+
+// ResolveProperties 在 ExpandProperties 的基础上补全所有（传递）依赖.
+func ResolveProperties(names []string) ([]string, error) {
+	return defaultAnalyzer.Resolve(names)
+}
+
+// AnalyzeSelected 只计算 names 中列出的属性（可包含预设名）及其依赖.
+// 结果中 Computed 记录了计算成功的属性，Errors 记录了计算失败的属性，未计算的字段保持零值。
+func AnalyzeSelected(bf *BooleanFunction, names []string) (AnalyzeResult, error) {
+	return AnalyzeSelectedCtx(context.Background(), bf, names)
+}
+
+// AnalyzeSelectedCtx 是 AnalyzeSelected 的可取消版本.
+// ctx 被取消时立即停止（包括代数免疫度、FAA/FAI 的次数循环和 FWHT 的每一级），
+// 并返回 ctx.Err() 以及已完成部分的结果。
+func AnalyzeSelectedCtx(ctx context.Context, bf *BooleanFunction, names []string) (AnalyzeResult, error) {
+	return defaultAnalyzer.Analyze(ctx, bf, names, AnalyzeOptions{})
+}
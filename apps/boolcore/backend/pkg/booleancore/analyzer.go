@@ -0,0 +1,338 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Analyzer 按注册的属性计算布尔函数的性质：
+//   - 内置属性来自 builtinProperties，NewAnalyzer 创建时全部登记；
+//   - 自定义属性通过 Register 追加，无需修改本包（值放在 AnalyzeResult.Extensions 中）；
+//   - 计算顺序即登记顺序，依赖必须先于使用它的属性登记，因此顺序总是满足依赖；
+//   - 单个属性失败（包括 panic）只记入 AnalyzeResult.Errors，依赖它的属性随之跳过，
+//     其余属性照常计算；只有 ctx 被取消时整个分析才会中止。
+//
+// Analyzer 可被多个 goroutine 并发使用。
+
+// PropertyFunc 计算一个自定义属性的值.
+// res 中已包含该属性所有依赖的结果（内置属性在对应字段中，自定义属性在 Extensions 中）。
+type PropertyFunc func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) (interface{}, error)
+
+// PropertyProvider 描述一个可注册到 Analyzer 的自定义属性.
+type PropertyProvider struct {
+	PropertyInfo
+	Compute PropertyFunc
+}
+
+// AnalyzeOptions 控制一次分析的行为.
+type AnalyzeOptions struct {
+	Timing bool // 记录每个属性的耗时到 AnalyzeResult.Timings
+}
+
+// Analyzer 管理属性注册表并执行分析.
+type Analyzer struct {
+	mu    sync.RWMutex
+	defs  []*propertyDef // 按登记顺序
+	index map[string]*propertyDef
+}
+
+// defaultAnalyzer 是包级函数（AnalyzeAll、AnalyzeSelected、ExpandProperties 等）使用的 Analyzer.
+var defaultAnalyzer = NewAnalyzer()
+
+// DefaultAnalyzer 返回包级函数与 API 使用的 Analyzer，在其上 Register 的属性对它们同样可见.
+func DefaultAnalyzer() *Analyzer {
+	return defaultAnalyzer
+}
+
+// RegisterProperty 在默认 Analyzer 上注册自定义属性，注册后 API 的 properties 参数与 GET /api/properties 均可使用.
+func RegisterProperty(p PropertyProvider) error {
+	return defaultAnalyzer.Register(p)
+}
+
+// NewAnalyzer 创建一个只包含内置属性的 Analyzer.
+func NewAnalyzer() *Analyzer {
+	a := &Analyzer{index: make(map[string]*propertyDef, len(builtinProperties))}
+	for i := range builtinProperties {
+		def := builtinProperties[i]
+		if err := a.addLocked(&def); err != nil {
+			panic("booleancore: invalid builtin property: " + err.Error())
+		}
+	}
+	return a
+}
+
+// Register 注册一个自定义属性. 属性键不能与已有属性或预设名重复，依赖必须已注册.
+func (a *Analyzer) Register(p PropertyProvider) error {
+	if p.Key == "" {
+		return errors.New("property key must not be empty")
+	}
+	if p.Compute == nil {
+		return fmt.Errorf("property %q has no compute function", p.Key)
+	}
+	info := p.PropertyInfo
+	if info.Label == "" {
+		info.Label = info.Key
+	}
+	if info.Cost == "" {
+		info.Cost = CostModerate
+	}
+	info.Dependencies = append([]string(nil), info.Dependencies...)
+
+	key, compute := info.Key, p.Compute
+	def := &propertyDef{
+		PropertyInfo: info,
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			v, err := compute(ctx, bf, res)
+			if err != nil {
+				return err
+			}
+			if res.Extensions == nil {
+				res.Extensions = make(map[string]interface{})
+			}
+			res.Extensions[key] = v
+			return nil
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.Extensions[key] },
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addLocked(def)
+}
+
+func (a *Analyzer) addLocked(def *propertyDef) error {
+	if _, dup := a.index[def.Key]; dup {
+		return fmt.Errorf("property %q is already registered", def.Key)
+	}
+	if _, isPreset := builtinPresets[def.Key]; isPreset || def.Key == "all" || def.Key == "fast" {
+		return fmt.Errorf("property key %q conflicts with a preset name", def.Key)
+	}
+	for _, dep := range def.Dependencies {
+		if _, ok := a.index[dep]; !ok {
+			return fmt.Errorf("property %q depends on unregistered property %q", def.Key, dep)
+		}
+	}
+	a.defs = append(a.defs, def)
+	a.index[def.Key] = def
+	return nil
+}
+
+// AllProperties 返回全部属性键（按计算顺序）.
+func (a *Analyzer) AllProperties() []string {
+	return a.keysWhere(func(PropertyInfo) bool { return true })
+}
+
+// Properties 返回所有属性的元数据（按计算顺序）.
+func (a *Analyzer) Properties() []PropertyInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	infos := make([]PropertyInfo, len(a.defs))
+	for i, def := range a.defs {
+		infos[i] = def.PropertyInfo
+		infos[i].Dependencies = append([]string(nil), def.Dependencies...)
+	}
+	return infos
+}
+
+// Lookup 返回属性键对应的元数据.
+func (a *Analyzer) Lookup(key string) (PropertyInfo, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	def, ok := a.index[key]
+	if !ok {
+		return PropertyInfo{}, false
+	}
+	info := def.PropertyInfo
+	info.Dependencies = append([]string(nil), def.Dependencies...)
+	return info, true
+}
+
+// Presets 返回所有预设及其包含的属性键，其中 "all" 与 "fast" 反映当前注册的属性.
+func (a *Analyzer) Presets() map[string][]string {
+	presets := make(map[string][]string, len(builtinPresets)+2)
+	for name, keys := range builtinPresets {
+		presets[name] = append([]string(nil), keys...)
+	}
+	presets["all"] = a.AllProperties()
+	presets["fast"] = a.keysWhere(func(info PropertyInfo) bool { return info.Cost != CostExpensive })
+	return presets
+}
+
+// PresetNames 返回所有预设名（按字母序）.
+func (a *Analyzer) PresetNames() []string {
+	presets := a.Presets()
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand 展开属性列表中的预设名并去重，返回按计算顺序排列的属性键.
+// 空列表表示全部属性；未知的属性或预设名会返回错误。
+func (a *Analyzer) Expand(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return a.AllProperties(), nil
+	}
+	presets := a.Presets()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if preset, ok := presets[name]; ok {
+			for _, key := range preset {
+				wanted[key] = true
+			}
+			continue
+		}
+		if _, ok := a.index[name]; !ok {
+			return nil, fmt.Errorf("unknown property or preset %q", name)
+		}
+		wanted[name] = true
+	}
+	return a.orderedLocked(wanted), nil
+}
+
+// Resolve 在 Expand 的基础上补全所有（传递）依赖.
+func (a *Analyzer) Resolve(names []string) ([]string, error) {
+	expanded, err := a.Expand(names)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	resolved := make(map[string]bool)
+	var visit func(key string)
+	visit = func(key string) {
+		if resolved[key] {
+			return
+		}
+		resolved[key] = true
+		for _, dep := range a.index[key].Dependencies {
+			visit(dep)
+		}
+	}
+	for _, key := range expanded {
+		visit(key)
+	}
+	return a.orderedLocked(resolved), nil
+}
+
+// Analyze 计算 names 中列出的属性（可包含预设名，空列表表示全部）及其依赖.
+// 返回的错误只可能是未知属性名或 ctx 被取消；单个属性的失败记录在 res.Errors 中。
+func (a *Analyzer) Analyze(ctx context.Context, bf *BooleanFunction, names []string, opts AnalyzeOptions) (AnalyzeResult, error) {
+	keys, err := a.Resolve(names)
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+	a.mu.RLock()
+	defs := make([]*propertyDef, len(keys))
+	for i, key := range keys {
+		defs[i] = a.index[key]
+	}
+	a.mu.RUnlock()
+
+	res := AnalyzeResult{N: bf.N(), TruthTable: bf.TruthTable(), Computed: make(map[string]bool, len(keys))}
+	if opts.Timing {
+		res.Timings = make(map[string]time.Duration, len(keys))
+	}
+	for _, def := range defs {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		if failed := res.failedDependency(def); failed != "" {
+			res.setError(def.Key, fmt.Errorf("dependency %q failed", failed))
+			continue
+		}
+
+		start := time.Now()
+		err := runProperty(ctx, def, bf, &res)
+		if opts.Timing {
+			res.Timings[def.Key] = time.Since(start)
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return res, ctxErr
+			}
+			res.setError(def.Key, err)
+			continue
+		}
+		res.Computed[def.Key] = true
+	}
+	return res, nil
+}
+
+// runProperty 执行单个属性的计算，把 panic 转换为该属性的错误.
+func runProperty(ctx context.Context, def *propertyDef, bf *BooleanFunction, res *AnalyzeResult) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("property %q panicked: %v", def.Key, r)
+		}
+	}()
+	return def.compute(ctx, bf, res)
+}
+
+func (a *Analyzer) keysWhere(keep func(PropertyInfo) bool) []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	keys := make([]string, 0, len(a.defs))
+	for _, def := range a.defs {
+		if keep(def.PropertyInfo) {
+			keys = append(keys, def.Key)
+		}
+	}
+	return keys
+}
+
+func (a *Analyzer) orderedLocked(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for _, def := range a.defs {
+		if set[def.Key] {
+			keys = append(keys, def.Key)
+		}
+	}
+	return keys
+}
+
+// Value 按属性键取出结果中的值：内置属性取对应字段，自定义属性取 Extensions.
+// 属性未成功计算时返回 false.
+func (res *AnalyzeResult) Value(key string) (interface{}, bool) {
+	if !res.Computed[key] {
+		return nil, false
+	}
+	if def, ok := defaultAnalyzer.lookupDef(key); ok {
+		return def.value(res), true
+	}
+	v, ok := res.Extensions[key]
+	return v, ok
+}
+
+func (a *Analyzer) lookupDef(key string) (*propertyDef, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	def, ok := a.index[key]
+	return def, ok
+}
+
+func (res *AnalyzeResult) failedDependency(def *propertyDef) string {
+	for _, dep := range def.Dependencies {
+		if _, failed := res.Errors[dep]; failed {
+			return dep
+		}
+	}
+	return ""
+}
+
+func (res *AnalyzeResult) setError(key string, err error) {
+	if res.Errors == nil {
+		res.Errors = make(map[string]error)
+	}
+	res.Errors[key] = err
+}
@@ -0,0 +1,120 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestAnalyzerCustomProperty 检查自定义属性的注册、依赖展开、取值与失败隔离
+func TestAnalyzerCustomProperty(t *testing.T) {
+	a := NewAnalyzer()
+	err := a.Register(PropertyProvider{
+		PropertyInfo: PropertyInfo{Key: "walshMax", Category: CategorySpectral, ResultType: ResultNumber,
+			Dependencies: []string{PropWalshSpectrum}},
+		Compute: func(_ context.Context, _ *BooleanFunction, res *AnalyzeResult) (interface{}, error) {
+			return maxAbsValue(res.WalshSpectrum, 0), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register 失败: %v", err)
+	}
+	if err := a.Register(PropertyProvider{
+		PropertyInfo: PropertyInfo{Key: "alwaysFails"},
+		Compute: func(context.Context, *BooleanFunction, *AnalyzeResult) (interface{}, error) {
+			return nil, errors.New("boom")
+		},
+	}); err != nil {
+		t.Fatalf("Register 失败: %v", err)
+	}
+	if err := a.Register(PropertyProvider{
+		PropertyInfo: PropertyInfo{Key: "dependsOnFailure", Dependencies: []string{"alwaysFails"}},
+		Compute: func(context.Context, *BooleanFunction, *AnalyzeResult) (interface{}, error) {
+			t.Error("依赖失败时不应计算")
+			return nil, nil
+		},
+	}); err != nil {
+		t.Fatalf("Register 失败: %v", err)
+	}
+	if err := a.Register(PropertyProvider{
+		PropertyInfo: PropertyInfo{Key: "panics"},
+		Compute: func(context.Context, *BooleanFunction, *AnalyzeResult) (interface{}, error) {
+			panic("unexpected")
+		},
+	}); err != nil {
+		t.Fatalf("Register 失败: %v", err)
+	}
+
+	bf := randomFunction(t, 6, 3)
+	res, err := a.Analyze(context.Background(), bf, []string{"walshMax", "dependsOnFailure", "panics"}, AnalyzeOptions{Timing: true})
+	if err != nil {
+		t.Fatalf("Analyze 返回错误: %v", err)
+	}
+	if got, want := res.Extensions["walshMax"], int64(1<<5)-bf.Nonlinearity(); got != 2*want {
+		t.Errorf("walshMax = %v, 期望 %d", got, 2*want)
+	}
+	if !res.Computed[PropWalshSpectrum] || !res.Computed["walshMax"] {
+		t.Errorf("依赖或自定义属性未计算: %v", res.Computed)
+	}
+	if res.Computed[PropNonlinearity] {
+		t.Error("未请求的属性不应被计算")
+	}
+	if _, ok := res.Timings["walshMax"]; !ok {
+		t.Error("Timing 为 true 时应记录每个属性的耗时")
+	}
+	if err := res.Errors["alwaysFails"]; err == nil || err.Error() != "boom" {
+		t.Errorf("alwaysFails 的错误 = %v", err)
+	}
+	if err := res.Errors["dependsOnFailure"]; err == nil || !strings.Contains(err.Error(), "alwaysFails") {
+		t.Errorf("dependsOnFailure 的错误 = %v", err)
+	}
+	if err := res.Errors["panics"]; err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("panics 的错误 = %v", err)
+	}
+
+	// 自定义属性只注册在 a 上，不影响默认 Analyzer
+	if _, ok := LookupProperty("walshMax"); ok {
+		t.Error("NewAnalyzer 创建的 Analyzer 不应修改默认注册表")
+	}
+	if presets := a.Presets(); len(presets["all"]) != len(AllProperties())+4 {
+		t.Errorf("all 预设应包含自定义属性: %v", presets["all"])
+	}
+}
+
+// TestAnalyzerRegisterErrors 检查非法注册被拒绝
+func TestAnalyzerRegisterErrors(t *testing.T) {
+	a := NewAnalyzer()
+	compute := func(context.Context, *BooleanFunction, *AnalyzeResult) (interface{}, error) { return 0, nil }
+	cases := map[string]PropertyProvider{
+		"空键":     {Compute: compute},
+		"缺少计算函数": {PropertyInfo: PropertyInfo{Key: "noCompute"}},
+		"重复键":    {PropertyInfo: PropertyInfo{Key: PropNonlinearity}, Compute: compute},
+		"与预设重名":  {PropertyInfo: PropertyInfo{Key: "fast"}, Compute: compute},
+		"依赖未注册":  {PropertyInfo: PropertyInfo{Key: "orphan", Dependencies: []string{"missing"}}, Compute: compute},
+	}
+	for name, p := range cases {
+		if err := a.Register(p); err == nil {
+			t.Errorf("%s: Register 应返回错误", name)
+		}
+	}
+	if got, want := len(a.AllProperties()), len(builtinProperties); got != want {
+		t.Errorf("注册失败后属性数 = %d, 期望 %d", got, want)
+	}
+}
+
+// TestAnalyzeAllTimedKeys 检查分步耗时以属性键为键
+func TestAnalyzeAllTimedKeys(t *testing.T) {
+	res, timings, total := AnalyzeAllTimed(randomFunction(t, 5, 7))
+	for _, key := range AllProperties() {
+		if _, ok := timings[key]; !ok {
+			t.Errorf("缺少属性 %s 的耗时", key)
+		}
+		if !res.Computed[key] {
+			t.Errorf("属性 %s 未计算", key)
+		}
+	}
+	if total <= 0 {
+		t.Errorf("总耗时 = %v", total)
+	}
+}
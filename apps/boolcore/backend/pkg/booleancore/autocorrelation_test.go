@@ -0,0 +1,108 @@
+package booleancore
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// TestExactAutocorrelation 将精确整数路径与原 float64 路径、逐点直接计算相互对照
+func TestExactAutocorrelation(t *testing.T) {
+	for n := 1; n <= 14; n++ {
+		bf := randomFunction(t, n, int64(100+n))
+		ac := bf.Autocorrelation()
+
+		if want := autocorrelationFloat(bf.WalshHadamardTransform()); !reflect.DeepEqual(ac, want) {
+			t.Fatalf("n=%d: 精确路径与 float64 路径不一致", n)
+		}
+		if ac[0] != int64(1)<<n {
+			t.Errorf("n=%d: r(0) 应为 2^n, 实际 %d", n, ac[0])
+		}
+
+		if n > 10 {
+			continue // 逐点计算是 O(4^n/64)，只在小 n 上对照
+		}
+		for a := range ac {
+			direct, err := bf.AutocorrelationAt(a)
+			if err != nil {
+				t.Fatalf("AutocorrelationAt(%d) error: %v", a, err)
+			}
+			if direct != ac[a] {
+				t.Fatalf("n=%d a=%d: 直接计算 %d, 谱计算 %d", n, a, direct, ac[a])
+			}
+		}
+	}
+
+	bf := randomFunction(t, 4, 1)
+	if _, err := bf.AutocorrelationAt(16); err == nil {
+		t.Error("越界的 a 应返回错误")
+	}
+}
+
+// TestAutocorrelationInt128 验证 128 位路径与 int64 路径结果一致
+func TestAutocorrelationInt128(t *testing.T) {
+	for _, n := range []int{3, 8, 12} {
+		bf := randomFunction(t, n, int64(n))
+		got, err := autocorrelationInt128(context.Background(), bf.WalshHadamardTransform(), n)
+		if err != nil {
+			t.Fatalf("autocorrelationInt128 error: %v", err)
+		}
+		if want := bf.Autocorrelation(); !reflect.DeepEqual(got, want) {
+			t.Errorf("n=%d: 128 位路径与 int64 路径不一致", n)
+		}
+	}
+}
+
+// TestSumOfSquareIndicatorLarge 验证超出 int64 的平方和指标：仿射函数 σ_f = 2^{3n}
+func TestSumOfSquareIndicatorLarge(t *testing.T) {
+	bf, err := NewFromExpr(22, "x0 ^ x21")
+	if err != nil {
+		t.Fatalf("NewFromExpr error: %v", err)
+	}
+	want := new(big.Int).Lsh(big.NewInt(1), 66)
+	if got := bf.SumOfSquareIndicatorBig(); got.Cmp(want) != 0 {
+		t.Errorf("σ_f 应为 2^66, 实际 %s", got)
+	}
+	if got := bf.SumOfSquareIndicator(); got != math.MaxInt64 {
+		t.Errorf("超出 int64 时应饱和为 MaxInt64, 实际 %d", got)
+	}
+
+	small := randomFunction(t, 10, 3)
+	if got := small.SumOfSquareIndicatorBig(); got.Int64() != small.SumOfSquareIndicator() {
+		t.Errorf("小 n 时两种实现应一致: %s vs %d", got, small.SumOfSquareIndicator())
+	}
+}
+
+// autocorrelationFloat 是原先基于 float64 的实现，仅在 n < 27 时精确，作为精确整数路径的对照.
+func autocorrelationFloat(wht []int64) []int64 {
+	length := len(wht)
+	sSquaredFloat := make([]float64, length)
+	for i := 0; i < length; i++ {
+		sSquaredFloat[i] = float64(wht[i]) * float64(wht[i])
+	}
+	fwhtFloatInplace(sSquaredFloat)
+
+	res := make([]int64, length)
+	nFloat := float64(length)
+	for i := 0; i < length; i++ {
+		res[i] = int64(math.Round(sSquaredFloat[i] / nFloat))
+	}
+	return res
+}
+
+// fwhtFloatInplace 是 fwhtInplace 的 float64 版本.
+func fwhtFloatInplace(data []float64) {
+	n := len(data)
+	for step := 1; step < n; step <<= 1 {
+		for i := 0; i < n; i += step << 1 {
+			for j := 0; j < step; j++ {
+				a := data[i+j]
+				b := data[i+j+step]
+				data[i+j] = a + b
+				data[i+j+step] = a - b
+			}
+		}
+	}
+}
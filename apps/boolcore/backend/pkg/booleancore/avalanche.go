@@ -0,0 +1,144 @@
+package booleancore
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+)
+
+// 扩散（雪崩）类判据，全部由缓存的自相关谱 r(a) = 2^n - 2·wt(D_a f) 得到：
+//   - PC(l)（扩散准则）：对所有 1 ≤ wt(a) ≤ l 有 r(a) = 0，即翻转任意不超过 l 个输入位时输出以 1/2 的概率改变；
+//   - SAC（严格雪崩准则）即 PC(1)；
+//   - k 阶 PC(l)：固定任意不超过 k 个输入后得到的子函数都满足 PC(l)，k 阶 SAC 即 k 阶 PC(1)。
+
+// AvalancheWeight 是雪崩表中重量为 Weight 的所有方向 a 的汇总.
+// 翻转概率 P(a) = Pr[f(x) ≠ f(x⊕a)] = 1/2 - r(a)/2^{n+1}。
+type AvalancheWeight struct {
+	Weight             int     `json:"weight"`
+	Directions         int     `json:"directions"`         // 该重量的方向个数 C(n, w)
+	Satisfied          int     `json:"satisfied"`          // r(a) = 0 的方向个数
+	MaxAbsAC           int64   `json:"maxAbsAC"`           // max |r(a)|
+	MinFlipProbability float64 `json:"minFlipProbability"` // 翻转概率的最小值
+	MaxFlipProbability float64 `json:"maxFlipProbability"` // 翻转概率的最大值
+}
+
+// PropagationCriterion 返回使 f 满足 PC(l) 的最大 l（0 ≤ l ≤ n），不满足 SAC 时为 0.
+// bent 函数满足 PC(n)；仿射函数的所有导数都是常数，结果为 0。
+func (f *BooleanFunction) PropagationCriterion() int {
+	pc := f.n
+	for a, r := range f.autocorrelation() {
+		if a == 0 || r == 0 {
+			continue
+		}
+		if w := bits.OnesCount(uint(a)) - 1; w < pc {
+			pc = w
+		}
+	}
+	return pc
+}
+
+// SatisfiesPC 判断 f 是否满足 PC(l)，l ≤ 0 时总是满足.
+func (f *BooleanFunction) SatisfiesPC(l int) bool {
+	return f.PropagationCriterion() >= l
+}
+
+// IsSAC 判断 f 是否满足严格雪崩准则，即翻转任意一个输入位时输出以 1/2 的概率改变.
+func (f *BooleanFunction) IsSAC() bool {
+	return f.SatisfiesPC(1)
+}
+
+// SatisfiesPCOfOrder 判断 f 是否满足 k 阶 PC(l)，k 阶 SAC 即 SatisfiesPCOfOrder(1, k).
+func (f *BooleanFunction) SatisfiesPCOfOrder(l, k int) (bool, error) {
+	return f.SatisfiesPCOfOrderCtx(context.Background(), l, k)
+}
+
+// SatisfiesPCOfOrderCtx 是 SatisfiesPCOfOrder 的可取消版本，每个方向之前检查 ctx.
+//
+// 对每个 1 ≤ wt(a) ≤ l 的方向，子函数的自相关是 D_a f 在固定变量取值的陪集上的平衡性。
+// 固定更少变量得到的陪集是更细陪集的并，因此只需检查固定恰好 min(k, n-wt(a)) 个与 a 不相交的变量，
+// 复杂度 O(Σ_a C(n-wt(a), k)·2^n)。
+func (f *BooleanFunction) SatisfiesPCOfOrderCtx(ctx context.Context, l, k int) (bool, error) {
+	if l < 0 || l > f.n {
+		return false, fmt.Errorf("propagation degree %d out of range [0, %d]", l, f.n)
+	}
+	if k < 0 || k > f.n {
+		return false, fmt.Errorf("order %d out of range [0, %d]", k, f.n)
+	}
+	if k == 0 {
+		return f.SatisfiesPC(l), nil
+	}
+	size := 1 << uint(f.n)
+	for a := 1; a < size; a++ {
+		wt := bits.OnesCount(uint(a))
+		if wt > l {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		support := packedSupport(f.derivativePacked(a))
+		fixed := min(k, f.n-wt)
+		// 每个陪集大小为 2^{n-fixed}，平衡时其中恰有一半使 D_a f = 1
+		want := 1 << uint(f.n-fixed-1)
+		counts := make([]int, 1<<uint(fixed))
+		for s := 0; s < size; s++ {
+			if s&a != 0 || bits.OnesCount(uint(s)) != fixed {
+				continue
+			}
+			clear(counts)
+			for _, x := range support {
+				counts[extractBits(x, s)]++
+			}
+			for _, c := range counts {
+				if c != want {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// AvalancheProfile 返回按方向重量 1..n 汇总的雪崩表，复杂度 O(2^n)（自相关谱已缓存时）.
+func (f *BooleanFunction) AvalancheProfile() []AvalancheWeight {
+	profile := make([]AvalancheWeight, f.n)
+	for w := range profile {
+		profile[w] = AvalancheWeight{Weight: w + 1, MinFlipProbability: 1}
+	}
+	full := float64(int64(1) << uint(f.n))
+	for a, r := range f.autocorrelation() {
+		if a == 0 {
+			continue
+		}
+		entry := &profile[bits.OnesCount(uint(a))-1]
+		entry.Directions++
+		if r == 0 {
+			entry.Satisfied++
+		}
+		abs := r
+		if abs < 0 {
+			abs = -abs
+		}
+		entry.MaxAbsAC = max(entry.MaxAbsAC, abs)
+		p := 0.5 - float64(r)/(2*full)
+		entry.MinFlipProbability = min(entry.MinFlipProbability, p)
+		entry.MaxFlipProbability = max(entry.MaxFlipProbability, p)
+	}
+	return profile
+}
+
+// --- 私有实现 ---
+
+// extractBits 把 x 中 mask 为 1 的各位按从低到高的顺序压缩到结果的低位（软件实现的 PEXT）.
+func extractBits(x, mask int) int {
+	out, pos := 0, 0
+	for mask != 0 {
+		low := mask & -mask
+		if x&low != 0 {
+			out |= 1 << uint(pos)
+		}
+		pos++
+		mask &^= low
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package booleancore
+
+import (
+	"math/bits"
+	"testing"
+)
+
+// TestPropagationCriterion 检查 PC、SAC 与雪崩表
+func TestPropagationCriterion(t *testing.T) {
+	cases := []struct {
+		n   int
+		anf string
+		pc  int
+	}{
+		{6, "x0*x1 + x2*x3 + x4*x5", 6}, // bent 函数满足 PC(n)
+		{4, "x0 + x1*x2", 0},            // 有线性结构
+		{3, "x0*x1 + x1*x2 + x0*x2", 2}, // 择多函数：同时翻转三位时输出必然改变
+		{5, "x0*x1 + x2*x3", 0},         // x4 方向的导数为常数
+	}
+	for _, tc := range cases {
+		f, _ := NewFromANF(tc.n, tc.anf)
+		if got := f.PropagationCriterion(); got != tc.pc || got != bruteForcePC(f) {
+			t.Errorf("%s: PropagationCriterion() = %d, 期望 %d", tc.anf, got, tc.pc)
+		}
+	}
+
+	maj, _ := NewFromANF(3, "x0*x1 + x1*x2 + x0*x2")
+	if !maj.IsSAC() || maj.PropagationCriterion() != 2 {
+		t.Errorf("3 元择多函数满足 PC(2) 但不满足 PC(3), 实际 PC=%d", maj.PropagationCriterion())
+	}
+	bent, _ := NewFromANF(6, "x0*x1 + x2*x3 + x4*x5")
+	if bent.PropagationCriterion() != 6 {
+		t.Errorf("bent 函数应满足 PC(6), 实际 %d", bent.PropagationCriterion())
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		f := randomFunction(t, 6, seed)
+		if got, want := f.PropagationCriterion(), bruteForcePC(f); got != want {
+			t.Fatalf("seed=%d: PropagationCriterion() = %d, 期望 %d", seed, got, want)
+		}
+	}
+
+	profile := bent.AvalancheProfile()
+	for _, entry := range profile {
+		if entry.Directions != binomial(6, entry.Weight) || entry.Satisfied != entry.Directions ||
+			entry.MaxAbsAC != 0 || entry.MinFlipProbability != 0.5 || entry.MaxFlipProbability != 0.5 {
+			t.Errorf("bent 函数的雪崩表项应完全平衡, 实际 %+v", entry)
+		}
+	}
+	affine, _ := NewFromANF(4, "x0 + x2 + 1")
+	if entry := affine.AvalancheProfile()[0]; entry.Satisfied != 0 || entry.MaxAbsAC != 16 ||
+		entry.MinFlipProbability != 0 || entry.MaxFlipProbability != 1 {
+		t.Errorf("仿射函数的导数都是常数, 实际 %+v", entry)
+	}
+}
+
+// TestPCOfOrder 将 k 阶 PC(l) 与逐个构造子函数的结果对比
+func TestPCOfOrder(t *testing.T) {
+	functions := []*BooleanFunction{randomFunction(t, 5, 1), randomFunction(t, 5, 2)}
+	for _, anf := range []string{"x0*x1 + x2*x3 + x4*x5", "x0*x1*x2 + x0*x3 + x1*x4 + x2*x5 + x3*x4*x5", "x0*x1 + x1*x2 + x2*x3 + x3*x4 + x4*x5 + x5*x0"} {
+		f, _ := NewFromANF(6, anf)
+		functions = append(functions, f)
+	}
+	for i, f := range functions {
+		for l := 0; l <= 2; l++ {
+			for k := 0; k <= 2; k++ {
+				got, err := f.SatisfiesPCOfOrder(l, k)
+				if err != nil {
+					t.Fatalf("SatisfiesPCOfOrder(%d, %d) error: %v", l, k, err)
+				}
+				if want := bruteForcePCOfOrder(t, f, l, k); got != want {
+					t.Errorf("函数 %d: SatisfiesPCOfOrder(%d, %d) = %v, 期望 %v", i, l, k, got, want)
+				}
+			}
+		}
+	}
+	// 循环二次 bent 函数满足 1 阶 PC(2)，但不满足 2 阶 SAC
+	cyclic := functions[4]
+	if ok, _ := cyclic.SatisfiesPCOfOrder(2, 1); !ok {
+		t.Error("循环二次函数应满足 1 阶 PC(2)")
+	}
+	if ok, _ := cyclic.SatisfiesPCOfOrder(1, 2); ok {
+		t.Error("循环二次函数不应满足 2 阶 SAC")
+	}
+	if _, err := functions[0].SatisfiesPCOfOrder(1, 6); err == nil {
+		t.Error("阶数越界应返回错误")
+	}
+}
+
+// bruteForcePC 逐方向由导数的重量求 PC(l) 的最大 l
+func bruteForcePC(f *BooleanFunction) int {
+	pc := f.N()
+	for a := 1; a < 1<<f.N(); a++ {
+		d, _ := f.Derivative(a)
+		if 2*d.HammingWeight() != 1<<f.N() && bits.OnesCount(uint(a))-1 < pc {
+			pc = bits.OnesCount(uint(a)) - 1
+		}
+	}
+	return pc
+}
+
+// bruteForcePCOfOrder 固定每个不超过 k 个变量的子集与取值，构造子函数并检查 PC(l)
+func bruteForcePCOfOrder(t *testing.T, f *BooleanFunction, l, k int) bool {
+	n := f.N()
+	tt := f.TruthTable()
+	for s := 0; s < 1<<n; s++ {
+		fixed := bits.OnesCount(uint(s))
+		if fixed > k || fixed == n {
+			continue
+		}
+		var free []int
+		for i := 0; i < n; i++ {
+			if (s>>i)&1 == 0 {
+				free = append(free, i)
+			}
+		}
+		for c := 0; c < 1<<n; c++ {
+			if c&^s != 0 {
+				continue
+			}
+			sub := make([]byte, 1<<len(free))
+			for y := range sub {
+				x := c
+				for j, v := range free {
+					x |= ((y >> j) & 1) << v
+				}
+				sub[y] = tt[x]
+			}
+			g, err := NewFromTruthTable(sub)
+			if err != nil {
+				t.Fatalf("NewFromTruthTable error: %v", err)
+			}
+			if !g.SatisfiesPC(min(l, len(free))) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// binomial 返回组合数 C(n, k)
+func binomial(n, k int) int {
+	r := 1
+	for i := 0; i < k; i++ {
+		r = r * (n - i) / (i + 1)
+	}
+	return r
+}
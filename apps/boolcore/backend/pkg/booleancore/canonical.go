@@ -0,0 +1,551 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+)
+
+// n ≤ 6 时仿射等价的穷举判定：真值表只有一个 uint64，
+// 在仿射群上用回溯搜索字典序最小的规范形式。
+//
+// 变换记为 g(x) = f(Ax ⊕ b) ⊕ l(x)，A 按列 cols[j] = A·e_j 给出。按 x 递增的顺序，
+// 先选 b（决定 g(0)），再依次选第 k 列（决定 g 在 [2^k, 2^{k+1}) 上的取值），
+// 这一段只依赖 b 与前 k+1 列，因此可以逐段与当前最优解比较并剪枝。
+// l 不参与分支：扩展仿射等价中取 l 使 g(0) = g(e_j) = 0，仿射等价中只取常数使 g(0) = 0。
+//
+// 每个位置的比较键除 g(x) 外还包含两个仿射不变量，作为更强的剪枝：
+//   - 方向 Ax 上的自相关值 r_f(Ax)（扩展仿射等价时取绝对值），因为 r_g(a) = ±r_f(Aa)；
+//   - 点 Ax⊕b 上的二阶导数计数 s_f(Ax⊕b) = #{(u,v) : D_u D_v f(Ax⊕b) = 1}，它不受加仿射函数影响。
+// 不变量相同的函数在同一位置的键相同，所以最小键序列仍是等价类的不变量。
+
+// maxExhaustiveN 是穷举判定支持的最大变量个数.
+const maxExhaustiveN = 6
+
+// errFrameFound 在目标模式下找到解时用于结束回溯.
+var errFrameFound = errors.New("frame found")
+
+// frameSearch 在 m 元函数 f 的仿射标架上回溯搜索.
+// target 为 nil 时求字典序最小的键序列，否则求与 target 完全相同的键序列。
+type frameSearch struct {
+	ctx      context.Context
+	m, size  int
+	f        uint64
+	extended bool
+	dir      [64]int64
+	pt       [64]int64
+	target   []int64
+
+	best    [64]int64
+	hasBest bool
+	bestGen int
+	bestB   int
+	bestCol [maxExhaustiveN]int
+
+	bestYv [64]int
+	autos  [][64]uint8 // 已发现的自同构（点的置换），用于合并等价的候选
+	jumpTo int         // ≥ 0 时回跳到该深度（深度 0 为 b 的选择，深度 j+1 为第 j 列的选择）
+
+	b     int
+	cols  [maxExhaustiveN]int
+	yv    [64]int    // yv[x] = Ax ⊕ b
+	lin   [64]uint64 // lin[x] = l(x)
+	keys  [64]int64
+	nodes int
+}
+
+func newFrameSearch(ctx context.Context, f uint64, m int, extended bool) *frameSearch {
+	s := &frameSearch{ctx: ctx, m: m, size: 1 << uint(m), f: f, extended: extended}
+	s.dir, s.pt = smallLabels(f, m, extended)
+	return s
+}
+
+// smallLabels 计算 m 元函数（m ≤ 6）各方向的自相关标签与各点的二阶导数计数.
+func smallLabels(f uint64, m int, extended bool) (dir, pt [64]int64) {
+	size := 1 << uint(m)
+	mask := lastWordMask(m)
+	for a := 0; a < size; a++ {
+		r := int64(size - 2*bits.OnesCount64((f^permuteWordXor(f, a))&mask))
+		if extended && r < 0 {
+			r = -r
+		}
+		dir[a] = r
+	}
+	for u := 1; u < size; u++ {
+		du := (f ^ permuteWordXor(f, u)) & mask
+		for v := u + 1; v < size; v++ {
+			duv := (du ^ permuteWordXor(du, v)) & mask
+			for duv != 0 {
+				pt[bits.TrailingZeros64(duv)]++
+				duv &= duv - 1
+			}
+		}
+	}
+	return dir, pt
+}
+
+func (s *frameSearch) bit(y int) uint64 {
+	return (s.f >> uint(y)) & 1
+}
+
+func (s *frameSearch) key(x int) int64 {
+	y := s.yv[x]
+	return (s.dir[y^s.b]+128)<<24 | s.pt[y]<<1 | int64(s.bit(y)^s.lin[x])
+}
+
+// run 执行搜索. 目标模式下找到解时返回 true.
+func (s *frameSearch) run() (bool, error) {
+	minPt := int64(-1)
+	for b := 0; b < s.size; b++ {
+		if minPt < 0 || s.pt[b] < minPt {
+			minPt = s.pt[b]
+		}
+	}
+	var orbits pointOrbits
+	var explored []int
+	for b := 0; b < s.size; b++ {
+		if s.target == nil && s.pt[b] != minPt {
+			continue
+		}
+		if s.target == nil && orbits.covered(s, nil, b, explored) {
+			continue
+		}
+		explored = append(explored, b)
+		s.b = b
+		s.yv[0] = b
+		s.lin[0] = s.bit(b)
+		s.keys[0] = s.key(0)
+		if s.target != nil && s.keys[0] != s.target[0] {
+			continue
+		}
+		s.jumpTo = -1
+		if err := s.search(0, 1, false); err != nil { // 方向 A·0 = 0 已占用
+			if err == errFrameFound {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// search 在位置 [0, 2^k) 已确定时选择第 k 列. span 记录已用方向 A·x（x < 2^k）.
+// less 表示当前前缀已严格小于最优解的前缀.
+func (s *frameSearch) search(k int, span uint64, less bool) error {
+	s.nodes++
+	if s.nodes&4095 == 0 {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	lo := 1 << uint(k)
+	if k == s.m {
+		if s.target != nil {
+			s.bestB, s.bestCol = s.b, s.cols
+			return errFrameFound
+		}
+		if !s.hasBest || less {
+			copy(s.best[:s.size], s.keys[:s.size])
+			s.hasBest = true
+			s.bestGen++
+			s.bestB, s.bestCol = s.b, s.cols
+			s.bestYv = s.yv
+			return nil
+		}
+		if len(s.autos) < maxStoredAutomorphisms {
+			var sigma [64]uint8
+			for x := 0; x < s.size; x++ {
+				sigma[s.bestYv[x]] = uint8(s.yv[x])
+			}
+			s.autos = append(s.autos, sigma)
+		}
+		// 与最优解相同：两个标架之差是 f 的自同构，它把最优解所在的子树映到当前子树上，
+		// 两条路径分叉处以下的当前子树都不必再搜索
+		s.jumpTo = 0
+		if s.b == s.bestB {
+			j := 0
+			for j < s.m-1 && s.cols[j] == s.bestCol[j] {
+				j++
+			}
+			s.jumpTo = j + 1
+		}
+		return nil
+	}
+
+	// 位置 2^k 的键只取决于新列，先求出所有候选列中的最小键（或目标键）
+	headKey := func(col int) int64 {
+		y := s.b ^ col
+		g := s.bit(y) ^ s.lin[0]
+		if s.extended {
+			g = 0
+		}
+		return (s.dir[col]+128)<<24 | s.pt[y]<<1 | int64(g)
+	}
+	var want int64
+	if s.target != nil {
+		want = s.target[lo]
+	} else {
+		want = -1
+		for col := 1; col < s.size; col++ {
+			if span&(1<<uint(col)) == 0 {
+				if hk := headKey(col); want < 0 || hk < want {
+					want = hk
+				}
+			}
+		}
+		if s.hasBest && !less && want > s.best[lo] {
+			return nil
+		}
+	}
+
+	var orbits pointOrbits
+	var explored []int
+	for col := 1; col < s.size; col++ {
+		if span&(1<<uint(col)) != 0 || headKey(col) != want {
+			continue
+		}
+		if s.target == nil && orbits.covered(s, s.yv[:lo], s.b^col, explored) {
+			continue
+		}
+		explored = append(explored, s.b^col)
+		s.cols[k] = col
+		y := s.b ^ col
+		headLin := s.lin[0]
+		if s.extended {
+			headLin = s.bit(y)
+		}
+		ck := headLin ^ s.lin[0]
+		newSpan := span
+		cmp := 0
+		if less {
+			cmp = -1
+		}
+		for xl := 0; xl < lo; xl++ {
+			x := lo | xl
+			s.yv[x] = s.yv[xl] ^ col
+			s.lin[x] = s.lin[xl] ^ ck
+			newSpan |= 1 << uint(s.yv[x]^s.b)
+			s.keys[x] = s.key(x)
+			if cmp != 0 {
+				continue
+			}
+			var ref int64
+			if s.target != nil {
+				ref = s.target[x]
+			} else if s.hasBest {
+				ref = s.best[x]
+			} else {
+				cmp = -1
+				continue
+			}
+			if s.keys[x] < ref {
+				cmp = -1
+			} else if s.keys[x] > ref {
+				cmp = 1
+			}
+		}
+		if cmp > 0 || (s.target != nil && cmp != 0) {
+			continue
+		}
+		gen := s.bestGen
+		if err := s.search(k+1, newSpan, cmp < 0); err != nil {
+			return err
+		}
+		if s.jumpTo >= 0 {
+			if s.jumpTo <= k {
+				return nil
+			}
+			s.jumpTo = -1 // 回跳到本层，继续下一个候选列
+		}
+		if s.bestGen != gen {
+			less = false // 新的最优解就在本节点之下，之后的兄弟与它比较
+		}
+	}
+	return nil
+}
+
+// maxStoredAutomorphisms 是搜索中保存的自同构个数上限，超出后只是少剪一些枝.
+const maxStoredAutomorphisms = 256
+
+// pointOrbits 是固定当前路径上所有点的已知自同构生成的群在点集上的轨道，
+// 同一轨道中的候选对应的子树互为像，只需搜索其中一个。
+type pointOrbits struct {
+	built  int // 构建时的自同构个数，-1 表示尚未构建
+	parent [64]uint8
+	init   bool
+}
+
+func (o *pointOrbits) find(p int) int {
+	for int(o.parent[p]) != p {
+		o.parent[p] = o.parent[o.parent[p]]
+		p = int(o.parent[p])
+	}
+	return p
+}
+
+// covered 判断点 p 是否与 explored 中的某个点在同一轨道上，fixed 为必须保持不动的点.
+func (o *pointOrbits) covered(s *frameSearch, fixed []int, p int, explored []int) bool {
+	if len(explored) == 0 || len(s.autos) == 0 {
+		return false
+	}
+	if !o.init || o.built != len(s.autos) {
+		for i := range o.parent {
+			o.parent[i] = uint8(i)
+		}
+	next:
+		for _, sigma := range s.autos {
+			for _, y := range fixed {
+				if int(sigma[y]) != y {
+					continue next
+				}
+			}
+			for y := 0; y < s.size; y++ {
+				if a, b := o.find(y), o.find(int(sigma[y])); a != b {
+					o.parent[a] = uint8(b)
+				}
+			}
+		}
+		o.init, o.built = true, len(s.autos)
+	}
+	root := o.find(p)
+	for _, q := range explored {
+		if o.find(q) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// smallCanonical 返回 m 元函数 f（m ≤ 6）的规范形式及把 f 变为它的标架 (cols, b)：
+// canon(x) = f(Ax ⊕ b) ⊕ l(x)，l 在扩展仿射等价中为仿射函数，否则为常数。
+// 先按线性结构降维，退化的二次函数直接取标准型，其余情形回溯求最小键序列。
+func smallCanonical(ctx context.Context, f uint64, m int, extended bool) (canon uint64, cols []int, b int, err error) {
+	size := 1 << uint(m)
+	mask := lastWordMask(m)
+	f &= mask
+
+	// 线性结构 V = {a : D_a f 为常数}，常数值 c(a) 在 V 上是线性的
+	var structures, flips []int
+	for a := 1; a < size; a++ {
+		d := (f ^ permuteWordXor(f, a)) & mask
+		switch d {
+		case 0:
+			structures = append(structures, a)
+		case mask:
+			structures = append(structures, a)
+			flips = append(flips, a)
+		}
+	}
+	if len(structures) > 0 {
+		return reduceByLinearStructures(ctx, f, m, extended, structures, flips)
+	}
+
+	s := newFrameSearch(ctx, f, m, extended)
+	if packedANFDegree([]uint64{anfWord(f, m)}, m) == 2 {
+		// 没有线性结构的二次函数都等价于 x0x1 ⊕ x2x3 ⊕ ...，直接搜索到它的标架
+		q := quadraticNormalForm(m)
+		t := newFrameSearch(ctx, q, m, extended)
+		target := make([]int64, size)
+		for x := 0; x < size; x++ {
+			t.yv[x] = x
+			target[x] = t.key(x)
+		}
+		s.target = target
+		found, err := s.run()
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		if !found {
+			return 0, nil, 0, errors.New("internal error: quadratic normal form not reached")
+		}
+		return q, append([]int(nil), s.bestCol[:m]...), s.bestB, nil
+	}
+
+	if _, err := s.run(); err != nil {
+		return 0, nil, 0, err
+	}
+	for x := 0; x < size; x++ {
+		canon |= uint64(s.best[x]&1) << uint(x)
+	}
+	return canon, append([]int(nil), s.bestCol[:m]...), s.bestB, nil
+}
+
+// reduceByLinearStructures 处理有线性结构的函数：取 V 的基与一个补空间 W 的基组成标架，
+// f(Σx'_i w_i ⊕ Σx”_j v_j) = h(x') ⊕ c·x”，规范形式为 h 的规范形式（仿射等价且 c ≠ 0 时再加上 x_{dim W}）.
+// 仿射等价中若 c ≠ 0，补空间的选取会给 h 加上任意线性函数，因此 h 按扩展仿射等价规范化。
+func reduceByLinearStructures(ctx context.Context, f uint64, m int, extended bool, structures, flips []int) (uint64, []int, int, error) {
+	// V 的基，c ≠ 0 时让第一个基向量满足 c = 1、其余基向量满足 c = 0
+	var basis []int
+	hasFlip := len(flips) > 0
+	if hasFlip {
+		basis = append(basis, flips[0])
+	}
+	for _, a := range structures {
+		if !inSpan(basis, a) {
+			basis = append(basis, a)
+		}
+	}
+	if hasFlip {
+		isFlip := make(map[int]bool, len(flips))
+		for _, a := range flips {
+			isFlip[a] = true
+		}
+		for i := 1; i < len(basis); i++ {
+			if isFlip[basis[i]] {
+				basis[i] ^= basis[0]
+			}
+		}
+	}
+	// 用单位向量补全得到 W 的基
+	var complement []int
+	all := append([]int(nil), basis...)
+	for i := 0; i < m && len(all) < m; i++ {
+		if !inSpan(all, 1<<uint(i)) {
+			complement = append(complement, 1<<uint(i))
+			all = append(all, 1<<uint(i))
+		}
+	}
+	w := len(complement)
+	frame := append(append([]int(nil), complement...), basis...)
+
+	var h uint64
+	for x := 0; x < 1<<uint(w); x++ {
+		h |= ((f >> uint(applyColumns(complement, x))) & 1) << uint(x)
+	}
+	subExtended := extended || hasFlip
+	hCanon, hCols, hB := uint64(0), []int(nil), 0
+	if w > 0 {
+		var err error
+		hCanon, hCols, hB, err = smallCanonical(ctx, h, w, subExtended)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+	}
+
+	// h 的标架按块对角提升到 m 元，仿射等价且 c ≠ 0 时用 x_w 吸收 h 变换后多出的仿射项
+	lifted := make([]int, m)
+	copy(lifted, hCols)
+	for j := w; j < m; j++ {
+		lifted[j] = 1 << uint(j)
+	}
+	liftedB := hB
+	if !extended && hasFlip && w > 0 {
+		hT := transformSmall(h, w, hCols, hB)
+		c, d := affineDifference(hT^hCanon, w)
+		for j := 0; j < w; j++ {
+			if (c>>uint(j))&1 == 1 {
+				lifted[j] |= 1 << uint(w)
+			}
+		}
+		liftedB |= int(d) << uint(w)
+	}
+
+	var canon uint64
+	for x := 0; x < 1<<uint(m); x++ {
+		v := (hCanon >> uint(x&(1<<uint(w)-1))) & 1
+		if !extended && hasFlip {
+			v ^= uint64(x>>uint(w)) & 1
+		}
+		canon |= v << uint(x)
+	}
+	return canon, composeColumns(frame, lifted), applyColumns(frame, liftedB), nil
+}
+
+// quadraticNormalForm 返回 m 元（m 为偶数）二次型 x0x1 ⊕ x2x3 ⊕ ... 的真值表.
+func quadraticNormalForm(m int) uint64 {
+	var q uint64
+	for x := 0; x < 1<<uint(m); x++ {
+		v := 0
+		for i := 0; i+1 < m; i += 2 {
+			v ^= (x >> uint(i)) & (x >> uint(i+1)) & 1
+		}
+		q |= uint64(v) << uint(x)
+	}
+	return q
+}
+
+// anfWord 返回单字真值表的 ANF 系数.
+func anfWord(f uint64, m int) uint64 {
+	words := []uint64{f}
+	fmtPackedInplace(words, m)
+	return words[0]
+}
+
+// transformSmall 返回 f(Ax ⊕ b) 的单字真值表.
+func transformSmall(f uint64, m int, cols []int, b int) uint64 {
+	var g uint64
+	for x := 0; x < 1<<uint(m); x++ {
+		g |= ((f >> uint(applyColumns(cols, x)^b)) & 1) << uint(x)
+	}
+	return g
+}
+
+// affineDifference 从 e 在 0 与各单位向量处的取值读出仿射函数 c·x ⊕ d 的系数.
+func affineDifference(e uint64, m int) (c int, d byte) {
+	d = byte(e & 1)
+	for j := 0; j < m; j++ {
+		if byte((e>>uint(1<<uint(j)))&1) != d {
+			c |= 1 << uint(j)
+		}
+	}
+	return c, d
+}
+
+// applyColumns 计算 Σ x_j cols[j].
+func applyColumns(cols []int, x int) int {
+	y := 0
+	for j, col := range cols {
+		if (x>>uint(j))&1 == 1 {
+			y ^= col
+		}
+	}
+	return y
+}
+
+// composeColumns 返回 A1·A2 的列.
+func composeColumns(a1, a2 []int) []int {
+	out := make([]int, len(a2))
+	for j, col := range a2 {
+		out[j] = applyColumns(a1, col)
+	}
+	return out
+}
+
+// invertColumns 返回可逆矩阵 A 的逆矩阵的列（m ≤ 6，查表求逆）.
+func invertColumns(cols []int) []int {
+	m := len(cols)
+	inv := make([]int, m)
+	for x := 0; x < 1<<uint(m); x++ {
+		y := applyColumns(cols, x)
+		if y != 0 && y&(y-1) == 0 {
+			inv[bits.TrailingZeros(uint(y))] = x
+		}
+	}
+	return inv
+}
+
+// inSpan 判断 a 是否在 vectors 张成的子空间中.
+func inSpan(vectors []int, a int) bool {
+	var basis [64]int // basis[i] 的最高位为 i
+	for _, v := range vectors {
+		for i := 63; i >= 0 && v != 0; i-- {
+			if (v>>uint(i))&1 == 0 {
+				continue
+			}
+			if basis[i] == 0 {
+				basis[i] = v
+				break
+			}
+			v ^= basis[i]
+		}
+	}
+	for i := 63; i >= 0 && a != 0; i-- {
+		if (a>>uint(i))&1 == 1 {
+			if basis[i] == 0 {
+				return false
+			}
+			a ^= basis[i]
+		}
+	}
+	return a == 0
+}
@@ -0,0 +1,171 @@
+package booleancore
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// 经典的初等构造，每个构造都返回新的 BooleanFunction.
+// 涉及两组变量 (x, y) 的构造（n = 2m）中 x 占输入的低 m 位、y 占高 m 位，
+// 即真值表下标为 x | y<<m；域元素与 m 维向量的对应关系见 gf2m.go。
+//
+//   - MaioranaMcFarland：f(x, y) = x·π(y) ⊕ g(y)，π 为 F_2^m 上的置换，bent；
+//   - PartialSpreadAP：Dillon 的 PS_ap 类 f(x, y) = g(x·y^{2^m-2})，g(0) = 0 且 wt(g) = 2^{m-1}，bent；
+//   - Rothaus：由三个 n 元 bent 函数得到 n+2 元 bent 函数；
+//   - Majority：择多函数，代数免疫度 ⌈n/2⌉；
+//   - CarletFeng：GF(2^n) 上支撑为连续 2^{n-1}-1 个本原元幂加上 0 的平衡函数，代数免疫度 ⌈n/2⌉；
+//   - TuDeng：g 的支撑为连续 2^{m-1} 个本原元幂的 PS_ap 函数，在 Tu–Deng 组合猜想下代数免疫度为 m。
+
+// MaxConstructedN 是构造函数直接生成的最大变量个数（真值表 2^24 位，即 2 MiB）.
+const MaxConstructedN = 24
+
+// MaioranaMcFarland 返回 2m 元 bent 函数 f(x, y) = x·π(y) ⊕ g(y).
+// perm 是 {0, ..., 2^m-1} 上的置换（π(y) = perm[y]），g 为 m 元函数，nil 表示 g = 0。
+func MaioranaMcFarland(m int, perm []int, g *BooleanFunction) (*BooleanFunction, error) {
+	if m < 1 || 2*m > MaxConstructedN {
+		return nil, fmt.Errorf("m must be in [1, %d], got %d", MaxConstructedN/2, m)
+	}
+	if len(perm) != 1<<uint(m) {
+		return nil, fmt.Errorf("permutation must have 2^%d = %d entries, got %d", m, 1<<uint(m), len(perm))
+	}
+	if err := validatePermutation(perm); err != nil {
+		return nil, err
+	}
+	gt, err := subfunctionTable(g, m, "g")
+	if err != nil {
+		return nil, err
+	}
+	low := 1<<uint(m) - 1
+	return newFromPredicate(2*m, func(z int) byte {
+		x, y := z&low, z>>uint(m)
+		return byte(bits.OnesCount(uint(x&perm[y]))&1) ^ gt[y]
+	}), nil
+}
+
+// PartialSpreadAP 返回 2m 元 PS_ap bent 函数 f(x, y) = g(x·y^{2^m-2})（约定 0^{-1} = 0）.
+// g 为 m 元函数，要求 g(0) = 0 且 wt(g) = 2^{m-1}；f 在每个 m 维子空间 {(a·y, y)} 上为常数。
+func PartialSpreadAP(m int, g *BooleanFunction) (*BooleanFunction, error) {
+	if m < 1 || 2*m > MaxConstructedN {
+		return nil, fmt.Errorf("m must be in [1, %d], got %d", MaxConstructedN/2, m)
+	}
+	if g == nil {
+		return nil, errors.New("g is required")
+	}
+	gt, err := subfunctionTable(g, m, "g")
+	if err != nil {
+		return nil, err
+	}
+	if gt[0] != 0 || g.HammingWeight() != 1<<uint(m-1) {
+		return nil, fmt.Errorf("g must satisfy g(0) = 0 and have weight 2^%d", m-1)
+	}
+	return partialSpread(m, gt)
+}
+
+// TuDeng 返回 2m 元 Tu–Deng bent 函数：g 的支撑为 {α^s, α^{s+1}, ..., α^{s+2^{m-1}-1}} 的 PS_ap 函数.
+// 与所有 PS_ap 函数一样其代数次数为 m，在 Tu–Deng 组合猜想（已对 m ≤ 29 验证）成立时代数免疫度也达到 m。
+func TuDeng(m, s int) (*BooleanFunction, error) {
+	if m < 1 || 2*m > MaxConstructedN {
+		return nil, fmt.Errorf("m must be in [1, %d], got %d", MaxConstructedN/2, m)
+	}
+	F, err := NewGF2m(m)
+	if err != nil {
+		return nil, err
+	}
+	gt := make([]byte, F.Size())
+	for i := 0; i < 1<<uint(m-1); i++ {
+		gt[F.Exp(s+i)] = 1
+	}
+	return partialSpread(m, gt)
+}
+
+// Rothaus 返回 n+2 元 bent 函数
+// f(x, x_n, x_{n+1}) = AB ⊕ BC ⊕ CA ⊕ (A ⊕ B)x_n ⊕ (A ⊕ C)x_{n+1} ⊕ x_n x_{n+1}，
+// 要求 A、B、C 与 A ⊕ B ⊕ C 都是 n 元 bent 函数。
+func Rothaus(a, b, c *BooleanFunction) (*BooleanFunction, error) {
+	if a.n != b.n || a.n != c.n {
+		return nil, fmt.Errorf("number of variables mismatch: %d, %d, %d", a.n, b.n, c.n)
+	}
+	if a.n+2 > MaxConstructedN {
+		return nil, fmt.Errorf("result would have %d variables, at most %d are supported", a.n+2, MaxConstructedN)
+	}
+	abc, _ := a.Xor(b)
+	abc, _ = abc.Xor(c)
+	if !a.IsBent() || !b.IsBent() || !c.IsBent() || !abc.IsBent() {
+		return nil, errors.New("A, B, C and A+B+C must all be bent")
+	}
+	n := a.n
+	at, bt, ct := a.TruthTable(), b.TruthTable(), c.TruthTable()
+	low := 1<<uint(n) - 1
+	return newFromPredicate(n+2, func(z int) byte {
+		x, u, v := z&low, byte(z>>uint(n))&1, byte(z>>uint(n+1))&1
+		A, B, C := at[x], bt[x], ct[x]
+		return A&B ^ B&C ^ C&A ^ (A^B)&u ^ (A^C)&v ^ u&v
+	}), nil
+}
+
+// Majority 返回 n 元择多函数：wt(x) > n/2 时取 1，代数免疫度为 ⌈n/2⌉（n 为奇数时平衡）.
+func Majority(n int) (*BooleanFunction, error) {
+	if n < 1 || n > MaxConstructedN {
+		return nil, fmt.Errorf("n must be in [1, %d], got %d", MaxConstructedN, n)
+	}
+	return newFromPredicate(n, func(x int) byte {
+		if 2*bits.OnesCount(uint(x)) > n {
+			return 1
+		}
+		return 0
+	}), nil
+}
+
+// CarletFeng 返回 GF(2^n) 上的 Carlet–Feng 函数，支撑为 {0, α^s, α^{s+1}, ..., α^{s+2^{n-1}-2}}.
+// 它是平衡函数，代数次数 n-1，代数免疫度 ⌈n/2⌉，非线性度较高；2 ≤ n ≤ 16。
+func CarletFeng(n, s int) (*BooleanFunction, error) {
+	if n < 2 || n > MaxGF2mDegree {
+		return nil, fmt.Errorf("n must be in [2, %d], got %d", MaxGF2mDegree, n)
+	}
+	F, err := NewGF2m(n)
+	if err != nil {
+		return nil, err
+	}
+	packed := make([]uint64, packedWords(n))
+	setBit := func(x int) { packed[x>>6] |= 1 << uint(x&63) }
+	setBit(0)
+	for i := 0; i < 1<<uint(n-1)-1; i++ {
+		setBit(F.Exp(s + i))
+	}
+	return &BooleanFunction{n: n, packedTruthTable: packed}, nil
+}
+
+// --- 私有实现 ---
+
+// newFromPredicate 按 value(x) 逐点生成 n 元函数的打包真值表.
+func newFromPredicate(n int, value func(x int) byte) *BooleanFunction {
+	packed := make([]uint64, packedWords(n))
+	for x := 0; x < 1<<uint(n); x++ {
+		packed[x>>6] |= uint64(value(x)&1) << uint(x&63)
+	}
+	return &BooleanFunction{n: n, packedTruthTable: packed}
+}
+
+// subfunctionTable 检查 g 为 m 元函数并返回其真值表，g 为 nil 时返回全 0 的真值表.
+func subfunctionTable(g *BooleanFunction, m int, name string) ([]byte, error) {
+	if g == nil {
+		return make([]byte, 1<<uint(m)), nil
+	}
+	if g.n != m {
+		return nil, fmt.Errorf("%s must have %d variables, got %d", name, m, g.n)
+	}
+	return g.TruthTable(), nil
+}
+
+// partialSpread 返回 f(x, y) = g(x·y^{2^m-2})，gt 为 g 的真值表，调用方保证 2m ≤ MaxConstructedN.
+func partialSpread(m int, gt []byte) (*BooleanFunction, error) {
+	F, err := NewGF2m(m)
+	if err != nil {
+		return nil, err
+	}
+	low := F.Size() - 1
+	return newFromPredicate(2*m, func(z int) byte {
+		return gt[F.Mul(z&low, F.Inv(z>>uint(m)))]
+	}), nil
+}
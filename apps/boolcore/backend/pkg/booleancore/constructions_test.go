@@ -0,0 +1,156 @@
+package booleancore
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestGF2m 检查本原多项式表与域运算
+func TestGF2m(t *testing.T) {
+	for m := 1; m <= MaxGF2mDegree; m++ {
+		F, err := NewGF2m(m)
+		if err != nil {
+			t.Fatalf("NewGF2m(%d) error: %v", m, err)
+		}
+		// α 的幂遍历全部非零元素当且仅当多项式是本原的
+		seen := make([]bool, F.Size())
+		for i := 0; i < F.Size()-1; i++ {
+			a := F.Exp(i)
+			if a == 0 || seen[a] {
+				t.Fatalf("m=%d: z 不是本原元（α^%d 重复）", m, i)
+			}
+			seen[a] = true
+		}
+		traceWeight := 0
+		rng := rand.New(rand.NewSource(int64(m)))
+		for a := 0; a < F.Size(); a++ {
+			traceWeight += int(F.Trace(a))
+		}
+		if traceWeight != F.Size()/2 {
+			t.Errorf("m=%d: 迹函数应平衡, 重量 %d", m, traceWeight)
+		}
+		for trial := 0; trial < 50; trial++ {
+			a, b, c := rng.Intn(F.Size()), rng.Intn(F.Size()), rng.Intn(F.Size())
+			if F.Mul(a, b^c) != F.Mul(a, b)^F.Mul(a, c) || F.Mul(F.Mul(a, b), c) != F.Mul(a, F.Mul(b, c)) {
+				t.Fatalf("m=%d: 乘法不满足分配律或结合律", m)
+			}
+			if a != 0 && F.Mul(a, F.Inv(a)) != 1 {
+				t.Fatalf("m=%d: %d 的逆元错误", m, a)
+			}
+			if F.Pow(a, 3) != F.Mul(a, F.Mul(a, a)) || F.Pow(a, F.Size()-1) != min(a, 1) {
+				t.Fatalf("m=%d: Pow(%d) 错误", m, a)
+			}
+		}
+	}
+	if _, err := NewGF2m(17); err == nil {
+		t.Error("m 超出范围应返回错误")
+	}
+	F, _ := NewGF2m(4)
+	if F.Inv(0) != 0 || F.Exp(-1) != F.Inv(F.Exp(1)) {
+		t.Error("Inv(0) 应为 0，Exp 应支持负指数")
+	}
+	if _, err := F.Log(0); err == nil {
+		t.Error("Log(0) 应返回错误")
+	}
+}
+
+// TestBentConstructions 检查 MM、PS_ap、Tu–Deng 与 Rothaus 构造得到 bent 函数
+func TestBentConstructions(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for m := 1; m <= 4; m++ {
+		g := randomFunction(t, m, int64(m))
+		f, err := MaioranaMcFarland(m, rng.Perm(1<<m), g)
+		if err != nil {
+			t.Fatalf("MaioranaMcFarland(%d) error: %v", m, err)
+		}
+		if !f.IsBent() {
+			t.Errorf("m=%d: MM 函数应为 bent 函数", m)
+		}
+		if ok, _ := f.IsCompletedMM(); !ok {
+			t.Errorf("m=%d: MM 函数应属于 M#", m)
+		}
+
+		// g = Tr 满足 g(0) = 0 且重量 2^{m-1}
+		F, _ := NewGF2m(m)
+		tr := newFromPredicate(m, F.Trace)
+		ps, err := PartialSpreadAP(m, tr)
+		if err != nil {
+			t.Fatalf("PartialSpreadAP(%d) error: %v", m, err)
+		}
+		if !ps.IsBent() || ps.HammingWeight() != 1<<(2*m-1)-1<<(m-1) {
+			t.Errorf("m=%d: PS_ap 函数应为重量 2^{n-1}-2^{m-1} 的 bent 函数", m)
+		}
+	}
+
+	for m := 2; m <= 4; m++ {
+		f, err := TuDeng(m, 1)
+		if err != nil {
+			t.Fatalf("TuDeng(%d) error: %v", m, err)
+		}
+		ai, _, _ := f.AlgebraicImmunity(false)
+		if !f.IsBent() || ai != m {
+			t.Errorf("m=%d: Tu–Deng 函数应为 bent 且 AI=%d, 实际 bent=%v AI=%d", m, m, f.IsBent(), ai)
+		}
+		if f.AlgebraicDegree() != m {
+			t.Errorf("m=%d: Tu–Deng 函数的次数应为 n/2, 实际 %d", m, f.AlgebraicDegree())
+		}
+	}
+
+	a, _ := NewFromANF(4, "x0*x1 + x2*x3")
+	b, _ := NewFromANF(4, "x0*x1 + x2*x3 + x0")
+	c, _ := NewFromANF(4, "x0*x1 + x2*x3 + x1*x2")
+	f, err := Rothaus(a, b, c)
+	if err != nil {
+		t.Fatalf("Rothaus error: %v", err)
+	}
+	if f.N() != 6 || !f.IsBent() {
+		t.Error("Rothaus 构造应得到 6 元 bent 函数")
+	}
+
+	// 参数检查
+	if _, err := MaioranaMcFarland(2, []int{0, 1, 1, 3}, nil); err == nil {
+		t.Error("非置换应返回错误")
+	}
+	if _, err := PartialSpreadAP(2, a); err == nil {
+		t.Error("g 的变量个数不符应返回错误")
+	}
+	g, _ := NewFromTruthTable([]byte{1, 1, 0, 0})
+	if _, err := PartialSpreadAP(2, g); err == nil {
+		t.Error("g(0) ≠ 0 应返回错误")
+	}
+	notBent, _ := NewFromANF(4, "x0*x1*x2")
+	if _, err := Rothaus(a, b, notBent); err == nil {
+		t.Error("非 bent 输入应返回错误")
+	}
+}
+
+// TestAIOptimalConstructions 检查择多函数与 Carlet–Feng 函数的代数免疫度达到 ⌈n/2⌉
+func TestAIOptimalConstructions(t *testing.T) {
+	for n := 2; n <= 9; n++ {
+		want := (n + 1) / 2
+		maj, err := Majority(n)
+		if err != nil {
+			t.Fatalf("Majority(%d) error: %v", n, err)
+		}
+		if ai, _, _ := maj.AlgebraicImmunity(false); ai != want {
+			t.Errorf("n=%d: 择多函数的 AI 应为 %d, 实际 %d", n, want, ai)
+		}
+		if n%2 == 1 && !maj.IsBalanced() {
+			t.Errorf("n=%d: 奇数元择多函数应平衡", n)
+		}
+
+		for _, s := range []int{0, 3} {
+			cf, err := CarletFeng(n, s)
+			if err != nil {
+				t.Fatalf("CarletFeng(%d, %d) error: %v", n, s, err)
+			}
+			if ai, _, _ := cf.AlgebraicImmunity(false); ai != want || !cf.IsBalanced() || cf.AlgebraicDegree() != n-1 {
+				t.Errorf("n=%d s=%d: Carlet–Feng 函数应平衡、次数 n-1、AI=%d, 实际 AI=%d deg=%d",
+					n, s, want, ai, cf.AlgebraicDegree())
+			}
+		}
+	}
+	if _, err := CarletFeng(1, 0); err == nil {
+		t.Error("n=1 应返回错误")
+	}
+}
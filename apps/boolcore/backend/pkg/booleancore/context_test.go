@@ -0,0 +1,157 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// randomFunction 生成一个确定性的伪随机 n 元布尔函数
+func randomFunction(t testing.TB, n int, seed int64) *BooleanFunction {
+	rng := rand.New(rand.NewSource(seed))
+	tt := make([]byte, 1<<n)
+	for i := range tt {
+		tt[i] = byte(rng.Intn(2))
+	}
+	bf, err := NewFromTruthTable(tt)
+	if err != nil {
+		t.Fatalf("NewFromTruthTable error: %v", err)
+	}
+	return bf
+}
+
+// TestCtxVariantsDeadline 验证耗时方法在超时后及时返回 context.DeadlineExceeded
+func TestCtxVariantsDeadline(t *testing.T) {
+	bf := randomFunction(t, 14, 1)
+
+	calls := map[string]func(ctx context.Context) error{
+		"AlgebraicImmunityCtx": func(ctx context.Context) error {
+			_, _, err := bf.AlgebraicImmunityCtx(ctx, false)
+			return err
+		},
+		"FAICtx": func(ctx context.Context) error {
+			_, err := bf.FAICtx(ctx)
+			return err
+		},
+		"FAACtx": func(ctx context.Context) error {
+			_, err := bf.FAACtx(ctx)
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err := call(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("期望 context.DeadlineExceeded, 实际 %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("取消后返回过慢: %v", elapsed)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bf.AutocorrelationCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("AutocorrelationCtx 期望 context.Canceled, 实际 %v", err)
+	}
+	if _, err := bf.WalshHadamardTransformParallelCtx(ctx, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("WalshHadamardTransformParallelCtx 期望 context.Canceled, 实际 %v", err)
+	}
+}
+
+// TestCtxVariantsMatch 验证未取消时 Ctx 版本与普通版本结果一致
+func TestCtxVariantsMatch(t *testing.T) {
+	bf := randomFunction(t, 8, 2)
+	ctx := context.Background()
+
+	ai, _, _ := bf.AlgebraicImmunity(false)
+	aiCtx, _, err := bf.AlgebraicImmunityCtx(ctx, false)
+	if err != nil || ai != aiCtx {
+		t.Errorf("代数免疫度不一致: %d vs %d (%v)", ai, aiCtx, err)
+	}
+	fai, _ := bf.FAI()
+	faiCtx, err := bf.FAICtx(ctx)
+	if err != nil || fai != faiCtx {
+		t.Errorf("FAI 不一致: %d vs %d (%v)", fai, faiCtx, err)
+	}
+
+	wht := bf.WalshHadamardTransform()
+	par, err := bf.WalshHadamardTransformParallelCtx(ctx, 4)
+	if err != nil {
+		t.Fatalf("WalshHadamardTransformParallelCtx error: %v", err)
+	}
+	for i := range wht {
+		if wht[i] != par[i] {
+			t.Fatalf("并行 WHT 第 %d 项不一致: %d vs %d", i, wht[i], par[i])
+		}
+	}
+}
+
+// TestProgressReporting 验证进度回调报告当前次数、矩阵规模与消元行
+func TestProgressReporting(t *testing.T) {
+	bf := randomFunction(t, 10, 3)
+
+	var events []ProgressEvent
+	ctx := WithProgress(context.Background(), func(ev ProgressEvent) {
+		events = append(events, ev)
+	})
+	ai, _, err := bf.AlgebraicImmunityCtx(ctx, false)
+	if err != nil {
+		t.Fatalf("AlgebraicImmunityCtx error: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("未收到任何进度事件")
+	}
+	maxDegree := 0
+	for _, ev := range events {
+		if ev.Stage != StageAlgebraicImmunity {
+			t.Fatalf("阶段应为 %s, 实际 %s", StageAlgebraicImmunity, ev.Stage)
+		}
+		if ev.Target != "f" && ev.Target != "f+1" {
+			t.Errorf("未知的零化子目标 %q", ev.Target)
+		}
+		if ev.Rows <= 0 || ev.Cols <= 0 || ev.Row < 0 || ev.Row > ev.Rows || ev.Row > ev.Cols {
+			t.Errorf("矩阵信息不合法: %+v", ev)
+		}
+		if ev.Degree < maxDegree {
+			t.Errorf("次数应单调不减: %d 之后出现 %d", maxDegree, ev.Degree)
+		}
+		maxDegree = ev.Degree
+	}
+	// 方程数少于未知数时无需消元即可断定零化子存在，因此最后一次消元的次数可能是 AI-1
+	if maxDegree != ai && maxDegree != ai-1 {
+		t.Errorf("最后消元的次数应为 AI=%d 或 AI-1, 实际 %d", ai, maxDegree)
+	}
+	if last := events[len(events)-1]; !last.Done {
+		t.Errorf("最后一个事件应标记消元完成: %+v", last)
+	}
+
+	events = nil
+	if _, err := bf.FAICtx(ctx); err != nil {
+		t.Fatalf("FAICtx error: %v", err)
+	}
+	fastAttack := 0
+	for _, ev := range events {
+		if ev.Stage == StageFastAttack {
+			fastAttack++
+			if ev.Metric != "FAI" || ev.Degree < 1 || ev.DegreeH < ev.Degree {
+				t.Errorf("fastAttack 事件不合法: %+v", ev)
+			}
+		}
+	}
+	if fastAttack == 0 {
+		t.Error("FAI 计算未报告 fastAttack 阶段的进度")
+	}
+
+	// 未挂载回调时正常计算
+	if got, _, _ := bf.AlgebraicImmunity(false); got != ai {
+		t.Errorf("无回调时 AI 应为 %d, 实际 %d", ai, got)
+	}
+}
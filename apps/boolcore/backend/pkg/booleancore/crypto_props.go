@@ -1,7 +1,10 @@
 package booleancore
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/big"
 	"math/bits"
 	"strings"
 )
@@ -9,18 +12,8 @@ import (
 // Nonlinearity 计算非线性度.
 func (f *BooleanFunction) Nonlinearity() int64 {
 	// 公式: (2^(n-1) - max(|WHT_coeffs|)) / 2
-	wht := f.WalshHadamardTransform() // 调用方法自动处理缓存
-
-	var maxAbs int64 = 0
-	for _, v := range wht {
-		absV := v
-		if absV < 0 {
-			absV = -absV
-		}
-		if absV > maxAbs {
-			maxAbs = absV
-		}
-	}
+	wht := f.walsh() // 调用方法自动处理缓存
+	maxAbs := maxAbsValue(wht, 0)
 	// nonlinearity = 2^(n-1) - maxAbs/2
 	return (1 << (f.n - 1)) - maxAbs/2
 }
@@ -28,7 +21,7 @@ func (f *BooleanFunction) Nonlinearity() int64 {
 // CorrelationImmunity 计算相关免疫阶数.
 // 【重构】采用逐阶验证的清晰逻辑.
 func (f *BooleanFunction) CorrelationImmunity() int {
-	wht := f.WalshHadamardTransform()
+	wht := f.walsh()
 	maxOrder := 0
 	for t := 1; t <= f.n; t++ {
 		isTOrderImmune := true
@@ -62,7 +55,7 @@ func (f *BooleanFunction) IsBent() bool {
 	if f.n%2 != 0 {
 		return false
 	}
-	wht := f.WalshHadamardTransform()
+	wht := f.walsh()
 	// bent 函数的 WHT 的绝对值是常数2^(n/2)
 	expectedMagnitude := int64(1 << (f.n / 2))
 	for _, v := range wht {
@@ -78,65 +71,60 @@ func (f *BooleanFunction) IsBent() bool {
 }
 
 // SumOfSquareIndicator 计算平方和指标.
+// σ_f 最大可达 2^{3n}（仿射函数），n <= 20 时总能用 int64 精确表示；
+// 更大的 n 在结果超出 int64 时饱和为 math.MaxInt64，需要精确值时使用 SumOfSquareIndicatorBig.
 func (f *BooleanFunction) SumOfSquareIndicator() int64 {
-	ac := f.Autocorrelation()
-	var sum int64 = 0
-	for _, v := range ac {
-		sum += v * v
+	hi, lo := sumOfSquares128(f.autocorrelation())
+	if hi != 0 || lo > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(lo)
+}
+
+// SumOfSquareIndicatorBig 以任意精度整数返回平方和指标.
+func (f *BooleanFunction) SumOfSquareIndicatorBig() *big.Int {
+	hi, lo := sumOfSquares128(f.autocorrelation())
+	sum := new(big.Int).SetUint64(hi)
+	sum.Lsh(sum, 64)
+	return sum.Or(sum, new(big.Int).SetUint64(lo))
+}
+
+// sumOfSquares128 以 128 位无符号整数累加 Σ v²（|v| <= 2^n，n <= 31 时总和不超过 2^{3n} < 2^128）.
+func sumOfSquares128(values []int64) (hi, lo uint64) {
+	for _, v := range values {
+		if v < 0 {
+			v = -v
+		}
+		pHi, pLo := bits.Mul64(uint64(v), uint64(v))
+		var carry uint64
+		lo, carry = bits.Add64(lo, pLo, 0)
+		hi, _ = bits.Add64(hi, pHi, carry)
 	}
-	return sum
+	return hi, lo
 }
 
 // AbsoluteWalshSpectrum 计算绝对 Walsh 谱.
 // AbsoluteWalshSpectrum 计算绝对 Walsh 谱的频率分布.
 // 返回一个 map，键是谱值的绝对值，值是该绝对值出现的次数.
 func (f *BooleanFunction) AbsoluteWalshSpectrum() map[int64]int {
-	wht := f.WalshHadamardTransform()
-	distribution := make(map[int64]int)
-	for _, v := range wht {
-		absV := v
-		if absV < 0 {
-			absV = -absV
-		}
-		distribution[absV]++
-	}
-	return distribution
+	return absDistribution(f.walsh())
 }
 
 // AbsoluteAutocorrelation 计算绝对自相关谱的频率分布.
 func (f *BooleanFunction) AbsoluteAutocorrelation() map[int64]int {
-	ac := f.Autocorrelation()
-	distribution := make(map[int64]int)
-	for _, v := range ac {
-		absV := v
-		if absV < 0 {
-			absV = -absV
-		}
-		distribution[absV]++
-	}
-	return distribution
+	return absDistribution(f.autocorrelation())
 }
 
 // AbsoluteIndicator 计算绝对指标 (Delta-uniformity).
 // Delta_f = max_{a != 0} |AC_f(a)|.   除去第一个元素
 // 这是一个衡量函数抵抗差分攻击能力的重要指标，值越小越好.
 func (f *BooleanFunction) AbsoluteIndicator() int64 {
-	ac := f.Autocorrelation()
+	ac := f.autocorrelation()
 	if len(ac) <= 1 {
 		return 0 // 对于n = 0的情况或异常情况
 	}
-	var maxAbs int64 = 0
-	// 循环从索引 1 开始，跳过 AC_f(0)
-	for _, v := range ac[1:] { // 跳过 a=0 的位置
-		absV := v
-		if absV < 0 {
-			absV = -absV
-		}
-		if absV > maxAbs {
-			maxAbs = absV
-		}
-	}
-	return maxAbs
+	// 从索引 1 开始，跳过 AC_f(0)
+	return maxAbsValue(ac, 1)
 }
 
 // DifferentialUniformity 计算差分均匀度 (delta_f).
@@ -173,7 +161,7 @@ func (f *BooleanFunction) IsRotationSymmetric() bool {
 // TransparencyOrder 计算透明度阶.
 // To(f) = 1 - (1/(2^n(2^n-1))) * ∑|ac[a]| for a != 0
 func (f *BooleanFunction) TransparencyOrder() float64 {
-	ac := f.Autocorrelation()
+	ac := f.autocorrelation()
 	length := 1 << f.n
 
 	if length <= 1 {
@@ -288,9 +276,25 @@ func (m *BitMatrix) Clone() *BitMatrix {
 // computeRREF_GF2 在 BitMatrix 上执行高斯消元法，效率极高.
 // 返回矩阵的秩
 func computeRREF_GF2(m *BitMatrix) int {
+	rank, _ := computeRREF_GF2Ctx(context.Background(), m) // Background 不会被取消
+	return rank
+}
+
+// rrefCancelCheckInterval 是高斯消元中两次检查 ctx 之间处理的主元列数.
+const rrefCancelCheckInterval = 64
+
+// computeRREF_GF2Ctx 是可取消的高斯消元，每处理 rrefCancelCheckInterval 列检查一次 ctx.
+// 被取消时返回 ctx.Err()，此时矩阵处于部分消元状态，不应再使用。
+func computeRREF_GF2Ctx(ctx context.Context, m *BitMatrix) (int, error) {
 	rank := 0
 	pivotRow := 0
 	for col := 0; col < m.cols && pivotRow < m.rows; col++ {
+		if col%rrefCancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return -1, err
+			}
+			reportProgress(ctx, pivotRow, false)
+		}
 		// 寻找主元
 		i := pivotRow
 		for i < m.rows && m.Get(i, col) == 0 {
@@ -311,20 +315,31 @@ func computeRREF_GF2(m *BitMatrix) int {
 		}
 	}
 	rank = pivotRow
-	return rank
+	reportProgress(ctx, rank, true)
+	return rank, nil
 }
 
 // computeRREF_GF2WithSolve 执行高斯消元并返回简化行阶梯形矩阵用于求解
-func computeRREF_GF2WithSolve(m *BitMatrix) (int, *BitMatrix) {
+func computeRREF_GF2WithSolve(ctx context.Context, m *BitMatrix) (int, *BitMatrix, error) {
 	rrefMatrix := m.Clone()
-	rank := computeRREF_GF2(rrefMatrix) // 使用RREF版本
-	return rank, rrefMatrix
+	rank, err := computeRREF_GF2Ctx(ctx, rrefMatrix) // 使用RREF版本
+	if err != nil {
+		return -1, nil, err
+	}
+	return rank, rrefMatrix, nil
 }
 
 // AlgebraicImmunity 计算代数免疫度.
 // 参数 findAnnihilator 控制是否需要计算并返回一个具体的最低次零化子表达式.
 // 返回值: (代数免疫度, 零化子ANF字符串, 错误)
 func (f *BooleanFunction) AlgebraicImmunity(findAnnihilator bool) (int, string, error) {
+	return f.AlgebraicImmunityCtx(context.Background(), findAnnihilator)
+}
+
+// AlgebraicImmunityCtx 是 AlgebraicImmunity 的可取消版本.
+// 在每个次数、每次求解之前以及高斯消元过程中检查 ctx，超时或取消时返回 ctx.Err()
+// （例如 context.DeadlineExceeded），便于批处理为每个函数设定时间预算。
+func (f *BooleanFunction) AlgebraicImmunityCtx(ctx context.Context, findAnnihilator bool) (int, string, error) {
 	// 边界情况处理
 	if f.n == 0 {
 		return 0, "", nil
@@ -354,6 +369,7 @@ func (f *BooleanFunction) AlgebraicImmunity(findAnnihilator bool) (int, string,
 	}
 
 	for d := 1; d <= maxDegreeToCheck; d++ {
+		degreeCtx := withProgressBase(ctx, ProgressEvent{Stage: StageAlgebraicImmunity, Degree: d})
 		// 决定检查顺序：优先检查支撑集更小的函数
 		var checkOrder [2]bool
 		if weight <= (1 << (f.n - 1)) {
@@ -363,9 +379,12 @@ func (f *BooleanFunction) AlgebraicImmunity(findAnnihilator bool) (int, string,
 		}
 
 		for _, checkF := range checkOrder {
+			if err := ctx.Err(); err != nil {
+				return -1, "", err
+			}
 			if findAnnihilator {
 				// 需要具体的零化子，使用完整版本
-				found, annihilator, err := findLowestDegreeAnnihilatorFull(f.n, d, tt, checkF)
+				found, annihilator, err := findLowestDegreeAnnihilatorFull(degreeCtx, f.n, d, tt, checkF)
 				if err != nil {
 					return -1, "", err
 				}
@@ -374,7 +393,7 @@ func (f *BooleanFunction) AlgebraicImmunity(findAnnihilator bool) (int, string,
 				}
 			} else {
 				// 只需要判断存在性，使用快速版本
-				found, err := findAnnihilatorExists(f.n, d, tt, checkF)
+				found, err := findAnnihilatorExists(degreeCtx, f.n, d, tt, checkF)
 				if err != nil {
 					return -1, "", err
 				}
@@ -391,14 +410,24 @@ func (f *BooleanFunction) AlgebraicImmunity(findAnnihilator bool) (int, string,
 // FAA 按较早文献中的定义计算抵抗快速代数攻击能力:
 // FAA(f) = min{deg(g)+deg(h) | fg=h, deg(g) < n/2, g!=0, h!=0}.
 func (f *BooleanFunction) FAA() (int, error) {
-	return f.fastAttackMetric(faaMode)
+	return f.FAACtx(context.Background())
+}
+
+// FAACtx 是 FAA 的可取消版本，取消语义同 AlgebraicImmunityCtx.
+func (f *BooleanFunction) FAACtx(ctx context.Context) (int, error) {
+	return f.fastAttackMetric(ctx, faaMode)
 }
 
 // FAAWithPositiveDegree 计算排除 deg(g)=0 平凡候选后的 FAA 型指标:
 // FAA+(f) = min{deg(g)+deg(h) | fg=h, 1<=deg(g)<n/2, g!=0, h!=0}.
 func (f *BooleanFunction) FAAWithPositiveDegree() (int, error) {
+	return f.FAAWithPositiveDegreeCtx(context.Background())
+}
+
+// FAAWithPositiveDegreeCtx 是 FAAWithPositiveDegree 的可取消版本.
+func (f *BooleanFunction) FAAWithPositiveDegreeCtx(ctx context.Context) (int, error) {
 	maxAllowedDegree := (f.n - 1) / 2
-	metric, found, err := f.fastAttackMetricWithDegreeRange(faaPositiveMode, 1, maxAllowedDegree, 2*f.n)
+	metric, found, err := f.fastAttackMetricWithDegreeRange(ctx, faaPositiveMode, 1, maxAllowedDegree, 2*f.n)
 	if err != nil {
 		return -1, err
 	}
@@ -411,7 +440,12 @@ func (f *BooleanFunction) FAAWithPositiveDegree() (int, error) {
 // FAI 按常见文献中的标准定义计算快速代数免疫:
 // FAI(f) = min(2AI(f), min_{1<=deg(g)<AI(f)}(deg(g)+deg(fg))).
 func (f *BooleanFunction) FAI() (int, error) {
-	ai, err := f.standardAIForFAI()
+	return f.FAICtx(context.Background())
+}
+
+// FAICtx 是 FAI 的可取消版本，内部计算代数免疫度时同样响应取消.
+func (f *BooleanFunction) FAICtx(ctx context.Context) (int, error) {
+	ai, err := f.standardAIForFAI(ctx)
 	if err != nil {
 		return -1, err
 	}
@@ -421,7 +455,7 @@ func (f *BooleanFunction) FAI() (int, error) {
 		return best, nil
 	}
 
-	metric, found, err := f.fastAttackMetricWithDegreeRange(faiMode, 1, ai-1, best-1)
+	metric, found, err := f.fastAttackMetricWithDegreeRange(ctx, faiMode, 1, ai-1, best-1)
 	if err != nil {
 		return -1, err
 	}
@@ -431,7 +465,7 @@ func (f *BooleanFunction) FAI() (int, error) {
 	return best, nil
 }
 
-func (f *BooleanFunction) standardAIForFAI() (int, error) {
+func (f *BooleanFunction) standardAIForFAI(ctx context.Context) (int, error) {
 	if f.n == 0 {
 		return 0, nil
 	}
@@ -441,7 +475,7 @@ func (f *BooleanFunction) standardAIForFAI() (int, error) {
 		return 0, nil
 	}
 
-	ai, _, err := f.AlgebraicImmunity(false)
+	ai, _, err := f.AlgebraicImmunityCtx(ctx, false)
 	if err != nil {
 		return -1, err
 	}
@@ -456,7 +490,7 @@ const (
 	faiMode
 )
 
-func (f *BooleanFunction) fastAttackMetric(mode fastAttackMode) (int, error) {
+func (f *BooleanFunction) fastAttackMetric(ctx context.Context, mode fastAttackMode) (int, error) {
 	minAllowedDegree := 0
 	maxAllowedDegree := f.n
 	maxSum := f.n
@@ -466,7 +500,7 @@ func (f *BooleanFunction) fastAttackMetric(mode fastAttackMode) (int, error) {
 		minAllowedDegree = 1
 		maxSum = 2 * f.n
 	}
-	metric, found, err := f.fastAttackMetricWithDegreeRange(mode, minAllowedDegree, maxAllowedDegree, maxSum)
+	metric, found, err := f.fastAttackMetricWithDegreeRange(ctx, mode, minAllowedDegree, maxAllowedDegree, maxSum)
 	if err != nil {
 		return -1, err
 	}
@@ -476,7 +510,7 @@ func (f *BooleanFunction) fastAttackMetric(mode fastAttackMode) (int, error) {
 	return metric, nil
 }
 
-func (f *BooleanFunction) fastAttackMetricWithDegreeRange(mode fastAttackMode, minAllowedDegree, maxAllowedDegree, maxSum int) (int, bool, error) {
+func (f *BooleanFunction) fastAttackMetricWithDegreeRange(ctx context.Context, mode fastAttackMode, minAllowedDegree, maxAllowedDegree, maxSum int) (int, bool, error) {
 	if f.n == 0 {
 		return 0, true, nil
 	}
@@ -486,7 +520,7 @@ func (f *BooleanFunction) fastAttackMetricWithDegreeRange(mode fastAttackMode, m
 		return -1, false, fmt.Errorf("%s is undefined for the zero function", fastAttackModeName(mode))
 	}
 
-	anfSupport := nonzeroANFIndices(f.AlgebraicNormalFormCoefficients())
+	anfSupport := packedSupport(f.anfPacked())
 	degF := f.AlgebraicDegree()
 
 	if maxAllowedDegree < minAllowedDegree {
@@ -503,8 +537,15 @@ func (f *BooleanFunction) fastAttackMetricWithDegreeRange(mode fastAttackMode, m
 			upperDegree = maxAllowedDegree
 		}
 		for e := minAllowedDegree; e <= upperDegree; e++ {
+			if err := ctx.Err(); err != nil {
+				return -1, false, err
+			}
 			d := sum - e
-			if hasFastAttackWitness(tt, anfSupport, degF, f.n, e, d, mode, gMonomialCache, annihilatorDimCache, solutionDimCache) {
+			found, err := hasFastAttackWitness(ctx, tt, anfSupport, degF, f.n, e, d, mode, gMonomialCache, annihilatorDimCache, solutionDimCache)
+			if err != nil {
+				return -1, false, err
+			}
+			if found {
 				return sum, true, nil
 			}
 		}
@@ -514,6 +555,7 @@ func (f *BooleanFunction) fastAttackMetricWithDegreeRange(mode fastAttackMode, m
 }
 
 func hasFastAttackWitness(
+	ctx context.Context,
 	tt []byte,
 	anfSupport []int,
 	degF, n, e, d int,
@@ -521,38 +563,47 @@ func hasFastAttackWitness(
 	gMonomialCache map[int][]int,
 	annihilatorDimCache map[int]int,
 	solutionDimCache map[[2]int]int,
-) bool {
+) (bool, error) {
 	if d < 0 || d > n {
-		return false
+		return false, nil
 	}
 
+	ctx = withProgressBase(ctx, ProgressEvent{Stage: StageFastAttack, Metric: fastAttackModeName(mode), Degree: e, DegreeH: d})
 	key := [2]int{e, d}
 	solutionDim, ok := solutionDimCache[key]
 	if !ok {
 		gMonomials := cachedMonomialsUpToDegree(gMonomialCache, n, e)
-		solutionDim = boundedProductSolutionDimension(n, d, gMonomials, anfSupport)
+		var err error
+		solutionDim, err = boundedProductSolutionDimension(ctx, n, d, gMonomials, anfSupport)
+		if err != nil {
+			return false, err
+		}
 		solutionDimCache[key] = solutionDim
 	}
 	if solutionDim == 0 {
-		return false
+		return false, nil
 	}
 
 	annihilatorDim, ok := annihilatorDimCache[e]
 	if !ok {
 		gMonomials := cachedMonomialsUpToDegree(gMonomialCache, n, e)
-		annihilatorDim = annihilatorDimension(gMonomials, tt)
+		var err error
+		annihilatorDim, err = annihilatorDimension(ctx, gMonomials, tt)
+		if err != nil {
+			return false, err
+		}
 		annihilatorDimCache[e] = annihilatorDim
 	}
 	if solutionDim <= annihilatorDim {
-		return false
+		return false, nil
 	}
 
 	if mode == faiMode && annihilatorDim == 0 && solutionDim == 1 && degF <= d {
 		// 此时解空间仅为 {0, 1}，而 FAI 的定义排除了常数乘子 g=1。
-		return false
+		return false, nil
 	}
 
-	return true
+	return true, nil
 }
 
 func cachedMonomialsUpToDegree(cache map[int][]int, n, d int) []int {
@@ -592,25 +643,15 @@ func highDegreeMonomials(n, d int) []int {
 	return monomials
 }
 
-func nonzeroANFIndices(coeffs []byte) []int {
-	indices := make([]int, 0)
-	for i, coeff := range coeffs {
-		if coeff == 1 {
-			indices = append(indices, i)
-		}
-	}
-	return indices
-}
-
-func boundedProductSolutionDimension(n, d int, gMonomials, anfSupport []int) int {
+func boundedProductSolutionDimension(ctx context.Context, n, d int, gMonomials, anfSupport []int) (int, error) {
 	numVars := len(gMonomials)
 	if numVars == 0 {
-		return 0
+		return 0, nil
 	}
 
 	highMonomials := highDegreeMonomials(n, d)
 	if len(highMonomials) == 0 {
-		return numVars
+		return numVars, nil
 	}
 
 	rowIndex := make(map[int]int, len(highMonomials))
@@ -618,6 +659,7 @@ func boundedProductSolutionDimension(n, d int, gMonomials, anfSupport []int) int
 		rowIndex[monomial] = i
 	}
 
+	ctx = withProgressMatrix(ctx, "product", len(highMonomials), numVars)
 	matrix := NewBitMatrix(len(highMonomials), numVars)
 	for col, gMonomial := range gMonomials {
 		for _, fMonomial := range anfSupport {
@@ -628,14 +670,17 @@ func boundedProductSolutionDimension(n, d int, gMonomials, anfSupport []int) int
 		}
 	}
 
-	rank := computeRREF_GF2(matrix)
-	return numVars - rank
+	rank, err := computeRREF_GF2Ctx(ctx, matrix)
+	if err != nil {
+		return -1, err
+	}
+	return numVars - rank, nil
 }
 
-func annihilatorDimension(gMonomials []int, tt []byte) int {
+func annihilatorDimension(ctx context.Context, gMonomials []int, tt []byte) (int, error) {
 	numVars := len(gMonomials)
 	if numVars == 0 {
-		return 0
+		return 0, nil
 	}
 
 	support := make([]int, 0)
@@ -646,9 +691,10 @@ func annihilatorDimension(gMonomials []int, tt []byte) int {
 	}
 
 	if len(support) == 0 {
-		return numVars
+		return numVars, nil
 	}
 
+	ctx = withProgressMatrix(ctx, "annihilator", len(support), numVars)
 	matrix := NewBitMatrix(len(support), numVars)
 	for row, inputVec := range support {
 		for col, monomial := range gMonomials {
@@ -658,8 +704,11 @@ func annihilatorDimension(gMonomials []int, tt []byte) int {
 		}
 	}
 
-	rank := computeRREF_GF2(matrix)
-	return numVars - rank
+	rank, err := computeRREF_GF2Ctx(ctx, matrix)
+	if err != nil {
+		return -1, err
+	}
+	return numVars - rank, nil
 }
 
 func isZeroTruthTable(tt []byte) bool {
@@ -694,8 +743,16 @@ func fastAttackModeName(mode fastAttackMode) string {
 	return "FAI"
 }
 
+// annihilatorTargetName 返回进度事件中零化子针对的函数名.
+func annihilatorTargetName(useSupportOfF bool) string {
+	if useSupportOfF {
+		return "f"
+	}
+	return "f+1"
+}
+
 // findAnnihilatorExists 快速判断是否存在零化子（不求解具体表达式）
-func findAnnihilatorExists(n, d int, tt []byte, useSupportOfF bool) (bool, error) {
+func findAnnihilatorExists(ctx context.Context, n, d int, tt []byte, useSupportOfF bool) (bool, error) {
 	// 1. 生成单项式
 	monomials := make([]int, 0)
 	for i := 0; i < (1 << n); i++ {
@@ -729,6 +786,8 @@ func findAnnihilatorExists(n, d int, tt []byte, useSupportOfF bool) (bool, error
 		return true, nil
 	}
 
+	ctx = withProgressMatrix(ctx, annihilatorTargetName(useSupportOfF), numEqs, numVars)
+
 	// 3. 构建 BitMatrix
 	matrix := NewBitMatrix(numEqs, numVars)
 	for i := 0; i < numEqs; i++ {
@@ -741,14 +800,17 @@ func findAnnihilatorExists(n, d int, tt []byte, useSupportOfF bool) (bool, error
 		}
 	}
 
-	// 4. 调用优化版的高斯消元
-	rank := computeRREF_GF2(matrix)
+	// 4. 调用优化版的高斯消元（可取消）
+	rank, err := computeRREF_GF2Ctx(ctx, matrix)
+	if err != nil {
+		return false, err
+	}
 
 	return rank < numVars, nil
 }
 
 // findLowestDegreeAnnihilatorFull 完整版本，计算具体的零化子表达式
-func findLowestDegreeAnnihilatorFull(n, d int, tt []byte, useSupportOfF bool) (bool, string, error) {
+func findLowestDegreeAnnihilatorFull(ctx context.Context, n, d int, tt []byte, useSupportOfF bool) (bool, string, error) {
 	// 1. 生成单项式
 	monomials := make([]int, 0)
 	for i := 0; i < (1 << n); i++ {
@@ -780,6 +842,8 @@ func findLowestDegreeAnnihilatorFull(n, d int, tt []byte, useSupportOfF bool) (b
 
 	numEqs := len(support)
 
+	ctx = withProgressMatrix(ctx, annihilatorTargetName(useSupportOfF), numEqs, numVars)
+
 	// 3. 构建 BitMatrix
 	matrix := NewBitMatrix(numEqs, numVars)
 	for i := 0; i < numEqs; i++ {
@@ -793,7 +857,10 @@ func findLowestDegreeAnnihilatorFull(n, d int, tt []byte, useSupportOfF bool) (b
 	}
 
 	// 4. 调用带求解的高斯消元
-	rank, rrefMatrix := computeRREF_GF2WithSolve(matrix)
+	rank, rrefMatrix, err := computeRREF_GF2WithSolve(ctx, matrix)
+	if err != nil {
+		return false, "", err
+	}
 
 	if rank < numVars {
 		// 从RREF矩阵中求解
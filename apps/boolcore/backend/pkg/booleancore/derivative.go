@@ -0,0 +1,90 @@
+package booleancore
+
+import (
+	"context"
+	"fmt"
+)
+
+// 导数 D_a f(x) = f(x) ⊕ f(x ⊕ a) 与二阶导数 D_b D_a f.
+// 自相关 r(a) = Σ_x (-1)^{D_a f(x)} 只是导数的汇总，很多判据（线性结构、PC、导数次数等）需要导数本身，
+// 这里把导数作为普通的 BooleanFunction 返回，可以继续计算任意性质。
+
+// LinearStructure 是 f 的一个线性结构：D_a f 恒等于常数 Value.
+type LinearStructure struct {
+	A     int  `json:"a"`
+	Value byte `json:"value"`
+}
+
+// Derivative 返回 f 在方向 a 上的导数 D_a f(x) = f(x) ⊕ f(x ⊕ a)，复杂度 O(2^n/64).
+func (f *BooleanFunction) Derivative(a int) (*BooleanFunction, error) {
+	if a < 0 || a >= 1<<f.n {
+		return nil, fmt.Errorf("direction %d out of range (must be in [0, 2^%d))", a, f.n)
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: f.derivativePacked(a)}, nil
+}
+
+// SecondDerivative 返回二阶导数 D_b D_a f(x) = f(x) ⊕ f(x⊕a) ⊕ f(x⊕b) ⊕ f(x⊕a⊕b).
+// 结果关于 a、b 对称，a = b 或其中之一为 0 时恒为 0。
+func (f *BooleanFunction) SecondDerivative(a, b int) (*BooleanFunction, error) {
+	da, err := f.Derivative(a)
+	if err != nil {
+		return nil, err
+	}
+	return da.Derivative(b)
+}
+
+// DerivativeDegrees 返回导数次数谱：下标为 a 的元素是 deg(D_a f)（零函数记为 0）.
+// 对 a ≠ 0 有 deg(D_a f) ≤ deg(f) - 1，复杂度 O(n·4^n/64)。
+func (f *BooleanFunction) DerivativeDegrees() []int {
+	degrees, _ := f.DerivativeDegreesCtx(context.Background())
+	return degrees
+}
+
+// DerivativeDegreesCtx 是 DerivativeDegrees 的可取消版本，每个方向之前检查 ctx.
+func (f *BooleanFunction) DerivativeDegreesCtx(ctx context.Context) ([]int, error) {
+	degrees := make([]int, 1<<f.n)
+	for a := 1; a < len(degrees); a++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		words := f.derivativePacked(a)
+		fmtPackedInplace(words, f.n)
+		degrees[a] = packedANFDegree(words, f.n)
+	}
+	return degrees, nil
+}
+
+// LinearStructures 返回 f 的全部非零线性结构（按 a 升序），即 |r(a)| = 2^n 的方向 a.
+// 线性结构与 0 一起构成线性子空间，存在线性结构的函数不是 bent 函数。
+func (f *BooleanFunction) LinearStructures() []LinearStructure {
+	full := int64(1) << uint(f.n)
+	structures := make([]LinearStructure, 0)
+	for a, r := range f.autocorrelation() {
+		switch {
+		case a == 0:
+		case r == full:
+			structures = append(structures, LinearStructure{A: a, Value: 0})
+		case r == -full:
+			structures = append(structures, LinearStructure{A: a, Value: 1})
+		}
+	}
+	return structures
+}
+
+// HasLinearStructure 判断 f 是否存在非零线性结构.
+func (f *BooleanFunction) HasLinearStructure() bool {
+	return len(f.LinearStructures()) > 0
+}
+
+// --- 私有实现 ---
+
+// derivativePacked 返回 D_a f 的打包真值表，调用方保证 a 在范围内.
+func (f *BooleanFunction) derivativePacked(a int) []uint64 {
+	words := f.packedTruthTable
+	packed := make([]uint64, len(words))
+	wordShift, inWord := a>>6, a&63
+	for w, v := range words {
+		packed[w] = v ^ permuteWordXor(words[w^wordShift], inWord)
+	}
+	return packed
+}
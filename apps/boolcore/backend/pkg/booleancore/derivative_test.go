@@ -0,0 +1,94 @@
+package booleancore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestDerivative 将导数与二阶导数和逐点定义对比（n=8 时跨越多个字）
+func TestDerivative(t *testing.T) {
+	f := randomFunction(t, 8, 3)
+	ft := f.TruthTable()
+	for _, a := range []int{0, 1, 5, 63, 64, 0x9b, 255} {
+		d, err := f.Derivative(a)
+		if err != nil {
+			t.Fatalf("Derivative(%d) error: %v", a, err)
+		}
+		dt := d.TruthTable()
+		for x := range dt {
+			if dt[x] != ft[x]^ft[x^a] {
+				t.Fatalf("a=%d x=%d: D_a f(x)=%d, 期望 %d", a, x, dt[x], ft[x]^ft[x^a])
+			}
+		}
+		// r(a) = 2^n - 2·wt(D_a f)
+		if r, _ := f.AutocorrelationAt(a); r != int64(len(dt)-2*d.HammingWeight()) {
+			t.Errorf("a=%d: 导数重量与自相关不一致", a)
+		}
+	}
+
+	a, b := 0x21, 0xc4
+	dab, _ := f.SecondDerivative(a, b)
+	dba, _ := f.SecondDerivative(b, a)
+	if !dab.Equal(dba) {
+		t.Error("二阶导数应关于 a、b 对称")
+	}
+	dt := dab.TruthTable()
+	for x := range dt {
+		if want := ft[x] ^ ft[x^a] ^ ft[x^b] ^ ft[x^a^b]; dt[x] != want {
+			t.Fatalf("x=%d: D_b D_a f(x)=%d, 期望 %d", x, dt[x], want)
+		}
+	}
+	if daa, _ := f.SecondDerivative(a, a); daa.HammingWeight() != 0 {
+		t.Error("D_a D_a f 应恒为 0")
+	}
+
+	if _, err := f.Derivative(256); err == nil {
+		t.Error("方向越界应返回错误")
+	}
+}
+
+// TestDerivativeDegrees 检查导数次数谱与线性结构
+func TestDerivativeDegrees(t *testing.T) {
+	// f = x0x1x2 ⊕ x3x4 ⊕ x5：x5 方向为 1-线性结构
+	f, _ := NewFromANF(6, "x0*x1*x2 + x3*x4 + x5")
+	degrees := f.DerivativeDegrees()
+	want := map[int]int{0: 0, 1: 2, 1 << 3: 1, 1 << 5: 0, 1<<3 | 1<<5: 1, 1 | 1<<3: 2}
+	for a, d := range want {
+		if degrees[a] != d {
+			t.Errorf("deg(D_%d f) = %d, 期望 %d", a, degrees[a], d)
+		}
+	}
+	deg := f.AlgebraicDegree()
+	for a := 1; a < len(degrees); a++ {
+		if degrees[a] > deg-1 {
+			t.Fatalf("deg(D_%d f) = %d 超过 deg(f)-1", a, degrees[a])
+		}
+	}
+
+	if got := f.LinearStructures(); !reflect.DeepEqual(got, []LinearStructure{{A: 1 << 5, Value: 1}}) {
+		t.Errorf("线性结构应为 [{32 1}], 实际 %v", got)
+	}
+	bent, _ := NewFromANF(6, "x0*x1 + x2*x3 + x4*x5")
+	if bent.HasLinearStructure() {
+		t.Error("bent 函数不应有线性结构")
+	}
+	// 线性结构与导数一致：D_a f 恒为 Value
+	g, _ := NewFromANF(5, "x0*x1 + x0*x2 + x3")
+	for _, s := range g.LinearStructures() {
+		d, _ := g.Derivative(s.A)
+		if w := d.HammingWeight(); w != int(s.Value)<<5 {
+			t.Errorf("a=%d: D_a f 应恒为 %d", s.A, s.Value)
+		}
+	}
+	// 线性结构空间由 x1⊕x2、x3、x4 三个方向张成
+	if len(g.LinearStructures()) != 7 {
+		t.Errorf("5 元函数 x0x1+x0x2+x3 的非零线性结构应有 7 个, 实际 %v", g.LinearStructures())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.DerivativeDegreesCtx(ctx); err == nil {
+		t.Error("ctx 取消后应返回错误")
+	}
+}
@@ -0,0 +1,171 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// bent 函数的对偶与子类判定.
+// f 为 bent 函数时 W_f(a) = 2^{n/2}(-1)^{f~(a)}，对偶 f~ 也是 bent 函数且 f~~ = f；
+// f~ = f 称为自对偶，f~ = f ⊕ 1 称为反自对偶。
+//
+// Maiorana–McFarland 完备类 M#（MM 类在扩展仿射等价下的闭包）用 Dillon 判据判定：
+// bent 函数 f 属于 M# 当且仅当存在 n/2 维线性子空间 V，使得对所有 a, b ∈ V 有 D_a D_b f ≡ 0，
+// 即 f 在 V 的每个陪集上都是仿射函数。
+
+// maxCompletedMMN 是 M# 判定支持的最大 n：需要对每个方向 a 求 D_a f 的自相关，复杂度 O(n·4^n).
+const maxCompletedMMN = 12
+
+// Dual 返回 bent 函数 f 的对偶 f~，f 不是 bent 函数时返回错误.
+func (f *BooleanFunction) Dual() (*BooleanFunction, error) {
+	if !f.IsBent() {
+		return nil, errors.New("dual is only defined for bent functions")
+	}
+	packed := make([]uint64, len(f.packedTruthTable))
+	for a, w := range f.walsh() {
+		if w < 0 {
+			packed[a>>6] |= 1 << uint(a&63)
+		}
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}, nil
+}
+
+// IsSelfDual 判断 f 是否为自对偶 bent 函数（f~ = f），非 bent 函数返回 false.
+func (f *BooleanFunction) IsSelfDual() bool {
+	dual, err := f.Dual()
+	return err == nil && dual.Equal(f)
+}
+
+// IsAntiSelfDual 判断 f 是否为反自对偶 bent 函数（f~ = f ⊕ 1），非 bent 函数返回 false.
+func (f *BooleanFunction) IsAntiSelfDual() bool {
+	dual, err := f.Dual()
+	return err == nil && dual.Equal(f.Not())
+}
+
+// IsCompletedMM 判断 f 是否属于 Maiorana–McFarland 完备类 M#，非 bent 函数返回 false，只支持 n ≤ 12.
+func (f *BooleanFunction) IsCompletedMM() (bool, error) {
+	return f.IsCompletedMMCtx(context.Background())
+}
+
+// IsCompletedMMCtx 是 IsCompletedMM 的可取消版本.
+func (f *BooleanFunction) IsCompletedMMCtx(ctx context.Context) (bool, error) {
+	basis, err := f.CompletedMMSubspaceCtx(ctx)
+	return basis != nil, err
+}
+
+// CompletedMMSubspaceCtx 返回 Dillon 判据中 n/2 维子空间 V 的一组基（f 在 V 的每个陪集上仿射），
+// 不存在（f 不属于 M#）或 f 不是 bent 函数时返回 nil.
+// 对某个 V 有 f(x, y) 在 x ∈ V 方向上仿射，按 V 与其补空间换基后 f 即成为 MM 形式 x·π(y) ⊕ g(y)。
+func (f *BooleanFunction) CompletedMMSubspaceCtx(ctx context.Context) ([]int, error) {
+	if f.n > maxCompletedMMN {
+		return nil, fmt.Errorf("completed Maiorana-McFarland check only supports n <= %d, got %d", maxCompletedMMN, f.n)
+	}
+	if !f.IsBent() {
+		return nil, nil
+	}
+	s, err := newMMSearch(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return s.search()
+}
+
+// --- 私有实现 ---
+
+// mmSearch 回溯搜索 Dillon 判据中的子空间.
+// flat[a] 是子空间 {b : D_b D_a f ≡ 0} 的位集合（包含 0 与 a 本身），它关于 a、b 对称，
+// 所以 V 满足条件当且仅当 V 的基两两满足 D_{b_i} D_{b_j} f ≡ 0，即 V ⊆ ∩ flat[b_i]。
+type mmSearch struct {
+	ctx   context.Context
+	n, m  int
+	words int
+	flat  [][]uint64
+	basis []int
+	span  []int
+}
+
+func newMMSearch(ctx context.Context, f *BooleanFunction) (*mmSearch, error) {
+	size := 1 << uint(f.n)
+	s := &mmSearch{ctx: ctx, n: f.n, m: f.n / 2, words: packedWords(f.n), flat: make([][]uint64, size)}
+	full := int64(size)
+	for a := 1; a < size; a++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		d := &BooleanFunction{n: f.n, packedTruthTable: f.derivativePacked(a)}
+		ac, err := d.autocorrelationCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		set := make([]uint64, s.words)
+		for b, r := range ac {
+			if r == full {
+				set[b>>6] |= 1 << uint(b&63)
+			}
+		}
+		s.flat[a] = set
+	}
+	return s, nil
+}
+
+// search 返回找到的基，找不到时返回 nil.
+func (s *mmSearch) search() ([]int, error) {
+	all := make([]uint64, s.words)
+	fillOnes(all, s.n)
+	s.span = []int{0}
+	found, err := s.extend(all, 0)
+	if err != nil || !found {
+		return nil, err
+	}
+	return append([]int(nil), s.basis...), nil
+}
+
+// extend 在候选集合 cand（当前所有基向量的 flat 之交，是包含 span 的子空间）中继续选基向量.
+// 只按贪心基（每个基向量是 V 中不在前面基张成空间里的最小元素）的形式枚举：
+// 新向量大于上一个基向量，且是它所在 span 陪集中的最小元素。
+func (s *mmSearch) extend(cand []uint64, last int) (bool, error) {
+	if len(s.basis) == s.m {
+		return true, nil
+	}
+	if err := s.ctx.Err(); err != nil {
+		return false, err
+	}
+	next := make([]uint64, s.words)
+	for _, c := range packedSupport(cand) {
+		if c <= last || !s.cosetMinimum(c) {
+			continue
+		}
+		count := 0
+		for w := range next {
+			next[w] = cand[w] & s.flat[c][w]
+			count += bits.OnesCount64(next[w])
+		}
+		if count < 1<<uint(s.m) {
+			continue // 交集维数不足 m
+		}
+		spanSize := len(s.span)
+		for i := 0; i < spanSize; i++ {
+			s.span = append(s.span, s.span[i]^c)
+		}
+		s.basis = append(s.basis, c)
+		found, err := s.extend(next, c)
+		if err != nil || found {
+			return found, err
+		}
+		s.basis = s.basis[:len(s.basis)-1]
+		s.span = s.span[:spanSize]
+	}
+	return false, nil
+}
+
+// cosetMinimum 判断 c 是否为陪集 c ⊕ span 中的最小元素（c 不在 span 中时才可能成立）.
+func (s *mmSearch) cosetMinimum(c int) bool {
+	for _, v := range s.span[1:] {
+		if c^v < c {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package booleancore
+
+import (
+	"context"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// randomMMBent 生成随机的 Maiorana–McFarland bent 函数 f(x, y) = x·π(y) ⊕ g(y)，x 为低 n/2 位
+func randomMMBent(t *testing.T, rng *rand.Rand, n int) *BooleanFunction {
+	m := n / 2
+	perm := rng.Perm(1 << m)
+	g := make([]byte, 1<<m)
+	for i := range g {
+		g[i] = byte(rng.Intn(2))
+	}
+	tt := make([]byte, 1<<n)
+	for z := range tt {
+		x, y := z&(1<<m-1), z>>m
+		tt[z] = byte(bits.OnesCount(uint(x&perm[y]))&1) ^ g[y]
+	}
+	f, err := NewFromTruthTable(tt)
+	if err != nil {
+		t.Fatalf("NewFromTruthTable error: %v", err)
+	}
+	return f
+}
+
+// TestDual 检查对偶的定义、f~~ = f 与自对偶/反自对偶判定
+func TestDual(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, n := range []int{2, 4, 6, 8} {
+		f := randomMMBent(t, rng, n)
+		dual, err := f.Dual()
+		if err != nil {
+			t.Fatalf("n=%d: Dual error: %v", n, err)
+		}
+		w := f.WalshHadamardTransform()
+		dt := dual.TruthTable()
+		for a := range w {
+			if want := int64(1-2*int(dt[a])) << uint(n/2); w[a] != want {
+				t.Fatalf("n=%d a=%d: W(a)=%d, 期望 %d", n, a, w[a], want)
+			}
+		}
+		if !dual.IsBent() {
+			t.Errorf("n=%d: 对偶应为 bent 函数", n)
+		}
+		if back, _ := dual.Dual(); !back.Equal(f) {
+			t.Errorf("n=%d: 对偶的对偶应为 f 本身", n)
+		}
+	}
+
+	// 内积函数自对偶，n=2 时的 x0 ∨ x1 反自对偶
+	inner, _ := NewFromANF(6, "x0*x1 + x2*x3 + x4*x5")
+	if !inner.IsSelfDual() || inner.IsAntiSelfDual() {
+		t.Error("x0x1+x2x3+x4x5 应为自对偶")
+	}
+	or, _ := NewFromANF(2, "x0*x1 + x0 + x1")
+	if !or.IsAntiSelfDual() || or.IsSelfDual() {
+		t.Error("x0x1+x0+x1 应为反自对偶")
+	}
+
+	notBent, _ := NewFromANF(4, "x0*x1*x2")
+	if _, err := notBent.Dual(); err == nil {
+		t.Error("非 bent 函数求对偶应返回错误")
+	}
+	if notBent.IsSelfDual() || notBent.IsAntiSelfDual() {
+		t.Error("非 bent 函数既不是自对偶也不是反自对偶")
+	}
+}
+
+// gf16Mul 是 GF(2^4) = GF(2)[z]/(z^4+z+1) 上的乘法
+func gf16Mul(a, b int) int {
+	r := 0
+	for i := 0; i < 4; i++ {
+		if (b>>i)&1 == 1 {
+			r ^= a << i
+		}
+	}
+	for i := 7; i >= 4; i-- {
+		if (r>>i)&1 == 1 {
+			r ^= 0x13 << (i - 4)
+		}
+	}
+	return r
+}
+
+// TestCompletedMM 用 Dillon 判据检查 M# 判定
+func TestCompletedMM(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(4))
+
+	// MM 函数经扩展仿射变换后仍在 M# 中，返回的子空间满足判据
+	for _, n := range []int{4, 6, 8} {
+		for trial := 0; trial < 3; trial++ {
+			f, _ := randomAffineMap(rng, n, true).Apply(randomMMBent(t, rng, n))
+			basis, err := f.CompletedMMSubspaceCtx(ctx)
+			if err != nil {
+				t.Fatalf("n=%d: %v", n, err)
+			}
+			if len(basis) != n/2 {
+				t.Fatalf("n=%d: MM 函数应属于 M#, 实际基为 %v", n, basis)
+			}
+			for i, a := range basis {
+				for _, b := range basis[i+1:] {
+					if d, _ := f.SecondDerivative(a, b); d.HammingWeight() != 0 {
+						t.Fatalf("n=%d: D_%d D_%d f 不恒为 0", n, a, b)
+					}
+				}
+			}
+		}
+	}
+
+	// n=8 的 PS_ap 函数 f(x, y) = g(x·y^{-1})（x, y ∈ GF(16)，g 重量 8 且 g(0) = 0），
+	// 对下面的 g 不存在满足判据的 4 维子空间（已用不剪枝的逐对检查穷举核对）
+	inv := make([]int, 16)
+	for a := 1; a < 16; a++ {
+		for b := 1; b < 16; b++ {
+			if gf16Mul(a, b) == 1 {
+				inv[a] = b
+			}
+		}
+	}
+	g := []byte{0, 1, 1, 0, 1, 0, 0, 1, 1, 0, 1, 0, 0, 1, 0, 1}
+	tt := make([]byte, 256)
+	for z := range tt {
+		tt[z] = g[gf16Mul(z&15, inv[z>>4])]
+	}
+	ps, _ := NewFromTruthTable(tt)
+	if !ps.IsBent() {
+		t.Fatal("PS_ap 函数应为 bent 函数")
+	}
+	if ok, err := ps.IsCompletedMM(); err != nil || ok {
+		t.Errorf("该 PS_ap 函数不应属于 M#, 实际 %v %v", ok, err)
+	}
+
+	notBent, _ := NewFromANF(6, "x0*x1*x2")
+	if ok, _ := notBent.IsCompletedMM(); ok {
+		t.Error("非 bent 函数不属于 M#")
+	}
+	if _, err := randomFunction(t, 13, 1).IsCompletedMM(); err == nil {
+		t.Error("n > 12 应返回错误")
+	}
+}
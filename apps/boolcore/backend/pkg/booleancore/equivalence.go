@@ -0,0 +1,169 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// 仿射等价判定：
+//   - 仿射等价：g(x) = f(Ax ⊕ b) ⊕ d；
+//   - 扩展仿射等价（EquivalenceOptions.Extended）：g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d。
+// 任意 n 先比较仿射不变量（代数次数、绝对 Walsh 谱分布、自相关谱分布，仿射等价时还有重量与代数免疫度），
+// 不同即可判定不等价；n ≤ 6 时再比较规范形式（见 canonical.go）给出确定的结论与具体的变换。
+
+// EquivalenceOptions 配置等价判定.
+type EquivalenceOptions struct {
+	Extended bool // 允许加上线性函数 c·x（扩展仿射等价）
+}
+
+// AffineMap 描述变换 g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d，与 AffineTransform 的参数一致.
+type AffineMap struct {
+	A *BitMatrix
+	B int
+	C int
+	D byte
+}
+
+// Apply 对 f 应用该变换.
+func (m AffineMap) Apply(f *BooleanFunction) (*BooleanFunction, error) {
+	return f.AffineTransform(m.A, m.B, m.C, m.D)
+}
+
+// EquivalenceResult 是等价判定的结果.
+type EquivalenceResult struct {
+	Equivalent bool
+	Decided    bool       // n > 6 且所有不变量都相同时为 false，此时 Equivalent 也为 false
+	Reason     string     // 不等价时给出第一个不同的不变量，未判定时说明原因
+	Map        *AffineMap // 等价时 g = Map.Apply(f)
+}
+
+// AffineEquivalent 判断 f 与 g 是否（扩展）仿射等价.
+// 只有 ctx 被取消或内部错误时返回错误。
+func AffineEquivalent(ctx context.Context, f, g *BooleanFunction, opts EquivalenceOptions) (EquivalenceResult, error) {
+	if f.n != g.n {
+		return EquivalenceResult{Decided: true, Reason: fmt.Sprintf("number of variables differs (%d vs %d)", f.n, g.n)}, nil
+	}
+	reason, err := compareAffineInvariants(ctx, f, g, opts)
+	if err != nil {
+		return EquivalenceResult{}, err
+	}
+	if reason != "" {
+		return EquivalenceResult{Decided: true, Reason: reason}, nil
+	}
+	if f.n > maxExhaustiveN {
+		return EquivalenceResult{Reason: fmt.Sprintf("all invariants match; exhaustive check only supports n <= %d", maxExhaustiveN)}, nil
+	}
+
+	canonF, colsF, bF, err := smallCanonical(ctx, f.packedTruthTable[0], f.n, opts.Extended)
+	if err != nil {
+		return EquivalenceResult{}, err
+	}
+	canonG, colsG, bG, err := smallCanonical(ctx, g.packedTruthTable[0], g.n, opts.Extended)
+	if err != nil {
+		return EquivalenceResult{}, err
+	}
+	if canonF != canonG {
+		return EquivalenceResult{Decided: true, Reason: "canonical forms differ"}, nil
+	}
+
+	// canon(x) = f(A_f x ⊕ b_f) ⊕ l_f(x) = g(A_g x ⊕ b_g) ⊕ l_g(x)，
+	// 代入 x = A_g^{-1}(z ⊕ b_g) 得 g(z) = f(Az ⊕ b) ⊕ l(z)，A = A_f A_g^{-1}，b = A b_g ⊕ b_f
+	cols := composeColumns(colsF, invertColumns(colsG))
+	b := applyColumns(cols, bG) ^ bF
+	m, err := affineMapBetween(f.packedTruthTable[0], g.packedTruthTable[0], f.n, cols, b, opts.Extended)
+	if err != nil {
+		return EquivalenceResult{}, err
+	}
+	return EquivalenceResult{Equivalent: true, Decided: true, Map: m}, nil
+}
+
+// AffineCanonicalForm 返回 f 所在（扩展）仿射等价类的规范代表元及把 f 变为它的变换，只支持 n ≤ 6.
+// 两个函数等价当且仅当规范代表元相同，可用作去重的键。
+func (f *BooleanFunction) AffineCanonicalForm(ctx context.Context, opts EquivalenceOptions) (*BooleanFunction, *AffineMap, error) {
+	if f.n > maxExhaustiveN {
+		return nil, nil, fmt.Errorf("canonical form only supports n <= %d, got %d", maxExhaustiveN, f.n)
+	}
+	canon, cols, b, err := smallCanonical(ctx, f.packedTruthTable[0], f.n, opts.Extended)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := affineMapBetween(f.packedTruthTable[0], canon, f.n, cols, b, opts.Extended)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: []uint64{canon}}, m, nil
+}
+
+// --- 私有实现 ---
+
+// affineMapBetween 在已知 A、b 时求出 g(x) = f(Ax ⊕ b) ⊕ c·x ⊕ d 中的 c、d，并校验结果.
+func affineMapBetween(f, g uint64, n int, cols []int, b int, extended bool) (*AffineMap, error) {
+	e := transformSmall(f, n, cols, b) ^ g
+	c, d := affineDifference(e, n)
+	if e != linearPacked(n, c)[0]^(uint64(d)*lastWordMask(n)) || (!extended && c != 0) {
+		return nil, errors.New("internal error: canonical frames do not match")
+	}
+	a := NewBitMatrix(n, n)
+	for j, col := range cols {
+		for r := 0; r < n; r++ {
+			a.Set(r, j, byte((col>>uint(r))&1))
+		}
+	}
+	return &AffineMap{A: a, B: b, C: c, D: d}, nil
+}
+
+// compareAffineInvariants 按代价从低到高比较仿射不变量，返回第一个不同之处的描述，全部相同时返回空串.
+func compareAffineInvariants(ctx context.Context, f, g *BooleanFunction, opts EquivalenceOptions) (string, error) {
+	degF, degG := f.AlgebraicDegree(), g.AlgebraicDegree()
+	if opts.Extended {
+		// 加上仿射函数只改变次数不超过 1 的函数的次数
+		degF, degG = max(degF, 1), max(degG, 1)
+	}
+	if degF != degG {
+		return fmt.Sprintf("algebraic degree differs (%d vs %d)", degF, degG), nil
+	}
+	if !opts.Extended {
+		full := 1 << f.n
+		wf, wg := f.HammingWeight(), g.HammingWeight()
+		if min(wf, full-wf) != min(wg, full-wg) {
+			return fmt.Sprintf("Hamming weight differs up to complement (%d vs %d)", wf, wg), nil
+		}
+	}
+	if !reflect.DeepEqual(f.AbsoluteWalshSpectrum(), g.AbsoluteWalshSpectrum()) {
+		return "absolute Walsh spectrum distribution differs", nil
+	}
+	if opts.Extended {
+		if !reflect.DeepEqual(f.AbsoluteAutocorrelation(), g.AbsoluteAutocorrelation()) {
+			return "absolute autocorrelation distribution differs", nil
+		}
+	} else {
+		// 不加线性函数时 r_g(a) = r_f(Aa)，带符号的分布也是不变量
+		if !reflect.DeepEqual(valueDistribution(f.autocorrelation()), valueDistribution(g.autocorrelation())) {
+			return "autocorrelation distribution differs", nil
+		}
+		// 代数免疫度只在不加线性函数时保持不变
+		aiF, _, err := f.AlgebraicImmunityCtx(ctx, false)
+		if err != nil {
+			return "", err
+		}
+		aiG, _, err := g.AlgebraicImmunityCtx(ctx, false)
+		if err != nil {
+			return "", err
+		}
+		if aiF != aiG {
+			return fmt.Sprintf("algebraic immunity differs (%d vs %d)", aiF, aiG), nil
+		}
+	}
+	return "", nil
+}
+
+// valueDistribution 统计各取值的出现次数.
+func valueDistribution(values []int64) map[int64]int {
+	distribution := make(map[int64]int)
+	for _, v := range values {
+		distribution[v]++
+	}
+	return distribution
+}
@@ -0,0 +1,135 @@
+package booleancore
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// randomAffineMap 生成 n 元的随机可逆仿射变换
+func randomAffineMap(rng *rand.Rand, n int, extended bool) AffineMap {
+	for {
+		a := NewBitMatrix(n, n)
+		for r := 0; r < n; r++ {
+			for c := 0; c < n; c++ {
+				a.Set(r, c, byte(rng.Intn(2)))
+			}
+		}
+		if !a.IsInvertible() {
+			continue
+		}
+		m := AffineMap{A: a, B: rng.Intn(1 << n), D: byte(rng.Intn(2))}
+		if extended {
+			m.C = rng.Intn(1 << n)
+		}
+		return m
+	}
+}
+
+// TestAffineEquivalenceRandom 对随机函数施加随机变换，检查判定结果、给出的变换与规范形式
+func TestAffineEquivalenceRandom(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	for _, extended := range []bool{false, true} {
+		opts := EquivalenceOptions{Extended: extended}
+		for n := 1; n <= 6; n++ {
+			for trial := 0; trial < 4; trial++ {
+				f := randomFunction(t, n, int64(100*n+trial))
+				g, err := randomAffineMap(rng, n, extended).Apply(f)
+				if err != nil {
+					t.Fatalf("Apply error: %v", err)
+				}
+				start := time.Now()
+				res, err := AffineEquivalent(ctx, f, g, opts)
+				if err != nil {
+					t.Fatalf("AffineEquivalent error: %v", err)
+				}
+				if time.Since(start) > time.Second {
+					t.Logf("extended=%v n=%d: %v", extended, n, time.Since(start))
+				}
+				if !res.Equivalent || !res.Decided || res.Map == nil {
+					t.Fatalf("extended=%v n=%d: 应判定为等价, 实际 %+v", extended, n, res)
+				}
+				if h, _ := res.Map.Apply(f); !h.Equal(g) {
+					t.Fatalf("extended=%v n=%d: 给出的变换不能把 f 变为 g", extended, n)
+				}
+				cf, mf, err := f.AffineCanonicalForm(ctx, opts)
+				if err != nil {
+					t.Fatalf("AffineCanonicalForm error: %v", err)
+				}
+				cg, _, _ := g.AffineCanonicalForm(ctx, opts)
+				if !cf.Equal(cg) {
+					t.Fatalf("extended=%v n=%d: 等价函数的规范形式不同", extended, n)
+				}
+				if h, _ := mf.Apply(f); !h.Equal(cf) {
+					t.Fatalf("extended=%v n=%d: 规范变换不能把 f 变为规范形式", extended, n)
+				}
+			}
+		}
+	}
+}
+
+// TestAffineEquivalenceStructured 检查对称性很高的函数（搜索中平局最多的情形）
+func TestAffineEquivalenceStructured(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(2))
+	exprs := []string{"0", "1", "x0", "x0 + x3", "x0*x1", "x0*x1 + x2*x3 + x4*x5", "x0*x1*x2", "x0*x1*x2 + x3*x4*x5",
+		"x0*x1*x2*x3*x4*x5", "x0*x1*x2 + x0*x3 + x1*x4 + x2*x5", "x0*x1*x2*x3 + x4*x5"}
+	for _, extended := range []bool{false, true} {
+		opts := EquivalenceOptions{Extended: extended}
+		for _, expr := range exprs {
+			f, err := NewFromANF(6, expr)
+			if err != nil {
+				t.Fatalf("NewFromANF(%q) error: %v", expr, err)
+			}
+			g, _ := randomAffineMap(rng, 6, extended).Apply(f)
+			res, err := AffineEquivalent(ctx, f, g, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", expr, err)
+			}
+			if !res.Equivalent {
+				t.Fatalf("extended=%v %s: 应判定为等价, 实际 %+v", extended, expr, res)
+			}
+			if h, _ := res.Map.Apply(f); !h.Equal(g) {
+				t.Fatalf("extended=%v %s: 给出的变换不能把 f 变为 g", extended, expr)
+			}
+		}
+	}
+}
+
+// TestAffineInequivalence 检查不等价的判定以及 n > 6 时无法判定的情形
+func TestAffineInequivalence(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		n          int
+		f, g       string
+		extended   bool
+		equivalent bool
+		decided    bool
+	}{
+		// 次数不同
+		{4, "x0*x1", "x0*x1*x2", false, false, true},
+		// 相差一个线性函数：扩展仿射等价，但重量改变，仿射意义下不等价
+		{4, "x0*x1", "x0*x1 + x2", false, false, true},
+		{4, "x0*x1", "x0*x1 + x2", true, true, true},
+		// 次数相同的两个不同类
+		{6, "x0*x1*x2 + x3*x4", "x0*x1*x2 + x0*x3*x4", true, false, true},
+		// n > 6 且不变量全部相同（实际等价）时无法判定
+		{7, "x0*x1 + x2*x3*x4", "x5*x6 + x0*x1*x2", false, false, false},
+	}
+	for _, tc := range cases {
+		f, _ := NewFromANF(tc.n, tc.f)
+		g, _ := NewFromANF(tc.n, tc.g)
+		res, err := AffineEquivalent(ctx, f, g, EquivalenceOptions{Extended: tc.extended})
+		if err != nil {
+			t.Fatalf("%s vs %s: %v", tc.f, tc.g, err)
+		}
+		if res.Equivalent != tc.equivalent || res.Decided != tc.decided {
+			t.Errorf("extended=%v %s vs %s: 实际 %+v", tc.extended, tc.f, tc.g, res)
+		}
+		if !res.Equivalent && res.Reason == "" {
+			t.Errorf("%s vs %s: 不等价或未判定时应给出原因", tc.f, tc.g)
+		}
+	}
+}
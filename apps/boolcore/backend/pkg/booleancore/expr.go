@@ -0,0 +1,347 @@
+package booleancore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 布尔表达式语法（优先级从低到高）:
+//   or  : "or"  | "|"  | "||"
+//   xor : "xor" | "^"  | "+"
+//   and : "and" | "&"  | "&&" | "*"
+//   not : "not" | "!"  | "~"     （一元前缀）
+//   原子: 变量 x0..x(n-1)、常数 0/1/true/false、括号 ( ... )
+// 同级二元运算符左结合。"+" 与 "*" 与 ANF 写法保持一致，分别表示异或和与。
+// 例如 "(x0 and not x1) xor x2" 与 "x0 & ~x1 ^ x2" 等价。
+//
+// 求值采用位切片方式：每个子表达式直接在打包的 uint64 真值表上做字运算，
+// 一次运算同时处理 64 个输入点。
+
+// ExprSyntaxError 表示布尔表达式中的语法错误，Pos 为出错位置（从 0 开始的字节偏移）.
+type ExprSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ExprSyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// NewFromExpr 通过布尔表达式字符串创建一个 n 元布尔函数.
+func NewFromExpr(n int, expr string) (*BooleanFunction, error) {
+	if n <= 0 || n > 30 {
+		return nil, fmt.Errorf("n must be between 1 and 30, got %d", n)
+	}
+
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, n: n}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &ExprSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+	}
+
+	return &BooleanFunction{n: n, packedTruthTable: node.eval(n)}, nil
+}
+
+// --- 词法分析 ---
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokVar
+	tokConst
+	tokOr
+	tokXor
+	tokAnd
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	pos   int
+	text  string
+	value int // 变量下标或常数值
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	i := 0
+	for i < len(expr) {
+		ch := expr[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, exprToken{kind: tokLParen, pos: i, text: "("})
+			i++
+		case ch == ')':
+			tokens = append(tokens, exprToken{kind: tokRParen, pos: i, text: ")"})
+			i++
+		case ch == '|' || ch == '&':
+			kind := tokOr
+			if ch == '&' {
+				kind = tokAnd
+			}
+			width := 1
+			if i+1 < len(expr) && expr[i+1] == ch {
+				width = 2
+			}
+			tokens = append(tokens, exprToken{kind: kind, pos: i, text: expr[i : i+width]})
+			i += width
+		case ch == '*':
+			tokens = append(tokens, exprToken{kind: tokAnd, pos: i, text: "*"})
+			i++
+		case ch == '^' || ch == '+':
+			tokens = append(tokens, exprToken{kind: tokXor, pos: i, text: string(ch)})
+			i++
+		case ch == '!' || ch == '~':
+			tokens = append(tokens, exprToken{kind: tokNot, pos: i, text: string(ch)})
+			i++
+		case ch < unicode.MaxASCII && (unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch))):
+			start := i
+			for i < len(expr) && expr[i] < unicode.MaxASCII &&
+				(unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i])) || expr[i] == '_') {
+				i++
+			}
+			tok, err := classifyWord(expr[start:i], start)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		default:
+			return nil, &ExprSyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character '%c'", ch)}
+		}
+	}
+	tokens = append(tokens, exprToken{kind: tokEOF, pos: len(expr)})
+	return tokens, nil
+}
+
+// classifyWord 识别关键字、常数和变量.
+func classifyWord(word string, pos int) (exprToken, error) {
+	lower := strings.ToLower(word)
+	switch lower {
+	case "or":
+		return exprToken{kind: tokOr, pos: pos, text: word}, nil
+	case "xor":
+		return exprToken{kind: tokXor, pos: pos, text: word}, nil
+	case "and":
+		return exprToken{kind: tokAnd, pos: pos, text: word}, nil
+	case "not":
+		return exprToken{kind: tokNot, pos: pos, text: word}, nil
+	case "0", "false":
+		return exprToken{kind: tokConst, pos: pos, text: word, value: 0}, nil
+	case "1", "true":
+		return exprToken{kind: tokConst, pos: pos, text: word, value: 1}, nil
+	}
+
+	if strings.HasPrefix(lower, "x") && len(lower) > 1 {
+		index, err := strconv.Atoi(lower[1:])
+		if err == nil && index >= 0 {
+			return exprToken{kind: tokVar, pos: pos, text: word, value: index}, nil
+		}
+	}
+	return exprToken{}, &ExprSyntaxError{Pos: pos, Msg: fmt.Sprintf("unknown identifier '%s'", word)}
+}
+
+// --- 语法分析 ---
+
+type exprParser struct {
+	tokens []exprToken
+	cur    int
+	n      int
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.cur] }
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.cur]
+	if tok.kind != tokEOF {
+		p.cur++
+	}
+	return tok
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(tokOr, p.parseXor)
+}
+
+func (p *exprParser) parseXor() (exprNode, error) {
+	return p.parseBinary(tokXor, p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(tokAnd, p.parseUnary)
+}
+
+// parseBinary 解析左结合的同级二元运算序列.
+func (p *exprParser) parseBinary(kind exprTokenKind, operand func() (exprNode, error)) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == kind {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokVar:
+		if tok.value >= p.n {
+			return nil, &ExprSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("variable x%d out of range (n=%d)", tok.value, p.n)}
+		}
+		return &varNode{index: tok.value}, nil
+	case tokConst:
+		return &constNode{value: tok.value}, nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &ExprSyntaxError{Pos: closing.pos, Msg: fmt.Sprintf("expected ')' to close '(' at position %d, found %s", tok.pos, closing)}
+		}
+		return inner, nil
+	default:
+		return nil, &ExprSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected variable, constant or '(', found %s", tok)}
+	}
+}
+
+// --- 位切片求值 ---
+
+type exprNode interface {
+	eval(n int) []uint64
+}
+
+type varNode struct{ index int }
+
+type constNode struct{ value int }
+
+type notNode struct{ operand exprNode }
+
+type binaryNode struct {
+	op          exprTokenKind
+	left, right exprNode
+}
+
+func (v *varNode) eval(n int) []uint64 { return variablePacked(n, v.index) }
+
+func (c *constNode) eval(n int) []uint64 {
+	out := make([]uint64, packedWords(n))
+	if c.value == 1 {
+		fillOnes(out, n)
+	}
+	return out
+}
+
+func (u *notNode) eval(n int) []uint64 {
+	out := u.operand.eval(n)
+	mask := lastWordMask(n)
+	for i := range out {
+		out[i] = ^out[i]
+	}
+	out[len(out)-1] &= mask
+	return out
+}
+
+func (b *binaryNode) eval(n int) []uint64 {
+	left := b.left.eval(n)
+	right := b.right.eval(n)
+	for i := range left {
+		switch b.op {
+		case tokOr:
+			left[i] |= right[i]
+		case tokXor:
+			left[i] ^= right[i]
+		case tokAnd:
+			left[i] &= right[i]
+		}
+	}
+	return left
+}
+
+// variableMasks[i] 是 i < 6 时变量 x_i 在单个 uint64 字内的真值表模式.
+var variableMasks = [6]uint64{
+	0xAAAAAAAAAAAAAAAA,
+	0xCCCCCCCCCCCCCCCC,
+	0xF0F0F0F0F0F0F0F0,
+	0xFF00FF00FF00FF00,
+	0xFFFF0000FFFF0000,
+	0xFFFFFFFF00000000,
+}
+
+// packedWords 返回 n 元函数打包真值表所需的 uint64 个数.
+func packedWords(n int) int {
+	return ((1 << n) + 63) / 64
+}
+
+// lastWordMask 返回最后一个字中有效位的掩码（n < 6 时只有低 2^n 位有效）.
+func lastWordMask(n int) uint64 {
+	if n >= 6 {
+		return ^uint64(0)
+	}
+	return (uint64(1) << uint(1<<n)) - 1
+}
+
+// fillOnes 将打包真值表置为全 1 常数函数.
+func fillOnes(words []uint64, n int) {
+	for i := range words {
+		words[i] = ^uint64(0)
+	}
+	words[len(words)-1] &= lastWordMask(n)
+}
+
+// variablePacked 返回坐标函数 x_i 的打包真值表.
+func variablePacked(n, i int) []uint64 {
+	out := make([]uint64, packedWords(n))
+	if i < 6 {
+		for w := range out {
+			out[w] = variableMasks[i]
+		}
+		out[len(out)-1] &= lastWordMask(n)
+		return out
+	}
+	for w := range out {
+		if (w>>(i-6))&1 == 1 {
+			out[w] = ^uint64(0)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package booleancore
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNewFromExpr 验证表达式构造与 ANF 构造的结果一致
+func TestNewFromExpr(t *testing.T) {
+	testCases := []struct {
+		name string
+		n    int
+		expr string
+		anf  string
+	}{
+		{"文档示例", 3, "(x0 and not x1) xor x2", "x0 + x0*x1 + x2"},
+		{"符号写法", 3, "x0 & ~x1 ^ x2", "x0 + x0*x1 + x2"},
+		{"或运算", 2, "x0 | x1", "x0 + x1 + x0*x1"},
+		{"优先级 and 高于 xor 高于 or", 3, "x0 | x1 ^ x1 & x2", "x0 + x1 + x1*x2 + x0*x1 + x0*x1*x2"},
+		{"ANF 写法", 4, "x0*x1 + x2*x3 + 1", "1 + x0*x1 + x2*x3"},
+		{"常数", 3, "true and !false", "1"},
+		{"跨字变量", 8, "x7 xor x6 and x0", "x7 + x0*x6"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewFromExpr(tc.n, tc.expr)
+			if err != nil {
+				t.Fatalf("NewFromExpr error: %v", err)
+			}
+			want, err := NewFromANF(tc.n, tc.anf)
+			if err != nil {
+				t.Fatalf("NewFromANF error: %v", err)
+			}
+			gotTT, wantTT := got.TruthTable(), want.TruthTable()
+			for i := range wantTT {
+				if gotTT[i] != wantTT[i] {
+					t.Fatalf("真值表第 %d 位不一致: 期望 %v, 实际 %v", i, wantTT, gotTT)
+				}
+			}
+		})
+	}
+}
+
+// TestNewFromExprSyntaxError 验证语法错误带有位置信息
+func TestNewFromExprSyntaxError(t *testing.T) {
+	testCases := []struct {
+		expr string
+		pos  int
+	}{
+		{"x0 and", 6},
+		{"(x0 xor x1", 10},
+		{"x0 $ x1", 3},
+		{"x0 and x5", 7},
+		{"x0 x1", 3},
+		{"foo", 0},
+	}
+	for _, tc := range testCases {
+		_, err := NewFromExpr(3, tc.expr)
+		var syntaxErr *ExprSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: 期望 ExprSyntaxError, 实际 %v", tc.expr, err)
+			continue
+		}
+		if syntaxErr.Pos != tc.pos {
+			t.Errorf("%q: 错误位置不匹配: 期望 %d, 实际 %d (%v)", tc.expr, tc.pos, syntaxErr.Pos, err)
+		}
+	}
+}
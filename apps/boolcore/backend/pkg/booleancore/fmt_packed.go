@@ -0,0 +1,123 @@
+package booleancore
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// 位打包的快速莫比乌斯变换：直接在 packedTruthTable 的 uint64 字上计算 ANF，
+// 不再把真值表展开成每项一个字节，内存只有字节版本的 1/8。
+//   - 低 6 个变量（字内）：a[j] ^= a[j^bit] 对所有第 i 位为 1 的 j 同时成立，
+//     等价于 w ^= (w << 2^i) & variableMasks[i]；
+//   - 其余变量（字间）：第 i-6 位为 1 的字异或上对应的配对字。
+// GF(2) 上莫比乌斯变换是对合，同一个函数既可由真值表求 ANF，也可由 ANF 还原真值表。
+
+// parallelFMTThreshold 以上的 n 在计算 ANF 缓存时使用并行变换.
+const parallelFMTThreshold = 20
+
+// fmtPackedInplace 对 n 元函数的打包真值表做原地莫比乌斯变换.
+func fmtPackedInplace(words []uint64, n int) {
+	for i := 0; i < n && i < 6; i++ {
+		shift := uint(1) << uint(i)
+		mask := variableMasks[i]
+		for w, v := range words {
+			words[w] = v ^ ((v << shift) & mask)
+		}
+	}
+	for i := 6; i < n; i++ {
+		step := 1 << uint(i-6)
+		for base := 0; base < len(words); base += step << 1 {
+			for j := base; j < base+step; j++ {
+				words[j+step] ^= words[j]
+			}
+		}
+	}
+}
+
+// fmtPackedInplaceParallel 是 fmtPackedInplace 的并行版本.
+// 字内的 6 级合并成一次遍历按字分块并行，字间的每一级按块或块内区间并行.
+func fmtPackedInplaceParallel(words []uint64, n, workers int) {
+	maxWorkers := runtime.GOMAXPROCS(0)
+	if workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers <= 1 || len(words) < workers*64 {
+		fmtPackedInplace(words, n)
+		return
+	}
+
+	parallelRange(len(words), workers, func(lo, hi int) {
+		for w := lo; w < hi; w++ {
+			v := words[w]
+			for i := 0; i < 6; i++ {
+				v ^= (v << (uint(1) << uint(i))) & variableMasks[i]
+			}
+			words[w] = v
+		}
+	})
+
+	for i := 6; i < n; i++ {
+		step := 1 << uint(i-6)
+		// 每个块的前半部分异或进后半部分，按前半部分的下标并行
+		half := len(words) / 2
+		parallelRange(half, workers, func(lo, hi int) {
+			for k := lo; k < hi; k++ {
+				j := (k/step)*(step<<1) + k%step
+				words[j+step] ^= words[j]
+			}
+		})
+	}
+}
+
+// parallelRange 将 [0, total) 均分给 workers 个 goroutine 执行并等待完成.
+func parallelRange(total, workers int, body func(lo, hi int)) {
+	chunk := (total + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < total; lo += chunk {
+		hi := lo + chunk
+		if hi > total {
+			hi = total
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			body(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// packedANFDegree 返回打包 ANF 系数中最高次单项式的次数.
+func packedANFDegree(coeffs []uint64, n int) int {
+	maxDegree := 0
+	for w, v := range coeffs {
+		if v == 0 {
+			continue
+		}
+		high := bits.OnesCount(uint(w))
+		if high+min(n, 6) <= maxDegree {
+			continue // 该字内的单项式次数不可能更高
+		}
+		for v != 0 {
+			low := bits.TrailingZeros64(v)
+			if d := high + bits.OnesCount(uint(low)); d > maxDegree {
+				maxDegree = d
+			}
+			v &= v - 1
+		}
+	}
+	return maxDegree
+}
+
+// packedSupport 返回打包向量中所有为 1 的下标.
+func packedSupport(words []uint64) []int {
+	indices := make([]int, 0)
+	for w, v := range words {
+		for v != 0 {
+			indices = append(indices, w<<6|bits.TrailingZeros64(v))
+			v &= v - 1
+		}
+	}
+	return indices
+}
@@ -0,0 +1,80 @@
+package booleancore
+
+import (
+	"math/bits"
+	"reflect"
+	"testing"
+)
+
+// TestPackedFMT 将打包的莫比乌斯变换与逐字节版本逐项比较
+func TestPackedFMT(t *testing.T) {
+	for n := 1; n <= 12; n++ {
+		bf := randomFunction(t, n, int64(n))
+
+		want := bf.TruthTable()
+		fmtInplace(want)
+
+		words := append([]uint64(nil), bf.packedTruthTable...)
+		fmtPackedInplace(words, n)
+		if got := truthTableFromUint64Slice(words, 1<<n); !reflect.DeepEqual(got, want) {
+			t.Fatalf("n=%d: 打包 FMT 与逐字节 FMT 结果不一致", n)
+		}
+
+		// 莫比乌斯变换是对合
+		fmtPackedInplace(words, n)
+		if !reflect.DeepEqual(words, bf.packedTruthTable) {
+			t.Fatalf("n=%d: 两次变换后未还原真值表", n)
+		}
+
+		wantDegree := 0
+		for i, c := range want {
+			if c == 1 && bits.OnesCount(uint(i)) > wantDegree {
+				wantDegree = bits.OnesCount(uint(i))
+			}
+		}
+		if got := bf.AlgebraicDegree(); got != wantDegree {
+			t.Errorf("n=%d: 代数次数应为 %d, 实际 %d", n, wantDegree, got)
+		}
+	}
+}
+
+// TestPackedFMTParallel 验证并行版本与串行版本一致
+func TestPackedFMTParallel(t *testing.T) {
+	bf := randomFunction(t, 16, 5)
+
+	want := append([]uint64(nil), bf.packedTruthTable...)
+	fmtPackedInplace(want, 16)
+
+	for _, workers := range []int{2, 3, 8} {
+		got := append([]uint64(nil), bf.packedTruthTable...)
+		fmtPackedInplaceParallel(got, 16, workers)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: 并行 FMT 与串行结果不一致", workers)
+		}
+	}
+}
+
+// BenchmarkAlgebraicDegree 测量 n=20 时打包 ANF 的计算开销
+func BenchmarkAlgebraicDegree(b *testing.B) {
+	src := randomFunction(b, 20, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf := &BooleanFunction{n: src.n, packedTruthTable: src.packedTruthTable}
+		_ = bf.AlgebraicDegree()
+	}
+}
+
+// fmtInplace 是逐字节的快速莫比乌斯变换，作为打包版本的对照.
+func fmtInplace(tt []byte) {
+	n := len(tt)
+	k := bits.TrailingZeros(uint(n))
+	for i := 0; i < k; i++ {
+		bit := 1 << i
+		for j := 0; j < n; j++ {
+			if (j & bit) != 0 {
+				tt[j] ^= tt[j^bit]
+			}
+		}
+	}
+}
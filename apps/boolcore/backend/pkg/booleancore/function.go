@@ -14,7 +14,7 @@ import (
 //   - FromInt(int)             整数构造（以二进制位为真值）
 //   - FromANF(string)          代数正规型构造，如 "x0 + x1*x2 + 1"
 //   - FromHex(string)          十六进制构造
-//   - FromExpr(string)         布尔表达式构造，如 "(x0 and not x1) xor x2"
+//   - FromExpr(string)         布尔表达式构造，如 "(x0 and not x1) xor x2"（见 expr.go）
 
 //
 // 真值表的索引规则与 SageMath 中的 BooleanFunction 保持一致：
@@ -40,9 +40,10 @@ type BooleanFunction struct {
 	n                int      // n 元
 	packedTruthTable []uint64 // 【升级】使用位打包存储真值表,每个元素存64个布尔值
 	// 缓存一些计算结果，避免重复计算
-	walshSpectrum           []int64 // 缓存Walsh 频谱
-	anfCoefficients         []byte  // 缓存 ANF 系数其实，该方法是布尔函数一种较为重要的表达方法
-	autocorrelationSpectrum []int64 // 缓存自相关频谱
+	// 缓存是并发安全的（见 memo.go），同一个 *BooleanFunction 可被多个 goroutine 同时分析
+	walshSpectrum           lazy[[]int64]  // 缓存Walsh 频谱
+	anfCoefficients         lazy[[]uint64] // 缓存 ANF 系数（与真值表相同的位打包格式）其实，该方法是布尔函数一种较为重要的表达方法
+	autocorrelationSpectrum lazy[[]int64]  // 缓存自相关频谱
 }
 
 // NewFromTruthTable 通过真值表创建一个布尔函数.
@@ -142,16 +143,12 @@ func NewFromANF(n int, anfString string) (*BooleanFunction, error) {
 		return nil, fmt.Errorf("failed to parse ANF: %v", err)
 	}
 
-	// 使用莫比乌斯逆变换将 ANF 系数转换为真值表
-	length := 1 << n
-	truthTable := make([]byte, length)
-	copy(truthTable, coefficients)
-	
-	// 调用逆变换
-	fmtInverseInplace(truthTable)
+	// 使用莫比乌斯逆变换将 ANF 系数转换为真值表（在打包的字上进行）
+	packed := uint64SliceFromTruthTable(coefficients)
+	fmtPackedInplace(packed, n)
 
 	// 创建布尔函数
-	return NewFromTruthTable(truthTable)
+	return &BooleanFunction{n: n, packedTruthTable: packed}, nil
 }
 
 // parseANF 解析 ANF 字符串并返回系数向量
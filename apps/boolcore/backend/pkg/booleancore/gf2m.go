@@ -0,0 +1,123 @@
+package booleancore
+
+import (
+	"fmt"
+)
+
+// 有限域 GF(2^m) 上的运算，供 PS_ap、Carlet–Feng、Tu–Deng 等基于域结构的构造使用.
+// 域元素用 [0, 2^m) 内的整数表示：第 i 位是多项式基下 z^i 的系数，
+// 与布尔函数输入向量的第 i 位 x_i 一一对应，因此真值表下标可以直接当作域元素。
+// 乘法通过本原元 α = z 的指数/对数表完成。
+
+// primitivePolynomials[m] 是 GF(2)[z] 上 m 次本原多项式（含最高次项）.
+var primitivePolynomials = [...]int{
+	1:  0x3,     // z + 1
+	2:  0x7,     // z^2 + z + 1
+	3:  0xb,     // z^3 + z + 1
+	4:  0x13,    // z^4 + z + 1
+	5:  0x25,    // z^5 + z^2 + 1
+	6:  0x43,    // z^6 + z + 1
+	7:  0x83,    // z^7 + z + 1
+	8:  0x11d,   // z^8 + z^4 + z^3 + z^2 + 1
+	9:  0x211,   // z^9 + z^4 + 1
+	10: 0x409,   // z^10 + z^3 + 1
+	11: 0x805,   // z^11 + z^2 + 1
+	12: 0x1053,  // z^12 + z^6 + z^4 + z + 1
+	13: 0x201b,  // z^13 + z^4 + z^3 + z + 1
+	14: 0x4443,  // z^14 + z^10 + z^6 + z + 1
+	15: 0x8003,  // z^15 + z + 1
+	16: 0x1100b, // z^16 + z^12 + z^3 + z + 1
+}
+
+// MaxGF2mDegree 是 NewGF2m 支持的最大扩张次数.
+const MaxGF2mDegree = len(primitivePolynomials) - 1
+
+// GF2m 是有限域 GF(2^m)，构造后只读，可并发使用.
+type GF2m struct {
+	m    int
+	poly int
+	exp  []int // exp[i] = α^i，长度 2(2^m-1)，乘法时指数相加无需取模
+	log  []int // log[a] = log_α(a)，log[0] 无意义
+}
+
+// NewGF2m 返回以本原多项式 primitivePolynomials[m] 定义的 GF(2^m)，1 ≤ m ≤ 16.
+func NewGF2m(m int) (*GF2m, error) {
+	if m < 1 || m > MaxGF2mDegree {
+		return nil, fmt.Errorf("field degree must be in [1, %d], got %d", MaxGF2mDegree, m)
+	}
+	order := 1<<uint(m) - 1
+	F := &GF2m{m: m, poly: primitivePolynomials[m], exp: make([]int, 2*order), log: make([]int, order+1)}
+	a := 1
+	for i := 0; i < order; i++ {
+		F.exp[i], F.exp[i+order] = a, a
+		F.log[a] = i
+		a <<= 1
+		if a>>uint(m) != 0 {
+			a ^= F.poly
+		}
+	}
+	return F, nil
+}
+
+// Degree 返回扩张次数 m.
+func (F *GF2m) Degree() int { return F.m }
+
+// Size 返回域的元素个数 2^m.
+func (F *GF2m) Size() int { return 1 << uint(F.m) }
+
+// Mul 返回 a·b.
+func (F *GF2m) Mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return F.exp[F.log[a]+F.log[b]]
+}
+
+// Inv 返回 a^{2^m-2}：a ≠ 0 时即 a^{-1}，并约定 Inv(0) = 0（PS_ap 等构造中的惯例）.
+func (F *GF2m) Inv(a int) int {
+	if a == 0 {
+		return 0
+	}
+	order := F.Size() - 1
+	return F.exp[(order-F.log[a])%order]
+}
+
+// Pow 返回 a^e（e ≥ 0，约定 0^0 = 1）.
+func (F *GF2m) Pow(a, e int) int {
+	if e == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	order := F.Size() - 1
+	return F.exp[(F.log[a]*(e%order))%order]
+}
+
+// Exp 返回本原元的幂 α^i，i 可以是任意整数.
+func (F *GF2m) Exp(i int) int {
+	order := F.Size() - 1
+	i %= order
+	if i < 0 {
+		i += order
+	}
+	return F.exp[i]
+}
+
+// Log 返回 a 的离散对数 log_α(a) ∈ [0, 2^m-1)，a = 0 时返回错误.
+func (F *GF2m) Log(a int) (int, error) {
+	if a <= 0 || a >= F.Size() {
+		return 0, fmt.Errorf("logarithm is only defined for nonzero elements of GF(2^%d), got %d", F.m, a)
+	}
+	return F.log[a], nil
+}
+
+// Trace 返回绝对迹 Tr(a) = a + a^2 + ... + a^{2^{m-1}} ∈ {0, 1}.
+func (F *GF2m) Trace(a int) byte {
+	t, s := 0, a
+	for i := 0; i < F.m; i++ {
+		t ^= s
+		s = F.Mul(s, s)
+	}
+	return byte(t)
+}
@@ -0,0 +1,64 @@
+package booleancore
+
+import (
+	"context"
+	"sync"
+)
+
+// lazy 是并发安全的惰性缓存，零值可直接使用.
+// 同一时刻只有一个 goroutine 执行计算，其余调用方等待其结果；
+// 计算失败（例如 ctx 被取消）不会写入缓存，下一个调用方会重新计算。
+// 与 sync.Once 不同，等待中的调用方可以通过自己的 ctx 提前返回。
+//
+// 缓存的值在所有调用方之间共享，必须视为只读。
+type lazy[T any] struct {
+	mu       sync.Mutex
+	done     bool
+	val      T
+	inflight chan struct{} // 非 nil 表示有计算正在进行，计算结束时关闭
+}
+
+// get 返回缓存值，尚未计算时调用 compute 计算并缓存.
+func (l *lazy[T]) get(ctx context.Context, compute func(ctx context.Context) (T, error)) (T, error) {
+	for {
+		l.mu.Lock()
+		if l.done {
+			v := l.val
+			l.mu.Unlock()
+			return v, nil
+		}
+		if l.inflight == nil {
+			ch := make(chan struct{})
+			l.inflight = ch
+			l.mu.Unlock()
+			return l.run(ctx, ch, compute)
+		}
+		ch := l.inflight
+		l.mu.Unlock()
+
+		select {
+		case <-ch: // 计算结束，重新检查（失败时由当前调用方接手计算）
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// run 执行计算并在结束时唤醒等待者，compute panic 时同样释放 inflight.
+func (l *lazy[T]) run(ctx context.Context, ch chan struct{}, compute func(ctx context.Context) (T, error)) (v T, err error) {
+	ok := false
+	defer func() {
+		l.mu.Lock()
+		if ok {
+			l.val = v
+			l.done = true
+		}
+		l.inflight = nil
+		l.mu.Unlock()
+		close(ch)
+	}()
+	v, err = compute(ctx)
+	ok = err == nil
+	return v, err
+}
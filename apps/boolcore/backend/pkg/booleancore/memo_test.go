@@ -0,0 +1,90 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestConcurrentAnalysis 验证同一个函数对象可被多个 goroutine 同时分析（配合 -race 运行）
+func TestConcurrentAnalysis(t *testing.T) {
+	want := randomFunction(t, 10, 7)
+	expected, _ := AnalyzeSelected(want, nil)
+
+	bf := randomFunction(t, 10, 7)
+	var wg sync.WaitGroup
+	results := make([]AnalyzeResult, 8)
+	for w := range results {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w], _ = AnalyzeSelected(bf, nil)
+		}(w)
+	}
+	wg.Wait()
+
+	for w, res := range results {
+		if !reflect.DeepEqual(res, expected) {
+			t.Errorf("worker %d 的分析结果与串行结果不一致", w)
+		}
+	}
+}
+
+// TestCachedSlicesAreCopies 验证修改公开方法返回的切片不会破坏缓存
+func TestCachedSlicesAreCopies(t *testing.T) {
+	bf := randomFunction(t, 8, 9)
+
+	wht := bf.WalshHadamardTransform()
+	wht[0] = 12345
+	if got := bf.WalshHadamardTransform()[0]; got == 12345 {
+		t.Error("WalshHadamardTransform 返回了缓存本身")
+	}
+
+	ac := bf.Autocorrelation()
+	ac[0] = -1
+	if got := bf.Autocorrelation()[0]; got != 256 {
+		t.Errorf("Autocorrelation 缓存被修改: r(0) = %d", got)
+	}
+
+	anf := bf.AlgebraicNormalFormCoefficients()
+	degree := bf.AlgebraicDegree()
+	for i := range anf {
+		anf[i] = 1
+	}
+	if got := bf.AlgebraicDegree(); got != degree {
+		t.Errorf("ANF 缓存被修改: 次数从 %d 变为 %d", degree, got)
+	}
+}
+
+// TestLazyRetryAfterError 验证计算失败不会写入缓存，且并发调用只计算一次
+func TestLazyRetryAfterError(t *testing.T) {
+	var l lazy[int]
+	if _, err := l.get(context.Background(), func(context.Context) (int, error) {
+		return 0, context.Canceled
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望 context.Canceled, 实际 %v", err)
+	}
+
+	var calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.get(context.Background(), func(context.Context) (int, error) {
+				atomic.AddInt32(&calls, 1)
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("get = %d, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("计算应只执行一次, 实际 %d 次", calls)
+	}
+}
@@ -0,0 +1,119 @@
+package booleancore
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 布尔函数之间的运算：全部直接在打包的 uint64 字上完成，一次处理 64 个输入点，
+// 结果是新的 BooleanFunction（缓存为空），原函数保持不变。
+// 二元运算要求两个函数的变量个数相同，否则返回错误。
+
+// Xor 返回 f ⊕ g.
+func (f *BooleanFunction) Xor(g *BooleanFunction) (*BooleanFunction, error) {
+	return f.combine(g, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// And 返回 f·g.
+func (f *BooleanFunction) And(g *BooleanFunction) (*BooleanFunction, error) {
+	return f.combine(g, func(a, b uint64) uint64 { return a & b })
+}
+
+// Or 返回 f ∨ g = f ⊕ g ⊕ f·g.
+func (f *BooleanFunction) Or(g *BooleanFunction) (*BooleanFunction, error) {
+	return f.combine(g, func(a, b uint64) uint64 { return a | b })
+}
+
+// Not 返回 f ⊕ 1.
+func (f *BooleanFunction) Not() *BooleanFunction {
+	packed := make([]uint64, len(f.packedTruthTable))
+	for w, v := range f.packedTruthTable {
+		packed[w] = ^v
+	}
+	packed[len(packed)-1] &= lastWordMask(f.n)
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}
+}
+
+// AddAffine 返回 f(x) ⊕ a·x ⊕ c，其中 a 是 [0, 2^n) 内的线性函数掩码（第 i 位对应 x_i），c 为 0 或 1.
+// 加上仿射函数不改变绝对 Walsh 谱（只是 W(ω) 平移到 W(ω⊕a) 并可能变号），常用于构造等价函数。
+func (f *BooleanFunction) AddAffine(a int, c byte) (*BooleanFunction, error) {
+	if a < 0 || a >= 1<<f.n {
+		return nil, fmt.Errorf("linear mask %d out of range (must be in [0, 2^%d))", a, f.n)
+	}
+	if c > 1 {
+		return nil, fmt.Errorf("constant term must be 0 or 1, got %d", c)
+	}
+	linear := linearPacked(f.n, a)
+	packed := make([]uint64, len(f.packedTruthTable))
+	for w, v := range f.packedTruthTable {
+		packed[w] = v ^ linear[w]
+	}
+	if c == 1 {
+		for w := range packed {
+			packed[w] = ^packed[w]
+		}
+		packed[len(packed)-1] &= lastWordMask(f.n)
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}, nil
+}
+
+// Equal 判断两个函数是否相同（变量个数不同视为不同）.
+func (f *BooleanFunction) Equal(g *BooleanFunction) bool {
+	if f.n != g.n {
+		return false
+	}
+	for w, v := range f.packedTruthTable {
+		if v != g.packedTruthTable[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// HammingDistance 返回 f 与 g 的汉明距离 d(f, g) = wt(f ⊕ g).
+func (f *BooleanFunction) HammingDistance(g *BooleanFunction) (int, error) {
+	if f.n != g.n {
+		return 0, fmt.Errorf("number of variables mismatch: %d vs %d", f.n, g.n)
+	}
+	distance := 0
+	for w, v := range f.packedTruthTable {
+		distance += bits.OnesCount64(v ^ g.packedTruthTable[w])
+	}
+	return distance, nil
+}
+
+// --- 私有实现 ---
+
+// combine 对两个函数的打包真值表逐字应用 op.
+// op(0, 0) 必须为 0，这样最后一个字中的无效位保持为 0.
+func (f *BooleanFunction) combine(g *BooleanFunction, op func(a, b uint64) uint64) (*BooleanFunction, error) {
+	if f.n != g.n {
+		return nil, fmt.Errorf("number of variables mismatch: %d vs %d", f.n, g.n)
+	}
+	packed := make([]uint64, len(f.packedTruthTable))
+	for w, v := range f.packedTruthTable {
+		packed[w] = op(v, g.packedTruthTable[w])
+	}
+	return &BooleanFunction{n: f.n, packedTruthTable: packed}, nil
+}
+
+// linearPacked 返回线性函数 a·x 的打包真值表.
+// 低 6 位决定每个字内的模式，高位决定整个字是否取反.
+func linearPacked(n, a int) []uint64 {
+	var low uint64
+	for i := 0; i < 6 && i < n; i++ {
+		if (a>>i)&1 == 1 {
+			low ^= variableMasks[i]
+		}
+	}
+	high := a >> 6
+	out := make([]uint64, packedWords(n))
+	for w := range out {
+		out[w] = low
+		if bits.OnesCount(uint(w&high))&1 == 1 {
+			out[w] = ^low
+		}
+	}
+	out[len(out)-1] &= lastWordMask(n)
+	return out
+}
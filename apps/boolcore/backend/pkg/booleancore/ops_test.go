@@ -0,0 +1,82 @@
+package booleancore
+
+import (
+	"math/bits"
+	"testing"
+)
+
+// TestBooleanOperations 将打包字上的运算与逐点计算的真值表对比
+func TestBooleanOperations(t *testing.T) {
+	for _, n := range []int{1, 3, 6, 8} {
+		f := randomFunction(t, n, int64(n))
+		g := randomFunction(t, n, int64(n)+100)
+		ft, gt := f.TruthTable(), g.TruthTable()
+
+		xor, _ := f.Xor(g)
+		and, _ := f.And(g)
+		or, _ := f.Or(g)
+		not := f.Not()
+		xt, at, ot, nt := xor.TruthTable(), and.TruthTable(), or.TruthTable(), not.TruthTable()
+		distance := 0
+		for x := range ft {
+			if xt[x] != ft[x]^gt[x] || at[x] != ft[x]&gt[x] || ot[x] != ft[x]|gt[x] || nt[x] != 1-ft[x] {
+				t.Fatalf("n=%d: 运算结果在 x=%d 处错误", n, x)
+			}
+			distance += int(ft[x] ^ gt[x])
+		}
+		if d, _ := f.HammingDistance(g); d != distance {
+			t.Errorf("n=%d: HammingDistance = %d, 期望 %d", n, d, distance)
+		}
+		if w := not.HammingWeight(); w != 1<<n-f.HammingWeight() {
+			t.Errorf("n=%d: Not 的汉明重量 = %d，最后一个字的无效位未清零", n, w)
+		}
+		if !xor.Not().Equal(mustXor(t, f, g.Not())) {
+			t.Errorf("n=%d: f⊕g⊕1 不等于 f⊕(g⊕1)", n)
+		}
+
+		// 加上仿射函数后 W(ω) 平移到 W(ω⊕a) 并按 c 变号
+		a := (1<<n - 1) & 0x5b
+		h, err := f.AddAffine(a, 1)
+		if err != nil {
+			t.Fatalf("AddAffine error: %v", err)
+		}
+		ht := h.TruthTable()
+		for x := range ft {
+			if want := ft[x] ^ byte(bits.OnesCount(uint(a&x))&1) ^ 1; ht[x] != want {
+				t.Fatalf("n=%d: AddAffine 在 x=%d 处错误", n, x)
+			}
+		}
+		wf, wh := f.WalshHadamardTransform(), h.WalshHadamardTransform()
+		for w := range wf {
+			if wh[w^a] != -wf[w] {
+				t.Fatalf("n=%d: W_h(%d) = %d, 期望 %d", n, w^a, wh[w^a], -wf[w])
+			}
+		}
+	}
+
+	f := randomFunction(t, 4, 1)
+	g := randomFunction(t, 5, 1)
+	if _, err := f.Xor(g); err == nil {
+		t.Error("变量个数不同时 Xor 应返回错误")
+	}
+	if _, err := f.HammingDistance(g); err == nil {
+		t.Error("变量个数不同时 HammingDistance 应返回错误")
+	}
+	if f.Equal(g) {
+		t.Error("变量个数不同的函数不应相等")
+	}
+	if _, err := f.AddAffine(16, 0); err == nil {
+		t.Error("越界的线性掩码应返回错误")
+	}
+	if _, err := f.AddAffine(1, 2); err == nil {
+		t.Error("非法的常数项应返回错误")
+	}
+}
+
+func mustXor(t *testing.T, f, g *BooleanFunction) *BooleanFunction {
+	h, err := f.Xor(g)
+	if err != nil {
+		t.Fatalf("Xor error: %v", err)
+	}
+	return h
+}
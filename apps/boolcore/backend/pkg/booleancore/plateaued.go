@@ -0,0 +1,110 @@
+package booleancore
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// 按 Walsh 谱的形状分类，全部基于缓存的 Walsh 谱，复杂度 O(2^n)：
+//   - s-plateaued：W(ω) ∈ {0, ±2^{(n+s)/2}}，幅值 λ = 2^{(n+s)/2}，Walsh 支撑大小为 2^{n-s}；
+//     由 Parseval 等式 λ²·|supp| = 2^{2n}，λ 必为 2 的幂且 s ≡ n (mod 2)；
+//   - bent 即 0-plateaued（n 为偶数），仿射函数即 n-plateaued；
+//   - semi-bent：n 为偶数的 2-plateaued 函数；near-bent：n 为奇数的 1-plateaued 函数
+//     （部分文献把后者也称为 semi-bent，这里按奇偶区分）。
+
+// SpectralClass 是 Walsh 谱形状的分类标签.
+type SpectralClass string
+
+const (
+	SpectralAffine    SpectralClass = "affine"    // 仿射函数（n-plateaued）
+	SpectralBent      SpectralClass = "bent"      // 0-plateaued
+	SpectralSemiBent  SpectralClass = "semi-bent" // n 为偶数，2-plateaued
+	SpectralNearBent  SpectralClass = "near-bent" // n 为奇数，1-plateaued
+	SpectralPlateaued SpectralClass = "plateaued" // 其余的 s-plateaued 函数
+	SpectralGeneral   SpectralClass = "general"   // 非 plateaued
+)
+
+// PlateauedOrder 返回 f 作为 s-plateaued 函数的阶 s 与幅值 λ = 2^{(n+s)/2}，不是 plateaued 函数时 ok 为 false.
+func (f *BooleanFunction) PlateauedOrder() (s int, amplitude int64, ok bool) {
+	for _, w := range f.walsh() {
+		if w < 0 {
+			w = -w
+		}
+		if w == 0 {
+			continue
+		}
+		if amplitude == 0 {
+			amplitude = w
+		} else if w != amplitude {
+			return 0, 0, false
+		}
+	}
+	return 2*bits.TrailingZeros64(uint64(amplitude)) - f.n, amplitude, true
+}
+
+// IsPlateaued 判断 f 是否为 plateaued 函数（非零 Walsh 值的绝对值都相同）.
+func (f *BooleanFunction) IsPlateaued() bool {
+	_, _, ok := f.PlateauedOrder()
+	return ok
+}
+
+// IsSemiBent 判断 f 是否为 semi-bent 函数：n 为偶数且 W(ω) ∈ {0, ±2^{(n+2)/2}}.
+func (f *BooleanFunction) IsSemiBent() bool {
+	s, _, ok := f.PlateauedOrder()
+	return ok && f.n%2 == 0 && s == 2
+}
+
+// IsNearBent 判断 f 是否为 near-bent 函数：n 为奇数且 W(ω) ∈ {0, ±2^{(n+1)/2}}.
+func (f *BooleanFunction) IsNearBent() bool {
+	s, _, ok := f.PlateauedOrder()
+	return ok && f.n%2 == 1 && s == 1
+}
+
+// WalshValues 返回 Walsh 谱中出现的所有不同取值（升序）.
+func (f *BooleanFunction) WalshValues() []int64 {
+	seen := make(map[int64]bool)
+	values := make([]int64, 0)
+	for _, w := range f.walsh() {
+		if !seen[w] {
+			seen[w] = true
+			values = append(values, w)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+// IsThreeValued 判断 Walsh 谱是否恰好取三个不同的值（例如 {0, ±λ}，正负号都出现的非 bent plateaued 函数）.
+func (f *BooleanFunction) IsThreeValued() bool {
+	return len(f.WalshValues()) == 3
+}
+
+// WalshSupport 返回 Walsh 支撑 {ω : W(ω) ≠ 0}（升序）.
+func (f *BooleanFunction) WalshSupport() []int {
+	support := make([]int, 0)
+	for w, v := range f.walsh() {
+		if v != 0 {
+			support = append(support, w)
+		}
+	}
+	return support
+}
+
+// SpectralClassification 返回 f 的 Walsh 谱形状分类，仿射函数优先于其他标签（如 n=2 时的 2-plateaued）.
+func (f *BooleanFunction) SpectralClassification() SpectralClass {
+	s, _, ok := f.PlateauedOrder()
+	switch {
+	case !ok:
+		return SpectralGeneral
+	case s == f.n:
+		return SpectralAffine
+	case s == 0:
+		return SpectralBent
+	case s == 2 && f.n%2 == 0:
+		return SpectralSemiBent
+	case s == 1 && f.n%2 == 1:
+		return SpectralNearBent
+	default:
+		return SpectralPlateaued
+	}
+}
@@ -0,0 +1,93 @@
+package booleancore
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSpectralClassification 检查 plateaued 阶数、幅值、三值谱与谱分类
+func TestSpectralClassification(t *testing.T) {
+	cases := []struct {
+		n          int
+		anf        string
+		s          int // -1 表示不是 plateaued 函数
+		class      SpectralClass
+		threeValue bool
+	}{
+		{4, "x0*x1 + x2*x3", 0, SpectralBent, false},            // W ∈ {±4}
+		{4, "x0*x1", 2, SpectralSemiBent, true},                 // W ∈ {0, ±8}
+		{5, "x0*x1 + x2*x3", 1, SpectralNearBent, true},         // W ∈ {0, ±8}
+		{3, "x0*x1 + x1*x2 + x0*x2", 1, SpectralNearBent, true}, // 择多函数
+		{6, "x0*x1 + x2*x3", 2, SpectralSemiBent, true},         // 两个变量不出现
+		{6, "x0*x1", 4, SpectralPlateaued, true},                // 4-plateaued
+		{3, "x0 + 1", 3, SpectralAffine, false},                 // W ∈ {0, -8}
+		{2, "x0 + x1", 2, SpectralAffine, false},                // 仿射优先于 n=2 时的 semi-bent
+		{3, "x0*x1*x2", -1, SpectralGeneral, true},              // W ∈ {6, ±2}：三值但不是 plateaued
+		{6, "x0*x1*x2 + x3*x4*x5", -1, SpectralGeneral, false},  // 非 plateaued
+		{4, "x0*x1*x2 + x0*x3 + x1*x3", -1, SpectralGeneral, false},
+	}
+	for _, tc := range cases {
+		f, err := NewFromANF(tc.n, tc.anf)
+		if err != nil {
+			t.Fatalf("NewFromANF(%q) error: %v", tc.anf, err)
+		}
+		s, amplitude, ok := f.PlateauedOrder()
+		if (tc.s >= 0) != ok || (ok && s != tc.s) {
+			t.Errorf("%s (n=%d): PlateauedOrder() = %d, %v, 期望 s=%d", tc.anf, tc.n, s, ok, tc.s)
+		}
+		if ok {
+			if amplitude*amplitude != int64(1)<<uint(tc.n+s) {
+				t.Errorf("%s: 幅值 %d 不等于 2^{(n+s)/2}", tc.anf, amplitude)
+			}
+			if len(f.WalshSupport()) != 1<<uint(tc.n-s) {
+				t.Errorf("%s: Walsh 支撑大小应为 2^{n-s}, 实际 %d", tc.anf, len(f.WalshSupport()))
+			}
+		}
+		if got := f.SpectralClassification(); got != tc.class {
+			t.Errorf("%s (n=%d): SpectralClassification() = %s, 期望 %s", tc.anf, tc.n, got, tc.class)
+		}
+		if got := f.IsThreeValued(); got != tc.threeValue {
+			t.Errorf("%s (n=%d): IsThreeValued() = %v, 期望 %v (值集 %v)", tc.anf, tc.n, got, tc.threeValue, f.WalshValues())
+		}
+		// n=2 的仿射函数按定义也是 semi-bent，只是分类标签取 affine
+		if (tc.class == SpectralSemiBent && !f.IsSemiBent()) || (tc.class == SpectralNearBent && !f.IsNearBent()) ||
+			(!ok && (f.IsSemiBent() || f.IsNearBent())) {
+			t.Errorf("%s (n=%d): IsSemiBent/IsNearBent 与分类 %s 不一致", tc.anf, tc.n, tc.class)
+		}
+		if f.IsPlateaued() != ok || (tc.class == SpectralBent) != f.IsBent() {
+			t.Errorf("%s (n=%d): IsPlateaued/IsBent 与分类不一致", tc.anf, tc.n)
+		}
+	}
+
+	maj, _ := NewFromANF(3, "x0*x1 + x1*x2 + x0*x2")
+	if got := maj.WalshValues(); !reflect.DeepEqual(got, []int64{-4, 0, 4}) {
+		t.Errorf("择多函数的 Walsh 值集应为 [-4 0 4], 实际 %v", got)
+	}
+	if got := maj.WalshSupport(); !reflect.DeepEqual(got, []int{1, 2, 4, 7}) {
+		t.Errorf("择多函数的 Walsh 支撑应为 [1 2 4 7], 实际 %v", got)
+	}
+}
+
+// TestPlateauedOrderProperty 检查分析结果中的 plateaued 阶数：不是 plateaued 函数时不给出阶数
+func TestPlateauedOrderProperty(t *testing.T) {
+	for _, tc := range []struct {
+		anf       string
+		plateaued bool
+		order     interface{}
+	}{
+		{"x0*x1", true, 4},
+		{"x0*x1*x2 + x3*x4*x5", false, nil},
+	} {
+		f, _ := NewFromANF(6, tc.anf)
+		res, err := AnalyzeSelected(f, []string{PropPlateauedOrder})
+		if err != nil {
+			t.Fatalf("AnalyzeSelected error: %v", err)
+		}
+		if plateaued, _ := res.Value(PropIsPlateaued); plateaued != tc.plateaued {
+			t.Errorf("%s: isPlateaued = %v, 期望 %v", tc.anf, plateaued, tc.plateaued)
+		}
+		if order, ok := res.Value(PropPlateauedOrder); !ok || order != tc.order {
+			t.Errorf("%s: plateauedOrder = %v, %v, 期望 %v", tc.anf, order, ok, tc.order)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package booleancore
+
+import (
+	"context"
+)
+
+// 进度报告：n >= 14 时代数免疫度与 FAA/FAI 的计算可能持续很久，
+// 通过 WithProgress 在 context 上挂载回调，即可在计算过程中获知当前检查的次数、
+// 线性方程组矩阵的规模以及高斯消元进行到的主元行。
+// 没有挂载回调时所有报告点都是空操作，不影响性能。
+
+// 进度事件的阶段名.
+const (
+	StageAlgebraicImmunity = "algebraicImmunity" // 求零化子（findAnnihilatorExists / findLowestDegreeAnnihilatorFull）
+	StageFastAttack        = "fastAttack"        // FAA/FAI 的见证搜索（hasFastAttackWitness）
+	StageSearch            = "search"            // 启发式搜索（Search），Rows 为总迭代数，Row 为已完成的迭代数
+)
+
+// ProgressEvent 描述一次进度报告.
+type ProgressEvent struct {
+	Stage   string `json:"stage"`             // 阶段名，见 StageAlgebraicImmunity 等常量
+	Metric  string `json:"metric,omitempty"`  // FAA/FAAWithPositiveDegree/FAI，仅 fastAttack 阶段；search 阶段为算法名
+	Degree  int    `json:"degree"`            // 当前检查的次数：零化子次数 d，或 fastAttack 中的 deg(g)
+	DegreeH int    `json:"degreeH,omitempty"` // fastAttack 中 deg(fg) 的上界
+	Target  string `json:"target,omitempty"`  // 零化子针对的函数: "f" 或 "f+1"；fastAttack 中为矩阵类型
+	Rows    int    `json:"rows"`              // 当前矩阵行数（方程数）
+	Cols    int    `json:"cols"`              // 当前矩阵列数（未知数个数）
+	Row     int    `json:"row"`               // 高斯消元已确定的主元行数，完成时等于矩阵的秩
+	Done    bool   `json:"done,omitempty"`    // 当前矩阵的消元是否已完成
+
+	Cost *float64 `json:"cost,omitempty"` // search 阶段目前找到的最小代价（可以为 0），其他阶段为 nil
+}
+
+// ProgressFunc 是进度回调，在计算所在的 goroutine 中同步调用，应尽快返回.
+type ProgressFunc func(ProgressEvent)
+
+type progressKey struct{}
+
+type progressState struct {
+	fn   ProgressFunc
+	base ProgressEvent
+}
+
+// WithProgress 返回挂载了进度回调的 context，可传给所有 ...Ctx 方法以及 AnalyzeSelectedCtx.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressState{fn: fn})
+}
+
+// withProgressBase 为后续的报告设置阶段、次数与矩阵规模等公共字段.
+func withProgressBase(ctx context.Context, base ProgressEvent) context.Context {
+	st, ok := ctx.Value(progressKey{}).(*progressState)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressState{fn: st.fn, base: base})
+}
+
+// withProgressMatrix 在已有的公共字段上补充即将消元的矩阵信息.
+func withProgressMatrix(ctx context.Context, target string, rows, cols int) context.Context {
+	st, ok := ctx.Value(progressKey{}).(*progressState)
+	if !ok {
+		return ctx
+	}
+	base := st.base
+	base.Target = target
+	base.Rows = rows
+	base.Cols = cols
+	return context.WithValue(ctx, progressKey{}, &progressState{fn: st.fn, base: base})
+}
+
+// reportProgress 以当前主元行 row 发送一次进度事件.
+func reportProgress(ctx context.Context, row int, done bool) {
+	st, ok := ctx.Value(progressKey{}).(*progressState)
+	if !ok {
+		return
+	}
+	ev := st.base
+	ev.Row = row
+	ev.Done = done
+	st.fn(ev)
+}
@@ -0,0 +1,314 @@
+package booleancore
+
+import (
+	"context"
+)
+
+// 属性注册表：每个内置属性在这里登记一次（键、显示名、分类、结果类型、代价、依赖、计算与取值函数），
+// Analyzer（见 analyzer.go）按注册表计算，API 的 GET /api/properties 直接返回注册表的元数据，
+// 前端据此渲染，不再在多处硬编码属性名与分类。
+// 新增内置属性时：在 AnalyzeResult 中加字段，在 builtinProperties 中登记，并在 API 响应中映射该字段；
+// 不想改动本包的自定义指标可通过 Analyzer.Register 注册，结果放在 AnalyzeResult.Extensions 中。
+
+// PropertyCategory 是属性的展示分类.
+type PropertyCategory string
+
+const (
+	CategoryBasic         PropertyCategory = "basic"         // 基础性质
+	CategoryAlgebraic     PropertyCategory = "algebraic"     // 代数性质
+	CategorySpectral      PropertyCategory = "spectral"      // 频谱分析
+	CategoryCryptographic PropertyCategory = "cryptographic" // 密码学性质
+)
+
+// CostClass 粗略描述计算一个属性（包括其依赖）的代价量级.
+type CostClass string
+
+const (
+	CostCheap     CostClass = "cheap"     // O(2^n)，直接在真值表上计算
+	CostModerate  CostClass = "moderate"  // O(n·2^n)，需要 WHT/FMT 等变换
+	CostExpensive CostClass = "expensive" // 需要 GF(2) 上的高斯消元，n >= 12 时可能很慢
+)
+
+// 属性结果的 JSON 类型，与前端配置中的 type 取值一致.
+const (
+	ResultNumber  = "number"
+	ResultBoolean = "boolean"
+	ResultText    = "text"
+	ResultArray   = "array"
+	ResultObject  = "object"
+)
+
+// PropertyInfo 是属性的元数据.
+type PropertyInfo struct {
+	Key          string           `json:"key"`                    // 属性键，同时是 API 响应中的字段名
+	Label        string           `json:"label"`                  // 显示名
+	Category     PropertyCategory `json:"category"`               // 展示分类
+	ResultType   string           `json:"type"`                   // 结果的 JSON 类型
+	Cost         CostClass        `json:"cost"`                   // 代价量级
+	Dependencies []string         `json:"dependencies,omitempty"` // 直接依赖的属性键
+}
+
+// CategoryInfo 是分类的元数据.
+type CategoryInfo struct {
+	ID    PropertyCategory `json:"id"`
+	Label string           `json:"label"`
+}
+
+// propertyComputer 计算单个属性并写入 AnalyzeResult 对应字段.
+// 计算失败时返回错误，由 Analyzer 记入 AnalyzeResult.Errors。
+type propertyComputer func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error
+
+type propertyDef struct {
+	PropertyInfo
+	compute propertyComputer
+	value   func(res *AnalyzeResult) interface{} // 从结果中取出该属性的值
+}
+
+// propertyCategories 按展示顺序列出所有分类.
+var propertyCategories = []CategoryInfo{
+	{ID: CategoryBasic, Label: "基础性质"},
+	{ID: CategoryAlgebraic, Label: "代数性质"},
+	{ID: CategorySpectral, Label: "频谱分析"},
+	{ID: CategoryCryptographic, Label: "密码学性质"},
+}
+
+// builtinProperties 按计算顺序登记所有内置属性，依赖项总是排在使用它的属性之前.
+// 频谱类属性先经由可取消的 WalshHadamardTransformCtx/AutocorrelationCtx 写入缓存，
+// 依赖它们的派生属性随后直接命中缓存。
+var builtinProperties = []propertyDef{
+	{
+		PropertyInfo: PropertyInfo{Key: PropHammingWeight, Label: "汉明重量", Category: CategoryBasic, ResultType: ResultNumber, Cost: CostCheap},
+		compute:      simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.HammingWeight = bf.HammingWeight() }),
+		value:        func(res *AnalyzeResult) interface{} { return res.HammingWeight },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsBalanced, Label: "是否平衡", Category: CategoryBasic, ResultType: ResultBoolean, Cost: CostCheap,
+			Dependencies: []string{PropHammingWeight}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsBalanced = bf.IsBalanced() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.IsBalanced },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropANF, Label: "ANF表达式", Category: CategoryAlgebraic, ResultType: ResultText, Cost: CostModerate},
+		compute:      simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.ANF = bf.AlgebraicNormalForm() }),
+		value:        func(res *AnalyzeResult) interface{} { return res.ANF },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAlgebraicDegree, Label: "代数次数", Category: CategoryAlgebraic, ResultType: ResultNumber, Cost: CostModerate},
+		compute:      simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.AlgebraicDegree = bf.AlgebraicDegree() }),
+		value:        func(res *AnalyzeResult) interface{} { return res.AlgebraicDegree },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropWalshSpectrum, Label: "Walsh 频谱", Category: CategorySpectral, ResultType: ResultArray, Cost: CostModerate},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			wht, err := bf.WalshHadamardTransformCtx(ctx)
+			res.WalshSpectrum = wht
+			return err
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.WalshSpectrum },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAutocorrelationSpectrum, Label: "自相关谱", Category: CategorySpectral, ResultType: ResultArray, Cost: CostModerate},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			ac, err := bf.AutocorrelationCtx(ctx)
+			res.AutocorrelationSpectrum = ac
+			return err
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.AutocorrelationSpectrum },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropTransparencyOrder, Label: "透明度阶", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.TransparencyOrder = bf.TransparencyOrder() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.TransparencyOrder },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropNonlinearity, Label: "非线性度", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.Nonlinearity = bf.Nonlinearity() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.Nonlinearity },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropCorrelationImmunity, Label: "相关免疫度", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.CorrelationImmunity = bf.CorrelationImmunity() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.CorrelationImmunity },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropResiliencyOrder, Label: "弹性阶数", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropIsBalanced, PropCorrelationImmunity}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.ResiliencyOrder = bf.ResiliencyOrder() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.ResiliencyOrder },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsBent, Label: "是否Bent函数", Category: CategoryCryptographic, ResultType: ResultBoolean, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsBent = bf.IsBent() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.IsBent },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropSumOfSquareIndicator, Label: "平方和指标", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.SumOfSquareIndicator = bf.SumOfSquareIndicator() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.SumOfSquareIndicator },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsRotationSymmetric, Label: "是否旋转对称", Category: CategoryBasic, ResultType: ResultBoolean, Cost: CostCheap},
+		compute:      simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsRotationSymmetric = bf.IsRotationSymmetric() }),
+		value:        func(res *AnalyzeResult) interface{} { return res.IsRotationSymmetric },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsDihedralSymmetric, Label: "是否二面体对称", Category: CategoryBasic, ResultType: ResultBoolean, Cost: CostCheap},
+		compute:      simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsDihedralSymmetric = bf.IsDihedralSymmetric() }),
+		value:        func(res *AnalyzeResult) interface{} { return res.IsDihedralSymmetric },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAbsoluteWalshSpectrum, Label: "绝对Walsh谱分布", Category: CategorySpectral, ResultType: ResultObject, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.AbsoluteWalshSpectrum = bf.AbsoluteWalshSpectrum() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.AbsoluteWalshSpectrum },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAbsoluteAutocorrelationSpectrum, Label: "绝对自相关谱分布", Category: CategorySpectral, ResultType: ResultObject, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) {
+			res.AbsoluteAutocorrelationSpectrum = bf.AbsoluteAutocorrelation()
+		}),
+		value: func(res *AnalyzeResult) interface{} { return res.AbsoluteAutocorrelationSpectrum },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAbsoluteIndicator, Label: "绝对指标", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.AbsoluteIndicator = bf.AbsoluteIndicator() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.AbsoluteIndicator },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropDifferentialUniformity, Label: "差分均匀度", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropAbsoluteIndicator}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) {
+			res.DifferentialUniformity = bf.DifferentialUniformity()
+		}),
+		value: func(res *AnalyzeResult) interface{} { return res.DifferentialUniformity },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropPropagationCriterion, Label: "扩散准则 PC(l)", Category: CategoryCryptographic, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.PropagationCriterion = bf.PropagationCriterion() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.PropagationCriterion },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsSAC, Label: "是否满足严格雪崩准则", Category: CategoryCryptographic, ResultType: ResultBoolean, Cost: CostModerate,
+			Dependencies: []string{PropPropagationCriterion}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsSAC = res.PropagationCriterion >= 1 }),
+		value:   func(res *AnalyzeResult) interface{} { return res.IsSAC },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAvalancheProfile, Label: "雪崩表（按翻转位数）", Category: CategoryCryptographic, ResultType: ResultArray, Cost: CostModerate,
+			Dependencies: []string{PropAutocorrelationSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.AvalancheProfile = bf.AvalancheProfile() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.AvalancheProfile },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsPlateaued, Label: "是否 plateaued", Category: CategorySpectral, ResultType: ResultBoolean, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsPlateaued = bf.IsPlateaued() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.IsPlateaued },
+	},
+	{
+		// 不是 plateaued 函数时阶数不存在，字段保持零值，由 isPlateaued 区分
+		PropertyInfo: PropertyInfo{Key: PropPlateauedOrder, Label: "Plateaued 阶数", Category: CategorySpectral, ResultType: ResultNumber, Cost: CostModerate,
+			Dependencies: []string{PropIsPlateaued}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) {
+			if s, _, ok := bf.PlateauedOrder(); ok {
+				res.PlateauedOrder = s
+			}
+		}),
+		value: func(res *AnalyzeResult) interface{} {
+			if !res.IsPlateaued {
+				return nil
+			}
+			return res.PlateauedOrder
+		},
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropIsThreeValued, Label: "是否三值谱", Category: CategorySpectral, ResultType: ResultBoolean, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.IsThreeValued = bf.IsThreeValued() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.IsThreeValued },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropWalshSupport, Label: "Walsh 支撑", Category: CategorySpectral, ResultType: ResultArray, Cost: CostModerate,
+			Dependencies: []string{PropWalshSpectrum}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.WalshSupport = bf.WalshSupport() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.WalshSupport },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropSpectralClass, Label: "谱分类", Category: CategorySpectral, ResultType: ResultText, Cost: CostModerate,
+			Dependencies: []string{PropPlateauedOrder}},
+		compute: simpleComputer(func(bf *BooleanFunction, res *AnalyzeResult) { res.SpectralClass = bf.SpectralClassification() }),
+		value:   func(res *AnalyzeResult) interface{} { return res.SpectralClass },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropAlgebraicImmunity, Label: "代数免疫度", Category: CategoryAlgebraic, ResultType: ResultNumber, Cost: CostExpensive},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			ai, _, err := bf.AlgebraicImmunityCtx(ctx, false)
+			if err != nil {
+				return err
+			}
+			res.AlgebraicImmunity = ai
+			return nil
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.AlgebraicImmunity },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropFAA, Label: "快速代数攻击抵抗度（早期定义）", Category: CategoryAlgebraic, ResultType: ResultNumber, Cost: CostExpensive,
+			Dependencies: []string{PropAlgebraicDegree}},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			faa, err := bf.FAACtx(ctx)
+			if err != nil {
+				return err
+			}
+			res.FAA = faa
+			return nil
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.FAA },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropFAAWithPositiveDegree, Label: "快速代数攻击抵抗度（1≤deg g<n/2）", Category: CategoryAlgebraic, ResultType: ResultNumber, Cost: CostExpensive,
+			Dependencies: []string{PropAlgebraicDegree}},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			faaPositive, err := bf.FAAWithPositiveDegreeCtx(ctx)
+			if err != nil {
+				return err
+			}
+			res.FAAWithPositiveDegree = faaPositive
+			return nil
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.FAAWithPositiveDegree },
+	},
+	{
+		PropertyInfo: PropertyInfo{Key: PropFAI, Label: "快速代数免疫度", Category: CategoryAlgebraic, ResultType: ResultNumber, Cost: CostExpensive,
+			Dependencies: []string{PropAlgebraicImmunity, PropAlgebraicDegree}},
+		compute: func(ctx context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+			fai, err := bf.FAICtx(ctx)
+			if err != nil {
+				return err
+			}
+			res.FAI = fai
+			return nil
+		},
+		value: func(res *AnalyzeResult) interface{} { return res.FAI },
+	},
+}
+
+// simpleComputer 包装不涉及耗时循环、也不会失败的属性计算，它们依赖的频谱此时已在缓存中.
+func simpleComputer(fn func(bf *BooleanFunction, res *AnalyzeResult)) propertyComputer {
+	return func(_ context.Context, bf *BooleanFunction, res *AnalyzeResult) error {
+		fn(bf, res)
+		return nil
+	}
+}
+
+// PropertyCategories 返回所有分类（按展示顺序）.
+func PropertyCategories() []CategoryInfo {
+	return append([]CategoryInfo(nil), propertyCategories...)
+}
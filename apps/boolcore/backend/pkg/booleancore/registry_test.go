@@ -0,0 +1,56 @@
+package booleancore
+
+import (
+	"testing"
+)
+
+// TestPropertyRegistry 检查注册表的元数据完整且与预设、计算结果一致
+func TestPropertyRegistry(t *testing.T) {
+	categories := make(map[PropertyCategory]bool)
+	for _, c := range PropertyCategories() {
+		categories[c.ID] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, info := range Properties() {
+		if info.Label == "" || info.ResultType == "" || info.Cost == "" {
+			t.Errorf("属性 %s 的元数据不完整: %+v", info.Key, info)
+		}
+		if !categories[info.Category] {
+			t.Errorf("属性 %s 的分类 %q 未登记", info.Key, info.Category)
+		}
+		for _, dep := range info.Dependencies {
+			if !seen[dep] {
+				t.Errorf("属性 %s 的依赖 %s 应排在它之前", info.Key, dep)
+			}
+		}
+		seen[info.Key] = true
+	}
+
+	for name, keys := range Presets() {
+		for _, key := range keys {
+			if _, ok := LookupProperty(key); !ok {
+				t.Errorf("预设 %s 包含未登记的属性 %s", name, key)
+			}
+		}
+	}
+	for _, key := range Presets()["fast"] {
+		if info, _ := LookupProperty(key); info.Cost == CostExpensive {
+			t.Errorf("fast 预设不应包含昂贵属性 %s", key)
+		}
+	}
+
+	// 修改返回的元数据不影响注册表
+	info, _ := LookupProperty(PropFAI)
+	info.Dependencies[0] = "tampered"
+	if again, _ := LookupProperty(PropFAI); again.Dependencies[0] == "tampered" {
+		t.Error("LookupProperty 返回了注册表内部的切片")
+	}
+
+	res := AnalyzeAll(randomFunction(t, 6, 1))
+	for _, info := range Properties() {
+		if !res.Computed[info.Key] {
+			t.Errorf("AnalyzeAll 未计算属性 %s", info.Key)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package booleancore
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// 具有保证弹性阶的构造，记 (n, m, d, nl) 为 n 元、m 阶弹性、代数次数 d、非线性度 nl 的函数.
+//
+//   - ResilientMaioranaMcFarland：f(x, y) = x·φ(y) ⊕ g(y)，x ∈ F_2^r 占低 r 位、y ∈ F_2^s 占高位；
+//     φ 的像的重量都不小于 m+1 时 f 为 m 阶弹性，φ 为单射时非线性度为 2^{n-1} - 2^{r-1}；
+//   - Tarannikov：由含一对拟线性变量的 (n, m, d, nl) 函数得到 (n+3, m+2, d+1, 2^{n+1} + 4nl) 函数，
+//     结果仍含同一对拟线性变量，可以反复迭代；
+//   - ResilientMM、TarannikovResilient：按目标 (n, m) 选择参数并给出理论值，用于复现文献中的参数表。
+//
+// 变量 x_i、x_j 称为 f 的一对拟线性变量，如果 f(x ⊕ e_i ⊕ e_j) = f(x) ⊕ 1。
+// 此时 f = x_i ⊕ φ(y, x_i ⊕ x_j)（y 为其余变量），W_f(u) 只在 u_i ≠ u_j 处非零。
+
+// ResilientConstruction 是按目标参数构造的弹性函数，Method 说明所用的构造与参数，Predicted 为理论值.
+type ResilientConstruction struct {
+	Function  *BooleanFunction
+	Method    string
+	Predicted []PredictedValue
+}
+
+// Verify 用实测值核对理论值，见 VerifyPredictions.
+func (c *ResilientConstruction) Verify() ([]PropertyCheck, error) {
+	return VerifyPredictions(c.Function, c.Predicted)
+}
+
+// ResilientMaioranaMcFarland 返回 r+s 元函数 f(x, y) = x·φ(y) ⊕ g(y)，其中 2^s = len(phi)、φ(y) = phi[y] ∈ [0, 2^r).
+// g 为 s 元函数，nil 表示 g = 0。
+func ResilientMaioranaMcFarland(r int, phi []int, g *BooleanFunction) (*BooleanFunction, error) {
+	if len(phi) == 0 || len(phi)&(len(phi)-1) != 0 {
+		return nil, fmt.Errorf("phi must have a power of 2 entries, got %d", len(phi))
+	}
+	s := bits.TrailingZeros(uint(len(phi)))
+	if r < 1 {
+		return nil, fmt.Errorf("r must be positive, got %d", r)
+	}
+	if err := checkConstructedN(r + s); err != nil {
+		return nil, err
+	}
+	for y, v := range phi {
+		if v < 0 || v >= 1<<uint(r) {
+			return nil, fmt.Errorf("phi[%d] = %d out of range [0, 2^%d)", y, v, r)
+		}
+	}
+	gt, err := subfunctionTable(g, s, "g")
+	if err != nil {
+		return nil, err
+	}
+	low := 1<<uint(r) - 1
+	return newFromPredicate(r+s, func(z int) byte {
+		x, y := z&low, z>>uint(r)
+		return byte(bits.OnesCount(uint(x&phi[y]))&1) ^ gt[y]
+	}), nil
+}
+
+// PredictResilientMaioranaMcFarland 返回 ResilientMaioranaMcFarland(r, phi, g) 的理论值（与 g 无关），参数须合法.
+// W_f(a, b) = 2^r Σ_{φ(y)=a} (-1)^{g(y) ⊕ b·y}，所以弹性阶不小于 min wt(φ(y)) - 1，
+// max|W_f| ≤ 2^r·max|φ^{-1}(a)|，φ 为单射时两者都取等号；
+// 代数次数不超过 s+1，φ 的像的异或非零时（某个分量 φ_k 重量为奇数）恰为 s+1，否则不超过 s。
+func PredictResilientMaioranaMcFarland(r int, phi []int) []PredictedValue {
+	s := bits.TrailingZeros(uint(len(phi)))
+	n := r + s
+	minWeight, sum := r, 0
+	multiplicity := make(map[int]int64, len(phi))
+	var maxMultiplicity int64
+	for _, v := range phi {
+		minWeight = min(minWeight, bits.OnesCount(uint(v)))
+		sum ^= v
+		multiplicity[v]++
+		maxMultiplicity = max(maxMultiplicity, multiplicity[v])
+	}
+	bound := BoundLower
+	if maxMultiplicity == 1 {
+		bound = BoundExact
+	}
+	degree := PredictedValue{PropAlgebraicDegree, int64(s + 1), BoundExact}
+	if sum == 0 {
+		degree = PredictedValue{PropAlgebraicDegree, int64(s), BoundUpper}
+	}
+	return []PredictedValue{
+		{PropNonlinearity, int64(1)<<uint(n-1) - int64(1)<<uint(r-1)*maxMultiplicity, bound},
+		{PropResiliencyOrder, int64(minWeight - 1), bound},
+		degree,
+	}
+}
+
+// ResilientMM 返回 n 元 m 阶弹性的 Maiorana–McFarland 型函数（0 ≤ m < n），φ 为单射、g = 0.
+// r 取满足 Σ_{w>m} C(r, w) ≥ 2^{n-r} 的最小值，非线性度为 2^{n-1} - 2^{r-1}；
+// φ 按重量从 m+1 开始依次取像，并在可能时使像的异或非零，使代数次数达到 n-r+1。
+func ResilientMM(n, m int) (*ResilientConstruction, error) {
+	if n < 1 || n > MaxConstructedN {
+		return nil, fmt.Errorf("n must be in [1, %d], got %d", MaxConstructedN, n)
+	}
+	if m < 0 || m >= n {
+		return nil, fmt.Errorf("resiliency order must be in [0, %d], got %d", n-1, m)
+	}
+	r, phi := resilientMMParameters(n, m, false)
+	f, err := ResilientMaioranaMcFarland(r, phi, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ResilientConstruction{
+		Function:  f,
+		Method:    fmt.Sprintf("mm(r=%d)", r),
+		Predicted: PredictResilientMaioranaMcFarland(r, phi),
+	}, nil
+}
+
+// IsQuasilinearPair 判断 x_i、x_j 是否为 f 的一对拟线性变量，即 D_{e_i ⊕ e_j} f ≡ 1.
+func (f *BooleanFunction) IsQuasilinearPair(i, j int) bool {
+	if i < 0 || j < 0 || i >= f.n || j >= f.n || i == j {
+		return false
+	}
+	d := f.derivativePacked(1<<uint(i) | 1<<uint(j))
+	ones := make([]uint64, len(d))
+	fillOnes(ones, f.n)
+	for w, v := range d {
+		if v != ones[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// Tarannikov 由以 x_i、x_j 为拟线性变量对的 n 元函数 f = x_i ⊕ φ(y, x_i ⊕ x_j) 构造 n+3 元函数
+// F(x, a, b, c) = x_i ⊕ (1 ⊕ s)(φ(y, a) ⊕ b ⊕ c) ⊕ s(a ⊕ c ⊕ φ(y, b ⊕ c))，其中 s = x_i ⊕ x_j，
+// 新变量 a、b、c 依次为 x_n、x_{n+1}、x_{n+2}。x_i、x_j 仍是 F 的拟线性变量对。
+func Tarannikov(f *BooleanFunction, i, j int) (*BooleanFunction, error) {
+	if err := checkConstructedN(f.n + 3); err != nil {
+		return nil, err
+	}
+	if !f.IsQuasilinearPair(i, j) {
+		return nil, fmt.Errorf("x%d and x%d are not a pair of quasilinear variables", i, j)
+	}
+	tt := f.TruthTable()
+	n, all := f.n, 1<<uint(f.n)-1
+	rest := all &^ (1<<uint(i) | 1<<uint(j))
+	// phi(y, z) = f(x_i = 0, x_j = z)，y 取 x 中除 x_i、x_j 外的位
+	phi := func(x int, z byte) byte { return tt[x&rest|int(z)<<uint(j)] }
+	return newFromPredicate(n+3, func(z int) byte {
+		x := z & all
+		xi, s := byte(x>>uint(i))&1, byte(x>>uint(i)^x>>uint(j))&1
+		a, b, c := byte(z>>uint(n))&1, byte(z>>uint(n+1))&1, byte(z>>uint(n+2))&1
+		if s == 0 {
+			return xi ^ phi(x, a) ^ b ^ c
+		}
+		return xi ^ a ^ c ^ phi(x, b^c)
+	}), nil
+}
+
+// PredictTarannikov 返回 Tarannikov(f, i, j) 的理论值（都是精确值），参数须合法.
+// 两部分的 Walsh 谱支撑不相交且都落在新变量重量不小于 2 的位置，因此 F 为 m+2 阶弹性、
+// 非线性度 2^{n+1} + 4nl；代数次数为 max(d, deg D_{e_j} f + 2)，D_{e_j} f 的次数为 d-1 时即 d+1。
+func PredictTarannikov(f *BooleanFunction, i, j int) []PredictedValue {
+	d := &BooleanFunction{n: f.n, packedTruthTable: f.derivativePacked(1 << uint(j))}
+	return []PredictedValue{
+		{PropNonlinearity, int64(1)<<uint(f.n+1) + 4*f.Nonlinearity(), BoundExact},
+		{PropResiliencyOrder, int64(f.ResiliencyOrder() + 2), BoundExact},
+		{PropAlgebraicDegree, int64(max(f.AlgebraicDegree(), d.AlgebraicDegree()+2)), BoundExact},
+	}
+}
+
+// TarannikovResilient 返回 n 元 m 阶弹性函数（0 ≤ m < n）：以 x_0、x_1 为拟线性变量对的
+// (n-3k, m-2k) Maiorana–McFarland 型函数为起点迭代 k 次 Tarannikov 构造，k = 0 时即 ResilientMM；
+// 在所有可行的 k 中取非线性度理论值最大者。例如 (9, 4)、(12, 6) 可以达到 Sarkar–Maitra 上界
+// (n, m, n-m-1, 2^{n-1} - 2^{m+1})，而单独的 MM 构造只能得到 2^{n-1} - 2^{m+2}。
+func TarannikovResilient(n, m int) (*ResilientConstruction, error) {
+	best, err := ResilientMM(n, m)
+	if err != nil {
+		return nil, err
+	}
+	bestNL := best.Predicted[0].Value
+	bestK := 0
+	var bestR int
+	var bestPhi []int
+	for k := 1; n-3*k >= 2 && m-2*k >= 0; k++ {
+		n0, m0 := n-3*k, m-2*k
+		if m0 >= n0-1 {
+			continue // 拟线性变量对使结果至多 n0-2 阶弹性
+		}
+		r, phi := resilientMMParameters(n0, m0, true)
+		nl := int64(1)<<uint(n0-1) - int64(1)<<uint(r-1)
+		for step := 0; step < k; step++ {
+			nl = int64(1)<<uint(n0+3*step+1) + 4*nl
+		}
+		if nl > bestNL {
+			bestNL, bestK, bestR, bestPhi = nl, k, r, phi
+		}
+	}
+	if bestK == 0 {
+		return best, nil
+	}
+
+	n0 := n - 3*bestK
+	f, err := ResilientMaioranaMcFarland(bestR, bestPhi, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 理论值由起点的理论值按 Tarannikov 的递推得到：
+	// nl' = 2^{n+1} + 4nl，m' = m+2，d' = max(d, e+2)，e' = e+1，其中 e = deg D_{e_1} f
+	predicted := PredictResilientMaioranaMcFarland(bestR, bestPhi)
+	nl, degree := predicted[0].Value, predicted[2]
+	e := int64(newFromPredicate(n0-bestR, func(y int) byte { return byte(bestPhi[y]>>1) & 1 }).AlgebraicDegree())
+	for step := 0; step < bestK; step++ {
+		if f, err = Tarannikov(f, 0, 1); err != nil {
+			return nil, err
+		}
+		nl = int64(1)<<uint(n0+3*step+1) + 4*nl
+		degree.Value = max(degree.Value, e+2)
+		e++
+	}
+	return &ResilientConstruction{
+		Function: f,
+		Method:   fmt.Sprintf("tarannikov(steps=%d) over mm(n=%d, r=%d)", bestK, n0, bestR),
+		Predicted: []PredictedValue{
+			{PropNonlinearity, nl, BoundExact},
+			{PropResiliencyOrder, int64(m), BoundExact},
+			degree,
+		},
+	}, nil
+}
+
+// --- 私有实现 ---
+
+// resilientMMParameters 返回 n 元 m 阶弹性 MM 函数的最小 r 与单射 φ.
+// pair 为 true 时 φ 的像都恰好含第 0、1 位之一，此时 x_0、x_1 是结果的拟线性变量对，要求 m ≤ n-2。
+// r = n 时 φ 只需要一个像，总是可行，因此循环必然结束。
+func resilientMMParameters(n, m int, pair bool) (int, []int) {
+	for r := max(m+1, 1); ; r++ {
+		size := 1 << uint(n-r)
+		candidates := mmCandidates(r, m, pair, size+1)
+		if len(candidates) < size {
+			continue
+		}
+		phi := candidates[:size]
+		sum := 0
+		for _, v := range phi {
+			sum ^= v
+		}
+		if sum == 0 && len(candidates) > size {
+			// 替换最后一个像（不影响最小重量 m+1），使某个分量 φ_k 的重量为奇数
+			phi[size-1] = candidates[size]
+		}
+		return r, phi
+	}
+}
+
+// mmCandidates 按重量从小到大返回 F_2^r 中重量不小于 m+1 的至多 limit 个向量，
+// pair 为 true 时只取第 0、1 位恰有一位为 1 的向量.
+func mmCandidates(r, m int, pair bool, limit int) []int {
+	var out []int
+	add := func(v int) bool {
+		out = append(out, v)
+		return len(out) < limit
+	}
+	if !pair {
+		for w := m + 1; w <= r && len(out) < limit; w++ {
+			forEachCombination(r, w, add)
+		}
+		return out
+	}
+	for w := m; w <= r-2 && len(out) < limit; w++ {
+		forEachCombination(r-2, w, func(v int) bool { return add(v<<2|1) && add(v<<2|2) })
+	}
+	return out
+}
+
+// forEachCombination 按递增顺序对 k 位中重量为 w 的每个向量调用 visit，visit 返回 false 时停止.
+func forEachCombination(k, w int, visit func(v int) bool) {
+	if w < 0 || w > k {
+		return
+	}
+	if w == 0 {
+		visit(0)
+		return
+	}
+	// Gosper's hack：同重量的下一个更大整数
+	for v := 1<<uint(w) - 1; v < 1<<uint(k); {
+		if !visit(v) {
+			return
+		}
+		c := v & -v
+		next := v + c
+		v = ((next^v)>>2)/c | next
+	}
+}
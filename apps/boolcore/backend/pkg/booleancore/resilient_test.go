@@ -0,0 +1,141 @@
+package booleancore
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkConstruction 断言构造结果满足全部理论值
+func checkConstruction(t *testing.T, c *ResilientConstruction) {
+	t.Helper()
+	checks, err := c.Verify()
+	if err != nil {
+		t.Fatalf("%s: Verify error: %v", c.Method, err)
+	}
+	for _, check := range checks {
+		if !check.Holds {
+			t.Errorf("%s (n=%d): %s 理论值 %d（%s）, 实测 %d",
+				c.Method, c.Function.N(), check.Property, check.Value, check.Bound, check.Measured)
+		}
+	}
+}
+
+// TestResilientMM 检查 MM 型弹性函数的弹性阶与理论值
+func TestResilientMM(t *testing.T) {
+	for n := 1; n <= 10; n++ {
+		for m := 0; m < n; m++ {
+			c, err := ResilientMM(n, m)
+			if err != nil {
+				t.Fatalf("ResilientMM(%d, %d) error: %v", n, m, err)
+			}
+			checkConstruction(t, c)
+			if got := c.Function.ResiliencyOrder(); got != m {
+				t.Errorf("ResilientMM(%d, %d): 弹性阶为 %d", n, m, got)
+			}
+		}
+	}
+	if _, err := ResilientMM(4, 4); err == nil {
+		t.Error("m ≥ n 应返回错误")
+	}
+
+	// φ 不是单射时理论值为界
+	rng := rand.New(rand.NewSource(22))
+	for trial := 0; trial < 20; trial++ {
+		phi := make([]int, 8)
+		for y := range phi {
+			phi[y] = 1 + rng.Intn(15) // 非零，f 至少平衡
+		}
+		g := randomFunction(t, 3, rng.Int63())
+		f, err := ResilientMaioranaMcFarland(4, phi, g)
+		if err != nil {
+			t.Fatalf("ResilientMaioranaMcFarland error: %v", err)
+		}
+		checkPredictions(t, "ResilientMaioranaMcFarland", f, PredictResilientMaioranaMcFarland(4, phi))
+	}
+	if _, err := ResilientMaioranaMcFarland(2, []int{1, 4}, nil); err == nil {
+		t.Error("φ 的像超出范围应返回错误")
+	}
+	if _, err := ResilientMaioranaMcFarland(2, []int{1, 2, 3}, nil); err == nil {
+		t.Error("φ 的长度不是 2 的幂应返回错误")
+	}
+}
+
+// TestTarannikov 在随机的含拟线性变量对的函数上核对单步构造的理论值
+func TestTarannikov(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for n := 2; n <= 7; n++ {
+		// f = x_i ⊕ φ(y, x_i ⊕ x_j)，φ 随机
+		i, j := rng.Intn(n), rng.Intn(n-1)
+		if j >= i {
+			j++
+		}
+		phi := randomFunction(t, n, rng.Int63()).TruthTable()
+		f := newFromPredicate(n, func(x int) byte {
+			xi, s := x>>uint(i)&1, (x>>uint(i)^x>>uint(j))&1
+			y := x &^ (1<<uint(i) | 1<<uint(j))
+			return byte(xi) ^ phi[y|s<<uint(j)]
+		})
+		if !f.IsQuasilinearPair(i, j) || !f.IsQuasilinearPair(j, i) {
+			t.Fatalf("n=%d: x%d、x%d 应为拟线性变量对", n, i, j)
+		}
+		F, err := Tarannikov(f, i, j)
+		if err != nil {
+			t.Fatalf("Tarannikov error: %v", err)
+		}
+		checkPredictions(t, "Tarannikov", F, PredictTarannikov(f, i, j))
+		if !F.IsQuasilinearPair(i, j) {
+			t.Errorf("n=%d: 结果应保留拟线性变量对", n)
+		}
+	}
+
+	f, _ := NewFromANF(3, "x0*x1 + x2")
+	if f.IsQuasilinearPair(0, 1) || f.IsQuasilinearPair(0, 0) {
+		t.Error("x0、x1 不是 x0*x1 + x2 的拟线性变量对")
+	}
+	if _, err := Tarannikov(f, 0, 1); err == nil {
+		t.Error("没有拟线性变量对时应返回错误")
+	}
+}
+
+// TestTarannikovResilientTable 复现达到上界 2^{n-1} - 2^{m+1} 且次数为 n-m-1 的参数
+func TestTarannikovResilientTable(t *testing.T) {
+	table := []struct {
+		n, m, degree int
+		nl           int64
+	}{
+		{4, 1, 2, 4},
+		{5, 2, 2, 8},
+		{6, 2, 3, 24},
+		{7, 3, 3, 48},
+		{9, 4, 4, 224},
+		{10, 5, 4, 448},
+		{12, 6, 5, 1920},
+	}
+	for _, tc := range table {
+		c, err := TarannikovResilient(tc.n, tc.m)
+		if err != nil {
+			t.Fatalf("TarannikovResilient(%d, %d) error: %v", tc.n, tc.m, err)
+		}
+		checkConstruction(t, c)
+		f := c.Function
+		if f.N() != tc.n || f.ResiliencyOrder() != tc.m || f.AlgebraicDegree() != tc.degree || f.Nonlinearity() != tc.nl {
+			t.Errorf("(%d, %d): 期望 (%d, %d, %d, %d), 实际 (%d, %d, %d, %d) [%s]", tc.n, tc.m,
+				tc.n, tc.m, tc.degree, tc.nl, f.N(), f.ResiliencyOrder(), f.AlgebraicDegree(), f.Nonlinearity(), c.Method)
+		}
+	}
+
+	// 其余参数下理论值同样成立，且非线性度不低于 MM 构造
+	for n := 2; n <= 11; n++ {
+		for m := 0; m < n; m++ {
+			c, err := TarannikovResilient(n, m)
+			if err != nil {
+				t.Fatalf("TarannikovResilient(%d, %d) error: %v", n, m, err)
+			}
+			checkConstruction(t, c)
+			mm, _ := ResilientMM(n, m)
+			if c.Function.Nonlinearity() < mm.Function.Nonlinearity() {
+				t.Errorf("(%d, %d): 非线性度 %d 低于 MM 构造的 %d", n, m, c.Function.Nonlinearity(), mm.Function.Nonlinearity())
+			}
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// 旋转对称布尔函数（RSBF）：f(ρ(x)) = f(x)，ρ 为循环左移一位（x0 为最低位，与 IsRotationSymmetric 一致）。
+// F_2^n 在 ρ 下分成 g_n = (1/n)·Σ_{d|n} φ(d)·2^{n/d} 个轨道 Λ_0, …, Λ_{g-1}，
+// 以轨道中的最小元素 λ_i 为代表元；RSBF 由它在各轨道上的取值决定，共 2^{g_n} 个。
+//
+// RSBF 的 Walsh 谱与自相关谱同样在每个轨道上取常值，可以只在代表元上计算（RSBF 矩阵，Stănică–Maitra）：
+//
+//	A[i][j] = Σ_{x∈Λ_i} (-1)^{x·λ_j}
+//	W_f(λ_j) = Σ_i (-1)^{f(λ_i)}·A[i][j]
+//	C_f(λ_j) = 2^{-n}·Σ_i W_f(λ_i)²·A[i][j]
+//
+// 矩阵只与 n 有关，计算一次后每个 RSBF 的谱是 O(g_n²)，
+// 翻转一个轨道上的取值只需 O(g_n) 更新 Walsh 谱，这是 RSBF 枚举与搜索（SearchOptions.RotationSymmetric）的基础。
+
+// MaxRSBFMatrixN 是 NewRSBFMatrix 支持的最大变量个数（g_16 = 4116，矩阵约 17MB）.
+const MaxRSBFMatrixN = 16
+
+// MaxRSBFEnumerationN 是 Enumerate 支持的最大变量个数：g_7 = 20 即 2^20 个 RSBF，而 g_8 = 36.
+const MaxRSBFEnumerationN = 7
+
+// RotationOrbits 返回 F_2^n 在循环移位下的全部轨道，按代表元（轨道中的最小元素）升序排列；
+// 每个轨道从代表元开始依次列出 λ, ρ(λ), ρ²(λ), …，长度即轨道大小.
+func RotationOrbits(n int) ([][]int, error) {
+	if err := checkRotationN(n); err != nil {
+		return nil, err
+	}
+	_, reps := rotationOrbitIndex(n)
+	orbits := make([][]int, len(reps))
+	for i, rep := range reps {
+		orbits[i] = rotationOrbit(rep, n)
+	}
+	return orbits, nil
+}
+
+// NewFromRotationRepresentatives 构造在 reps 所在的轨道上取 1、其余轨道上取 0 的 RSBF.
+// reps 中的元素可以是轨道中的任意元素，不必是最小元素.
+func NewFromRotationRepresentatives(n int, reps []int) (*BooleanFunction, error) {
+	if err := checkRotationN(n); err != nil {
+		return nil, err
+	}
+	packed := make([]uint64, packedWords(n))
+	for _, rep := range reps {
+		if rep < 0 || rep >= 1<<uint(n) {
+			return nil, fmt.Errorf("representative %d out of range [0, %d)", rep, 1<<uint(n))
+		}
+		for _, x := range rotationOrbit(rep, n) {
+			packed[x>>6] |= 1 << uint(x&63)
+		}
+	}
+	return &BooleanFunction{n: n, packedTruthTable: packed}, nil
+}
+
+// RotationRepresentatives 返回 RSBF 的支撑中各轨道的代表元（升序），是 NewFromRotationRepresentatives 的逆.
+func (f *BooleanFunction) RotationRepresentatives() ([]int, error) {
+	if !f.IsRotationSymmetric() {
+		return nil, errors.New("function is not rotation symmetric")
+	}
+	tt := f.TruthTable()
+	var reps []int
+	for x, v := range tt {
+		if v == 1 && isRotationMin(x, f.n) {
+			reps = append(reps, x)
+		}
+	}
+	return reps, nil
+}
+
+// IsKRotationSymmetric 判断 f 是否在循环左移 k 位下不变（k-RSBF），k ∈ [1, n].
+// k = 1 即 IsRotationSymmetric；ρ^k 不变等价于 ρ^{gcd(k, n)} 不变，k = n 时恒为 true.
+func (f *BooleanFunction) IsKRotationSymmetric(k int) (bool, error) {
+	if k < 1 || k > f.n {
+		return false, fmt.Errorf("k must be in [1, %d], got %d", f.n, k)
+	}
+	tt := f.TruthTable()
+	for x := range tt {
+		if tt[x] != tt[rotateLeft(x, f.n, k)] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IsDihedralSymmetric 判断 f 是否二面体对称：在循环移位与逆序 (x0, …, x_{n-1}) ↦ (x_{n-1}, …, x0) 下都不变.
+func (f *BooleanFunction) IsDihedralSymmetric() bool {
+	if !f.IsRotationSymmetric() {
+		return false
+	}
+	tt := f.TruthTable()
+	for x := range tt {
+		if tt[x] != tt[reverseBits(x, f.n)] {
+			return false
+		}
+	}
+	return true
+}
+
+// RSBFMatrix 是 n 元 RSBF 的轨道级 Walsh 变换矩阵，创建后只读，可被多个 goroutine 共享.
+// RSBF 用轨道取值向量 values 表示：values[i] 是 f 在第 i 个轨道上的取值.
+type RSBFMatrix struct {
+	n       int
+	reps    []int   // 代表元，升序
+	sizes   []int   // 轨道大小
+	orbitOf []int32 // x → 所在轨道的下标
+	entries []int8  // g×g 行优先，entries[i*g+j] = A[i][j]，|A[i][j]| ≤ |Λ_i| ≤ n
+}
+
+// NewRSBFMatrix 计算 n 元 RSBF 矩阵，耗时 O(2^n·g_n).
+func NewRSBFMatrix(n int) (*RSBFMatrix, error) {
+	if n < 1 || n > MaxRSBFMatrixN {
+		return nil, fmt.Errorf("n must be in [1, %d], got %d", MaxRSBFMatrixN, n)
+	}
+	orbitOf, reps := rotationOrbitIndex(n)
+	g := len(reps)
+	m := &RSBFMatrix{n: n, reps: reps, sizes: make([]int, g), orbitOf: orbitOf, entries: make([]int8, g*g)}
+	for x, i := range orbitOf {
+		m.sizes[i]++
+		row := m.entries[int(i)*g : int(i+1)*g]
+		for j, rep := range reps {
+			row[j] += int8(parity(x, rep))
+		}
+	}
+	return m, nil
+}
+
+// N 返回变量个数.
+func (m *RSBFMatrix) N() int { return m.n }
+
+// Orbits 返回轨道个数 g_n.
+func (m *RSBFMatrix) Orbits() int { return len(m.reps) }
+
+// Representatives 返回各轨道的代表元.
+func (m *RSBFMatrix) Representatives() []int {
+	return append([]int(nil), m.reps...)
+}
+
+// OrbitSizes 返回各轨道的大小.
+func (m *RSBFMatrix) OrbitSizes() []int {
+	return append([]int(nil), m.sizes...)
+}
+
+// OrbitOf 返回 x 所在轨道的下标，x 须在 [0, 2^n) 内.
+func (m *RSBFMatrix) OrbitOf(x int) int {
+	return int(m.orbitOf[x])
+}
+
+// At 返回矩阵元素 A[i][j] = Σ_{x∈Λ_i} (-1)^{x·λ_j}.
+func (m *RSBFMatrix) At(i, j int) int64 {
+	return int64(m.entries[i*len(m.reps)+j])
+}
+
+// Values 返回 RSBF f 的轨道取值向量.
+func (m *RSBFMatrix) Values(f *BooleanFunction) ([]byte, error) {
+	if f.n != m.n {
+		return nil, fmt.Errorf("function must have %d variables, got %d", m.n, f.n)
+	}
+	if !f.IsRotationSymmetric() {
+		return nil, errors.New("function is not rotation symmetric")
+	}
+	values := make([]byte, len(m.reps))
+	for i, rep := range m.reps {
+		values[i] = byte(f.packedTruthTable[rep>>6] >> uint(rep&63) & 1)
+	}
+	return values, nil
+}
+
+// Function 由轨道取值向量构造 RSBF.
+func (m *RSBFMatrix) Function(values []byte) (*BooleanFunction, error) {
+	if err := m.checkValues(values); err != nil {
+		return nil, err
+	}
+	return &BooleanFunction{n: m.n, packedTruthTable: m.expand(values)}, nil
+}
+
+// Walsh 返回轨道级 Walsh 谱：第 j 项是 W_f 在第 j 个轨道上的值 W_f(λ_j).
+func (m *RSBFMatrix) Walsh(values []byte) ([]int64, error) {
+	if err := m.checkValues(values); err != nil {
+		return nil, err
+	}
+	g := len(m.reps)
+	walsh := make([]int64, g)
+	for i, v := range values {
+		sign := 1 - 2*int64(v)
+		row := m.entries[i*g : (i+1)*g]
+		for j, a := range row {
+			walsh[j] += sign * int64(a)
+		}
+	}
+	return walsh, nil
+}
+
+// Autocorrelation 返回轨道级自相关谱：第 j 项是 C_f(λ_j).
+func (m *RSBFMatrix) Autocorrelation(values []byte) ([]int64, error) {
+	walsh, err := m.Walsh(values)
+	if err != nil {
+		return nil, err
+	}
+	return m.autocorrelationFromWalsh(walsh), nil
+}
+
+// Nonlinearity 由轨道级 Walsh 谱计算非线性度.
+func (m *RSBFMatrix) Nonlinearity(walsh []int64) int64 {
+	return (1 << (m.n - 1)) - maxAbsValue(walsh, 0)/2
+}
+
+// Enumerate 按 Gray 码顺序枚举全部 2^{g_n} 个 RSBF（n ≤ MaxRSBFEnumerationN），
+// 每步只翻转一个轨道，Walsh 谱 O(g_n) 增量更新。visit 收到的切片只在回调期间有效，返回 false 时停止枚举.
+func (m *RSBFMatrix) Enumerate(ctx context.Context, visit func(values []byte, walsh []int64) bool) error {
+	if m.n > MaxRSBFEnumerationN {
+		return fmt.Errorf("enumeration supports n <= %d, got %d", MaxRSBFEnumerationN, m.n)
+	}
+	g := len(m.reps)
+	values := make([]byte, g)
+	walsh, _ := m.Walsh(values)
+	if !visit(values, walsh) {
+		return nil
+	}
+	for k := uint64(1); k < 1<<uint(g); k++ {
+		if k&0xfff == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		m.flipOrbit(values, walsh, bits.TrailingZeros64(k))
+		if !visit(values, walsh) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// --- 私有实现 ---
+
+// checkRotationN 检查轨道枚举与 RSBF 构造的变量个数.
+func checkRotationN(n int) error {
+	if n < 1 || n > MaxConstructedN {
+		return fmt.Errorf("n must be in [1, %d], got %d", MaxConstructedN, n)
+	}
+	return nil
+}
+
+// rotateLeft 返回 x 的 n 位循环左移 k 位，0 ≤ k ≤ n.
+func rotateLeft(x, n, k int) int {
+	mask := 1<<uint(n) - 1
+	return (x<<uint(k) | x>>uint(n-k)) & mask
+}
+
+// reverseBits 返回 x 的低 n 位逆序.
+func reverseBits(x, n int) int {
+	r := 0
+	for i := 0; i < n; i++ {
+		r = r<<1 | x>>uint(i)&1
+	}
+	return r
+}
+
+// rotationOrbit 返回 x 的轨道 x, ρ(x), ρ²(x), …
+func rotationOrbit(x, n int) []int {
+	orbit := []int{x}
+	for y := rotateLeft(x, n, 1); y != x; y = rotateLeft(y, n, 1) {
+		orbit = append(orbit, y)
+	}
+	return orbit
+}
+
+// isRotationMin 判断 x 是否为所在轨道的最小元素.
+func isRotationMin(x, n int) bool {
+	for y := rotateLeft(x, n, 1); y != x; y = rotateLeft(y, n, 1) {
+		if y < x {
+			return false
+		}
+	}
+	return true
+}
+
+// rotationOrbitIndex 返回每个输入所在轨道的下标与各轨道的代表元（升序）.
+// 按 x 升序扫描，遇到尚未归类的 x 时它就是新轨道的最小元素.
+func rotationOrbitIndex(n int) (orbitOf []int32, reps []int) {
+	orbitOf = make([]int32, 1<<uint(n))
+	for x := range orbitOf {
+		orbitOf[x] = -1
+	}
+	for x := range orbitOf {
+		if orbitOf[x] >= 0 {
+			continue
+		}
+		idx := int32(len(reps))
+		reps = append(reps, x)
+		for _, y := range rotationOrbit(x, n) {
+			orbitOf[y] = idx
+		}
+	}
+	return orbitOf, reps
+}
+
+func (m *RSBFMatrix) checkValues(values []byte) error {
+	if len(values) != len(m.reps) {
+		return fmt.Errorf("values must have %d entries (one per orbit), got %d", len(m.reps), len(values))
+	}
+	for _, v := range values {
+		if v > 1 {
+			return errors.New("values must be 0 or 1")
+		}
+	}
+	return nil
+}
+
+// expand 把轨道取值向量展开为打包真值表.
+func (m *RSBFMatrix) expand(values []byte) []uint64 {
+	packed := make([]uint64, packedWords(m.n))
+	for x, i := range m.orbitOf {
+		packed[x>>6] |= uint64(values[i]) << uint(x&63)
+	}
+	return packed
+}
+
+// flipOrbit 翻转第 i 个轨道上的取值并更新轨道级 Walsh 谱：W(λ_j) −= 2σ_i·A[i][j].
+func (m *RSBFMatrix) flipOrbit(values []byte, walsh []int64, i int) {
+	g := len(m.reps)
+	d := 2 * (1 - 2*int64(values[i]))
+	for j, a := range m.entries[i*g : (i+1)*g] {
+		walsh[j] -= d * int64(a)
+	}
+	values[i] ^= 1
+}
+
+// autocorrelationFromWalsh 由轨道级 Walsh 谱计算轨道级自相关谱，耗时 O(g_n²).
+func (m *RSBFMatrix) autocorrelationFromWalsh(walsh []int64) []int64 {
+	g := len(m.reps)
+	ac := make([]int64, g)
+	for i, w := range walsh {
+		sq := w * w
+		for j, a := range m.entries[i*g : (i+1)*g] {
+			ac[j] += sq * int64(a)
+		}
+	}
+	for j := range ac {
+		ac[j] >>= uint(m.n)
+	}
+	return ac
+}
@@ -0,0 +1,299 @@
+package booleancore
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+)
+
+// RSBF 搜索（SearchOptions.RotationSymmetric）：状态是轨道取值向量，空间从 2^{2^n} 缩小到 2^{g_n}。
+// 邻域操作是翻转一个轨道；限定平衡时改为交换大小相同、取值不同的两个轨道，
+// 每个大小分组中取 1 的轨道数保持不变，因此平衡的随机起点先在分组上做子集和 DP，使总重量恰为 2^{n-1}。
+// 翻转轨道后 Walsh 谱 O(g_n) 增量更新；需要自相关谱（AbsoluteIndicator 或 TransparencyOrder 非零）时为 O(g_n²)。
+// 遗传算法在轨道取值向量上单点交叉，限定平衡时按第一个父代修复每个分组取 1 的轨道数。
+
+// --- 私有实现 ---
+
+// rsbfSearch 是 RSBF 搜索在 searcher 之外的状态.
+type rsbfSearch struct {
+	m       *RSBFMatrix
+	classes [][]int // 按轨道大小分组的轨道下标
+	classOf []int   // 轨道 → 所在分组
+	// balanced 为 true 时只生成平衡的随机起点，reach[k][w] 表示只用前 k 个分组能否凑出总大小 w（w ≤ 2^{n-1}）
+	balanced bool
+	reach    [][]bool
+	best     []byte // 目前最优函数的轨道取值向量
+}
+
+// newRSBFSearch 计算 RSBF 矩阵与轨道分组；限定平衡时另做子集和 DP 并检查平衡 RSBF 是否存在.
+func newRSBFSearch(n int, balanced bool) (*rsbfSearch, error) {
+	m, err := NewRSBFMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	r := &rsbfSearch{m: m, classOf: make([]int, m.Orbits()), balanced: balanced}
+	classIndex := make(map[int]int)
+	for i, size := range m.sizes {
+		k, ok := classIndex[size]
+		if !ok {
+			k = len(r.classes)
+			classIndex[size] = k
+			r.classes = append(r.classes, nil)
+		}
+		r.classes[k] = append(r.classes[k], i)
+		r.classOf[i] = k
+	}
+	if !balanced {
+		return r, nil
+	}
+
+	half := 1 << uint(n-1)
+	r.reach = make([][]bool, len(r.classes)+1)
+	r.reach[0] = make([]bool, half+1)
+	r.reach[0][0] = true
+	for k, class := range r.classes {
+		size := m.sizes[class[0]]
+		prev, cur := r.reach[k], make([]bool, half+1)
+		for w := range cur {
+			for c := 0; c <= len(class) && c*size <= w; c++ {
+				if prev[w-c*size] {
+					cur[w] = true
+					break
+				}
+			}
+		}
+		r.reach[k+1] = cur
+	}
+	if !r.reach[len(r.classes)][half] {
+		return nil, fmt.Errorf("no balanced rotation symmetric function with %d variables", n)
+	}
+	return r, nil
+}
+
+// randomValues 返回随机 RSBF 的轨道取值向量；限定平衡时从后往前在各分组上随机回溯取 1 的轨道数.
+func (r *rsbfSearch) randomValues(rng *rand.Rand) []byte {
+	values := make([]byte, r.m.Orbits())
+	if !r.balanced {
+		for i := range values {
+			values[i] = byte(rng.Intn(2))
+		}
+		return values
+	}
+	w := 1 << uint(r.m.n-1)
+	for k := len(r.classes) - 1; k >= 0; k-- {
+		class := r.classes[k]
+		size := r.m.sizes[class[0]]
+		var counts []int
+		for c := 0; c <= len(class) && c*size <= w; c++ {
+			if r.reach[k][w-c*size] {
+				counts = append(counts, c)
+			}
+		}
+		c := counts[rng.Intn(len(counts))]
+		for _, p := range rng.Perm(len(class))[:c] {
+			values[class[p]] = 1
+		}
+		w -= c * size
+	}
+	return values
+}
+
+// orbitCost 评估轨道取值向量的代价，walsh 是对应的轨道级 Walsh 谱；CostFunc 收到展开后的函数.
+func (s *searcher) orbitCost(values []byte, walsh []int64) float64 {
+	if s.opts.CostFunc != nil {
+		return s.cost(s.rsbf.m.expand(values))
+	}
+	s.evaluations++
+	return s.opts.Cost.evaluateOrbits(s.rsbf.m, walsh)
+}
+
+// evaluateOrbits 由轨道级谱计算代价，与对展开后的函数调用 Evaluate 相同（谱代价一项只有浮点求和顺序不同）.
+func (c SearchCost) evaluateOrbits(m *RSBFMatrix, walsh []int64) float64 {
+	size := 1 << uint(m.n)
+	metrics := costMetrics{
+		n:            m.n,
+		walsh:        walsh,
+		multiplicity: m.sizes,
+		nonlinearity: m.Nonlinearity(walsh),
+		weight:       (size - int(walsh[0])) / 2, // 第 0 个轨道是 {0}
+	}
+	if c.AbsoluteIndicator != 0 || c.TransparencyOrder != 0 {
+		ac := m.autocorrelationFromWalsh(walsh)
+		var sum int64
+		for j := 1; j < len(ac); j++ {
+			v := abs64(ac[j])
+			metrics.absoluteIndicator = max64(metrics.absoluteIndicator, v)
+			sum += int64(m.sizes[j]) * v
+		}
+		metrics.transparencyOrder = 1.0 - float64(sum)/float64(size*(size-1))
+	}
+	return c.combine(metrics)
+}
+
+// initialValues 返回 opts.Initial 的轨道取值向量，未指定时返回随机 RSBF.
+func (s *searcher) initialValues() []byte {
+	if s.opts.Initial == nil {
+		return s.rsbf.randomValues(s.rng)
+	}
+	values, _ := s.rsbf.m.Values(s.opts.Initial) // withDefaults 已检查旋转对称
+	return values
+}
+
+// pickOrbits 随机选择一次轨道级邻域操作：不限定平衡时翻转轨道 i（j = -1），
+// 限定平衡时交换大小相同、取值不同的轨道 i 与 j；有限次尝试仍找不到时 ok 为 false.
+func (s *searcher) pickOrbits(values []byte) (i, j int, ok bool) {
+	g := len(values)
+	if !s.opts.Balanced {
+		return s.rng.Intn(g), -1, true
+	}
+	for probe := 0; probe < 4*g; probe++ {
+		i = s.rng.Intn(g)
+		class := s.rsbf.classes[s.rsbf.classOf[i]]
+		j = class[s.rng.Intn(len(class))]
+		if values[i] != values[j] {
+			return i, j, true
+		}
+	}
+	return 0, 0, false
+}
+
+// moveOrbits 对轨道取值向量做一次随机的邻域操作，不维护 Walsh 谱.
+func (s *searcher) moveOrbits(values []byte) {
+	if i, j, ok := s.pickOrbits(values); ok {
+		values[i] ^= 1
+		if j >= 0 {
+			values[j] ^= 1
+		}
+	}
+}
+
+// recordValues 在 cost 更小时把轨道取值向量记为目前最优.
+func (s *searcher) recordValues(values []byte, cost float64) {
+	if s.best == nil || cost < s.bestCost {
+		s.best = s.rsbf.m.expand(values)
+		s.bestCost = cost
+		s.rsbf.best = append(s.rsbf.best[:0], values...)
+	}
+}
+
+// rotationLocalSearch 是 localSearch 的轨道级版本；找不到邻域操作的一步只计数.
+func (s *searcher) rotationLocalSearch(ctx context.Context) error {
+	m := s.rsbf.m
+	values := s.initialValues()
+	walsh, err := m.Walsh(values)
+	if err != nil {
+		return err
+	}
+	curCost := s.orbitCost(values, walsh)
+	s.recordValues(values, curCost)
+	temperature := s.opts.Temperature
+
+	for s.iterations < s.opts.Iterations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i, j, ok := s.pickOrbits(values); ok {
+			m.flipOrbit(values, walsh, i)
+			if j >= 0 {
+				m.flipOrbit(values, walsh, j)
+			}
+			cost := s.orbitCost(values, walsh)
+			if s.accept(cost-curCost, temperature) {
+				curCost = cost
+				s.recordValues(values, cost)
+			} else {
+				if j >= 0 {
+					m.flipOrbit(values, walsh, j)
+				}
+				m.flipOrbit(values, walsh, i)
+			}
+		}
+		temperature *= s.opts.CoolingRate
+		s.iterations++
+		s.report(ctx, false)
+	}
+	s.report(ctx, true)
+	return nil
+}
+
+// orbitIndividual 是 RSBF 遗传算法种群中的一个函数.
+type orbitIndividual struct {
+	values []byte
+	cost   float64
+}
+
+// rotationGenetic 是 genetic 的轨道级版本.
+func (s *searcher) rotationGenetic(ctx context.Context) error {
+	m := s.rsbf.m
+	evaluate := func(values []byte) orbitIndividual {
+		walsh, _ := m.Walsh(values)
+		ind := orbitIndividual{values: values, cost: s.orbitCost(values, walsh)}
+		s.recordValues(values, ind.cost)
+		return ind
+	}
+	pop := make([]orbitIndividual, s.opts.Population)
+	for i := range pop {
+		if i == 0 {
+			pop[i] = evaluate(s.initialValues())
+		} else {
+			pop[i] = evaluate(s.rsbf.randomValues(s.rng))
+		}
+	}
+
+	next := make([]orbitIndividual, len(pop))
+	for s.iterations < s.opts.Iterations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next[0] = orbitIndividual{values: append([]byte(nil), s.rsbf.best...), cost: s.bestCost}
+		for i := 1; i < len(next); i++ {
+			a, b := s.orbitTournament(pop).values, s.orbitTournament(pop).values
+			cut := 1 + s.rng.Intn(len(a)-1)
+			child := append(append([]byte(nil), a[:cut]...), b[cut:]...)
+			if s.opts.Balanced {
+				s.repairClasses(child, a)
+			}
+			for k := 0; k < s.opts.Mutations; k++ {
+				s.moveOrbits(child)
+			}
+			next[i] = evaluate(child)
+		}
+		pop, next = next, pop
+		s.iterations++
+		s.report(ctx, false)
+	}
+	s.report(ctx, true)
+	return nil
+}
+
+// orbitTournament 随机抽取 3 个个体，返回其中代价最小者.
+func (s *searcher) orbitTournament(pop []orbitIndividual) orbitIndividual {
+	best := pop[s.rng.Intn(len(pop))]
+	for k := 1; k < 3; k++ {
+		if c := pop[s.rng.Intn(len(pop))]; c.cost < best.cost {
+			best = c
+		}
+	}
+	return best
+}
+
+// repairClasses 随机翻转 child 的轨道，使每个大小分组中取 1 的轨道数与平衡的 parent 相同，child 因此也平衡.
+func (s *searcher) repairClasses(child, parent []byte) {
+	for _, class := range s.rsbf.classes {
+		want, have := 0, 0
+		for _, i := range class {
+			want += int(parent[i])
+			have += int(child[i])
+		}
+		for have != want {
+			i := class[s.rng.Intn(len(class))]
+			if have > want && child[i] == 1 {
+				child[i] = 0
+				have--
+			} else if have < want && child[i] == 0 {
+				child[i] = 1
+				have++
+			}
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package booleancore
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// orbitCount 按 g_n = (1/n)·Σ_{d|n} φ(d)·2^{n/d} 计算轨道个数
+func orbitCount(n int) int {
+	sum := 0
+	for d := 1; d <= n; d++ {
+		if n%d != 0 {
+			continue
+		}
+		phi := 0
+		for k := 1; k <= d; k++ {
+			a, b := k, d
+			for b != 0 {
+				a, b = b, a%b
+			}
+			if a == 1 {
+				phi++
+			}
+		}
+		sum += phi << uint(n/d)
+	}
+	return sum / n
+}
+
+// randomRSBF 返回随机选取轨道得到的 RSBF
+func randomRSBF(t *testing.T, n int, seed int64) *BooleanFunction {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	var reps []int
+	for x := 0; x < 1<<uint(n); x++ {
+		if isRotationMin(x, n) && rng.Intn(2) == 1 {
+			reps = append(reps, x)
+		}
+	}
+	f, err := NewFromRotationRepresentatives(n, reps)
+	if err != nil {
+		t.Fatalf("NewFromRotationRepresentatives error: %v", err)
+	}
+	return f
+}
+
+// TestRotationOrbits 检查轨道个数、划分与代表元
+func TestRotationOrbits(t *testing.T) {
+	for n := 1; n <= 12; n++ {
+		orbits, err := RotationOrbits(n)
+		if err != nil {
+			t.Fatalf("RotationOrbits(%d) error: %v", n, err)
+		}
+		if len(orbits) != orbitCount(n) {
+			t.Errorf("n=%d: 轨道个数为 %d, 期望 %d", n, len(orbits), orbitCount(n))
+		}
+		seen := make([]bool, 1<<uint(n))
+		for i, orbit := range orbits {
+			if i > 0 && orbit[0] <= orbits[i-1][0] {
+				t.Fatalf("n=%d: 轨道应按代表元升序排列", n)
+			}
+			if n%len(orbit) != 0 {
+				t.Errorf("n=%d: 轨道大小 %d 应整除 n", n, len(orbit))
+			}
+			for k, x := range orbit {
+				if seen[x] || x < orbit[0] || rotateLeft(x, n, 1) != orbit[(k+1)%len(orbit)] {
+					t.Fatalf("n=%d: 轨道 %v 不正确", n, orbit)
+				}
+				seen[x] = true
+			}
+		}
+		for x, ok := range seen {
+			if !ok {
+				t.Fatalf("n=%d: %d 不在任何轨道中", n, x)
+			}
+		}
+	}
+	if _, err := RotationOrbits(0); err == nil {
+		t.Error("n=0 应返回错误")
+	}
+}
+
+// TestRotationRepresentatives 检查由代表元构造与反向提取
+func TestRotationRepresentatives(t *testing.T) {
+	for n := 1; n <= 10; n++ {
+		f := randomRSBF(t, n, int64(n))
+		if !f.IsRotationSymmetric() {
+			t.Fatalf("n=%d: 构造的函数应旋转对称", n)
+		}
+		reps, err := f.RotationRepresentatives()
+		if err != nil {
+			t.Fatalf("RotationRepresentatives error: %v", err)
+		}
+		g, _ := NewFromRotationRepresentatives(n, reps)
+		if !g.Equal(f) {
+			t.Errorf("n=%d: 代表元往返后函数不一致", n)
+		}
+	}
+
+	// 轨道中的任意元素都代表整个轨道
+	f, _ := NewFromRotationRepresentatives(4, []int{0b0110})
+	if want, _ := NewFromRotationRepresentatives(4, []int{0b0011}); !f.Equal(want) {
+		t.Error("非最小元素应代表同一轨道")
+	}
+	if _, err := NewFromRotationRepresentatives(4, []int{16}); err == nil {
+		t.Error("代表元越界应返回错误")
+	}
+	if _, err := randomFunction(t, 6, 1).RotationRepresentatives(); err == nil {
+		t.Error("非旋转对称函数应返回错误")
+	}
+}
+
+// TestRSBFMatrix 检查轨道级 Walsh 谱与自相关谱与完整计算一致
+func TestRSBFMatrix(t *testing.T) {
+	for n := 1; n <= 11; n++ {
+		m, err := NewRSBFMatrix(n)
+		if err != nil {
+			t.Fatalf("NewRSBFMatrix(%d) error: %v", n, err)
+		}
+		if m.Orbits() != orbitCount(n) {
+			t.Fatalf("n=%d: 轨道个数为 %d", n, m.Orbits())
+		}
+		for seed := int64(0); seed < 3; seed++ {
+			f := randomRSBF(t, n, seed)
+			values, err := m.Values(f)
+			if err != nil {
+				t.Fatalf("Values error: %v", err)
+			}
+			walsh, _ := m.Walsh(values)
+			ac, _ := m.Autocorrelation(values)
+			fullWalsh, fullAC := f.WalshHadamardTransform(), f.Autocorrelation()
+			for j, rep := range m.Representatives() {
+				if walsh[j] != fullWalsh[rep] || ac[j] != fullAC[rep] {
+					t.Fatalf("n=%d: 轨道 %d 的谱不一致: W %d/%d, C %d/%d", n, j, walsh[j], fullWalsh[rep], ac[j], fullAC[rep])
+				}
+			}
+			if m.Nonlinearity(walsh) != f.Nonlinearity() {
+				t.Errorf("n=%d: 非线性度 %d, 期望 %d", n, m.Nonlinearity(walsh), f.Nonlinearity())
+			}
+			if g, _ := m.Function(values); !g.Equal(f) {
+				t.Errorf("n=%d: 轨道取值向量往返后函数不一致", n)
+			}
+		}
+		for x := 0; x < 1<<uint(n); x++ {
+			if rep := m.Representatives()[m.OrbitOf(x)]; !isRotationMin(rep, n) || rep > x {
+				t.Fatalf("n=%d: OrbitOf(%d) 不正确", n, x)
+			}
+		}
+	}
+
+	m, _ := NewRSBFMatrix(4)
+	if _, err := m.Values(randomFunction(t, 5, 1)); err == nil {
+		t.Error("变量个数不一致应返回错误")
+	}
+	if _, err := m.Walsh([]byte{0, 1}); err == nil {
+		t.Error("取值向量长度错误应返回错误")
+	}
+	if _, err := m.Function(make([]byte, m.Orbits()-1)); err == nil {
+		t.Error("取值向量长度错误应返回错误")
+	}
+	if _, err := NewRSBFMatrix(MaxRSBFMatrixN + 1); err == nil {
+		t.Error("n 超过上限应返回错误")
+	}
+}
+
+// TestRSBFEnumerate 检查枚举的个数、增量谱与已知的最大非线性度
+func TestRSBFEnumerate(t *testing.T) {
+	ctx := context.Background()
+	for n := 1; n <= 5; n++ {
+		m, _ := NewRSBFMatrix(n)
+		count := 0
+		seen := make(map[string]bool)
+		err := m.Enumerate(ctx, func(values []byte, walsh []int64) bool {
+			count++
+			seen[string(values)] = true
+			want, _ := m.Walsh(values)
+			if !reflect.DeepEqual(walsh, want) {
+				t.Fatalf("n=%d: 第 %d 个函数的增量 Walsh 谱不一致", n, count)
+			}
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Enumerate error: %v", err)
+		}
+		if count != 1<<uint(m.Orbits()) || len(seen) != count {
+			t.Errorf("n=%d: 枚举了 %d 个（不同的 %d 个）, 期望 %d", n, count, len(seen), 1<<uint(m.Orbits()))
+		}
+	}
+
+	// 7 元 RSBF 的最大非线性度是 56，且达到 56 的平衡 RSBF 存在
+	m, _ := NewRSBFMatrix(7)
+	var best, bestBalanced int64
+	err := m.Enumerate(ctx, func(values []byte, walsh []int64) bool {
+		nl := m.Nonlinearity(walsh)
+		best = max64(best, nl)
+		if walsh[0] == 0 {
+			bestBalanced = max64(bestBalanced, nl)
+		}
+		return true
+	})
+	if err != nil || best != 56 || bestBalanced != 56 {
+		t.Errorf("7 元 RSBF 最大非线性度为 %d（平衡 %d）, 期望 56 (%v)", best, bestBalanced, err)
+	}
+
+	count := 0
+	_ = m.Enumerate(ctx, func([]byte, []int64) bool { count++; return count < 10 })
+	if count != 10 {
+		t.Errorf("回调返回 false 后应停止, 实际枚举 %d 个", count)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := m.Enumerate(cancelled, func([]byte, []int64) bool { return true }); err != context.Canceled {
+		t.Errorf("取消后应返回 context.Canceled, 实际 %v", err)
+	}
+	m8, _ := NewRSBFMatrix(8)
+	if err := m8.Enumerate(ctx, func([]byte, []int64) bool { return true }); err == nil {
+		t.Error("n 超过枚举上限应返回错误")
+	}
+}
+
+// TestSymmetryChecks 检查 k-旋转对称与二面体对称
+func TestSymmetryChecks(t *testing.T) {
+	// x0x1 ⊕ x2x3 在循环移 2 位下不变，移 1 位时变为 x1x2 ⊕ x3x0
+	f, _ := NewFromANF(4, "x0*x1 + x2*x3")
+	for k, want := range map[int]bool{1: false, 2: true, 3: false, 4: true} {
+		if got, err := f.IsKRotationSymmetric(k); err != nil || got != want {
+			t.Errorf("IsKRotationSymmetric(%d) = %v, %v, 期望 %v", k, got, err, want)
+		}
+	}
+	if _, err := f.IsKRotationSymmetric(0); err == nil {
+		t.Error("k=0 应返回错误")
+	}
+	if _, err := f.IsKRotationSymmetric(5); err == nil {
+		t.Error("k>n 应返回错误")
+	}
+	g := randomRSBF(t, 6, 7)
+	if ok, _ := g.IsKRotationSymmetric(1); !ok {
+		t.Error("RSBF 应是 1-旋转对称的")
+	}
+
+	// 000111 的轨道在逆序下不变，001011 的逆序 110100 不在它的轨道中
+	dihedral, _ := NewFromRotationRepresentatives(6, []int{0b000111})
+	rotationOnly, _ := NewFromRotationRepresentatives(6, []int{0b001011})
+	if !dihedral.IsDihedralSymmetric() || rotationOnly.IsDihedralSymmetric() {
+		t.Errorf("二面体对称判断错误: %v %v", dihedral.IsDihedralSymmetric(), rotationOnly.IsDihedralSymmetric())
+	}
+	if f.IsDihedralSymmetric() {
+		t.Error("非旋转对称函数不是二面体对称的")
+	}
+}
+
+// TestSearchRotationSymmetric 检查 RSBF 搜索的结果与轨道级代价
+func TestSearchRotationSymmetric(t *testing.T) {
+	ctx := context.Background()
+	for _, alg := range []SearchAlgorithm{SearchHillClimbing, SearchAnnealing, SearchGenetic} {
+		for _, balanced := range []bool{false, true} {
+			opts := SearchOptions{N: 9, Algorithm: alg, Seed: 5, Balanced: balanced, RotationSymmetric: true, Iterations: 1000}
+			if alg == SearchGenetic {
+				opts.Iterations = 30
+			}
+			res, err := Search(ctx, opts)
+			if err != nil {
+				t.Fatalf("%s: Search error: %v", alg, err)
+			}
+			if !res.Function.IsRotationSymmetric() || (balanced && !res.IsBalanced) {
+				t.Errorf("%s balanced=%v: 结果应为旋转对称函数 (平衡 %v)", alg, balanced, res.IsBalanced)
+			}
+			if res.Nonlinearity < 232 {
+				t.Errorf("%s balanced=%v: 9 元 RSBF 搜索结果的非线性度过低: %d", alg, balanced, res.Nonlinearity)
+			}
+			if want := DefaultSearchCost.Evaluate(res.Function); math.Abs(res.Cost-want) > 1e-9 {
+				t.Errorf("%s: 轨道级代价 %v 与完整计算 %v 不一致", alg, res.Cost, want)
+			}
+		}
+	}
+
+	// 不限定平衡时不做子集和 DP
+	for n := 2; n <= 10; n++ {
+		if r, err := newRSBFSearch(n, false); err != nil || r.reach != nil {
+			t.Errorf("n=%d: 不限定平衡时不应计算平衡可行性 (%v)", n, err)
+		}
+		if _, err := newRSBFSearch(n, true); err != nil {
+			t.Errorf("n=%d: 平衡 RSBF 应存在: %v", n, err)
+		}
+	}
+
+	// 轨道级代价的各项与完整计算一致
+	m, _ := NewRSBFMatrix(8)
+	cost := SearchCost{Nonlinearity: 1, AbsoluteIndicator: 1, TransparencyOrder: 1, Imbalance: 1, Spectral: 1}
+	for seed := int64(0); seed < 5; seed++ {
+		f := randomRSBF(t, 8, seed)
+		values, _ := m.Values(f)
+		walsh, _ := m.Walsh(values)
+		if got, want := cost.evaluateOrbits(m, walsh), cost.Evaluate(f); math.Abs(got-want) > 1e-9 {
+			t.Errorf("evaluateOrbits = %v, Evaluate = %v", got, want)
+		}
+	}
+
+	// 自定义代价函数收到展开后的 RSBF
+	_, err := Search(ctx, SearchOptions{N: 6, RotationSymmetric: true, Iterations: 50, CostFunc: func(f *BooleanFunction) float64 {
+		if !f.IsRotationSymmetric() {
+			t.Fatal("CostFunc 收到的函数应旋转对称")
+		}
+		return -float64(f.Nonlinearity())
+	}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+
+	for _, opts := range []SearchOptions{
+		{N: MaxRSBFMatrixN + 1, RotationSymmetric: true},
+		{N: 6, RotationSymmetric: true, Initial: randomFunction(t, 6, 1)},
+	} {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("选项 n=%d 应校验失败", opts.N)
+		}
+	}
+}
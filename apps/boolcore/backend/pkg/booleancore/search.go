@@ -0,0 +1,553 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// 启发式搜索：在 n 元函数空间中寻找非线性度高、自相关低的函数。
+// 支持爬山、模拟退火与遗传算法三种策略，代价函数是非线性度、绝对指标、透明度阶、
+// 平衡性与 Clark–Jacob 谱代价的加权和（也可传入自定义函数）。
+// 邻域操作是翻转真值表的一位；限定平衡时改为交换一个 0 位与一个 1 位，搜索始终停留在平衡函数中。
+// 爬山与退火在 SearchState 上增量更新谱（见 search_state.go），每步 O(2^n)；遗传算法的子代从头计算。
+// RotationSymmetric 时只在 RSBF 中搜索，状态是轨道取值向量，谱在轨道级计算（见 rsbf_search.go）。
+// 随机数只来自以 Seed 初始化的 math/rand，相同的选项总是得到相同的结果。
+
+// MaxSearchN 是 Search 支持的最大变量个数，每次评估代价需要 O(n·2^n) 的谱计算.
+const MaxSearchN = 20
+
+// SearchAlgorithm 是搜索策略名.
+type SearchAlgorithm string
+
+const (
+	SearchHillClimbing SearchAlgorithm = "hillClimbing" // 爬山：只接受代价不增的邻居
+	SearchAnnealing    SearchAlgorithm = "annealing"    // 模拟退火：以 exp(-Δ/T) 的概率接受变差的邻居
+	SearchGenetic      SearchAlgorithm = "genetic"      // 遗传算法：锦标赛选择、单点交叉与变异，保留最优个体
+)
+
+// 选项的缺省值.
+const (
+	DefaultSearchIterations  = 10000 // 爬山、退火的步数
+	DefaultSearchGenerations = 200   // 遗传算法的代数
+	DefaultSearchPopulation  = 32    // 遗传算法的种群大小
+	DefaultSearchTemperature = 1.0   // 退火的初始温度
+)
+
+// SearchCost 是按权重组合的代价函数，代价越小越好：
+//
+//	cost(f) = −Nonlinearity·NL(f) + AbsoluteIndicator·Δ_f − TransparencyOrder·To(f)
+//	          + Imbalance·|wt(f) − 2^{n−1}| + Spectral·Σ_ω ||W_f(ω)| − X|³ / 2^{2n}
+//
+// 其中 X = SpectralTarget（为 0 时取 2^{⌈n/2⌉}）。谱代价一项即 Clark–Jacob 代价（R = 3），
+// 它随每次翻转连续变化，能在非线性度不变的平台上为局部搜索提供方向；除以 2^{2n} 使它与非线性度同一量级。
+// 权重为 0 的项不计算；只有 AbsoluteIndicator 或 TransparencyOrder 非零时才需要自相关谱。
+type SearchCost struct {
+	Nonlinearity      float64 `json:"nonlinearity"`
+	AbsoluteIndicator float64 `json:"absoluteIndicator"`
+	TransparencyOrder float64 `json:"transparencyOrder"`
+	Imbalance         float64 `json:"imbalance"`
+	Spectral          float64 `json:"spectral"`
+	SpectralTarget    float64 `json:"spectralTarget,omitempty"`
+}
+
+// DefaultSearchCost 以非线性度为主、绝对指标为辅，并用谱代价引导局部搜索.
+var DefaultSearchCost = SearchCost{Nonlinearity: 1, AbsoluteIndicator: 0.25, Spectral: 1}
+
+// IsZero 报告所有权重是否都为 0.
+func (c SearchCost) IsZero() bool {
+	return c.Nonlinearity == 0 && c.AbsoluteIndicator == 0 && c.TransparencyOrder == 0 &&
+		c.Imbalance == 0 && c.Spectral == 0
+}
+
+// Evaluate 计算 f 的代价.
+func (c SearchCost) Evaluate(f *BooleanFunction) float64 {
+	m := costMetrics{n: f.n}
+	if c.Spectral != 0 {
+		m.walsh = f.walsh()
+	}
+	if c.Nonlinearity != 0 {
+		m.nonlinearity = f.Nonlinearity()
+	}
+	if c.AbsoluteIndicator != 0 {
+		m.absoluteIndicator = f.AbsoluteIndicator()
+	}
+	if c.TransparencyOrder != 0 {
+		m.transparencyOrder = f.TransparencyOrder()
+	}
+	if c.Imbalance != 0 {
+		m.weight = f.HammingWeight()
+	}
+	return c.combine(m)
+}
+
+// EvaluateState 计算搜索状态当前函数的代价，结果与对 st.Function() 调用 Evaluate 相同，
+// 但除谱代价一项（O(2^n)）外都是 O(1).
+func (c SearchCost) EvaluateState(st *SearchState) float64 {
+	return c.combine(costMetrics{
+		n:                 st.n,
+		walsh:             st.walsh,
+		nonlinearity:      st.Nonlinearity(),
+		absoluteIndicator: st.AbsoluteIndicator(),
+		transparencyOrder: st.TransparencyOrder(),
+		weight:            st.HammingWeight(),
+	})
+}
+
+// SearchOptions 是 Search 的参数，零值字段取缺省值.
+type SearchOptions struct {
+	N         int             `json:"n"`
+	Algorithm SearchAlgorithm `json:"algorithm"` // 为空时取 SearchHillClimbing
+	Seed      int64           `json:"seed"`
+	Balanced  bool            `json:"balanced"` // 只在平衡函数中搜索（硬约束；软约束用 SearchCost.Imbalance）
+
+	// RotationSymmetric 只在旋转对称函数中搜索，要求 n ≤ MaxRSBFMatrixN
+	RotationSymmetric bool `json:"rotationSymmetric"`
+
+	// Cost 为全 0 时取 DefaultSearchCost；CostFunc 非 nil 时代替 Cost，传入的函数只在调用期间有效
+	Cost     SearchCost                       `json:"cost"`
+	CostFunc func(f *BooleanFunction) float64 `json:"-"`
+
+	// Iterations 对爬山与退火是候选步数，对遗传算法是代数
+	Iterations int `json:"iterations"`
+	// Initial 是爬山与退火的起点、遗传算法初始种群中的一员，nil 时随机生成
+	Initial *BooleanFunction `json:"-"`
+
+	Temperature float64 `json:"temperature,omitempty"` // 退火的初始温度
+	CoolingRate float64 `json:"coolingRate,omitempty"` // 退火每步的降温系数，0 时使最后一步的温度为初始温度的 1/1000
+	Population  int     `json:"population,omitempty"`  // 遗传算法的种群大小
+	Mutations   int     `json:"mutations,omitempty"`   // 遗传算法中每个子代的邻域操作次数，0 时取 1
+}
+
+// SearchResult 是 Search 找到的最优函数及其指标.
+type SearchResult struct {
+	Function          *BooleanFunction `json:"-"`
+	Algorithm         SearchAlgorithm  `json:"algorithm"`
+	Seed              int64            `json:"seed"`
+	Cost              float64          `json:"cost"`
+	Nonlinearity      int64            `json:"nonlinearity"`
+	AbsoluteIndicator int64            `json:"absoluteIndicator"`
+	TransparencyOrder float64          `json:"transparencyOrder"`
+	IsBalanced        bool             `json:"isBalanced"`
+	Iterations        int              `json:"iterations"`  // 实际完成的步数或代数
+	Evaluations       int              `json:"evaluations"` // 代价函数的调用次数
+}
+
+// Validate 检查选项是否合法，Search 会做同样的检查；可用于在提交异步任务前提前报错.
+func (opts SearchOptions) Validate() error {
+	_, err := opts.withDefaults()
+	return err
+}
+
+// Search 按 opts 执行启发式搜索，返回代价最小的函数.
+// ctx 取消时返回 ctx.Err()；通过 WithProgress 挂载的回调会收到 StageSearch 阶段的进度.
+func Search(ctx context.Context, opts SearchOptions) (*SearchResult, error) {
+	s, err := newSearcher(opts)
+	if err != nil {
+		return nil, err
+	}
+	genetic := s.opts.Algorithm == SearchGenetic
+	switch {
+	case s.rsbf == nil && !genetic:
+		err = s.localSearch(ctx)
+	case s.rsbf == nil:
+		err = s.genetic(ctx)
+	case !genetic:
+		err = s.rotationLocalSearch(ctx)
+	default:
+		err = s.rotationGenetic(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	best := &BooleanFunction{n: s.n, packedTruthTable: s.best}
+	return &SearchResult{
+		Function:          best,
+		Algorithm:         s.opts.Algorithm,
+		Seed:              s.opts.Seed,
+		Cost:              s.bestCost,
+		Nonlinearity:      best.Nonlinearity(),
+		AbsoluteIndicator: best.AbsoluteIndicator(),
+		TransparencyOrder: best.TransparencyOrder(),
+		IsBalanced:        best.IsBalanced(),
+		Iterations:        s.iterations,
+		Evaluations:       s.evaluations,
+	}, nil
+}
+
+// --- 私有实现 ---
+
+// costMetrics 是计算代价所需的指标，权重为 0 的项可以不填.
+// 轨道级评估时 walsh 每项代表一个轨道，multiplicity 是各轨道的大小.
+type costMetrics struct {
+	n                 int
+	walsh             []int64
+	multiplicity      []int
+	nonlinearity      int64
+	absoluteIndicator int64
+	transparencyOrder float64
+	weight            int
+}
+
+// combine 按权重组合各项指标，Evaluate 与 EvaluateState 共用以保证结果一致.
+func (c SearchCost) combine(m costMetrics) float64 {
+	var cost float64
+	if c.Nonlinearity != 0 {
+		cost -= c.Nonlinearity * float64(m.nonlinearity)
+	}
+	if c.AbsoluteIndicator != 0 {
+		cost += c.AbsoluteIndicator * float64(m.absoluteIndicator)
+	}
+	if c.TransparencyOrder != 0 {
+		cost -= c.TransparencyOrder * m.transparencyOrder
+	}
+	if c.Imbalance != 0 {
+		d := m.weight - 1<<uint(m.n-1)
+		if d < 0 {
+			d = -d
+		}
+		cost += c.Imbalance * float64(d)
+	}
+	if c.Spectral != 0 {
+		x := c.SpectralTarget
+		if x == 0 {
+			x = float64(int64(1) << uint((m.n+1)/2))
+		}
+		var sum float64
+		for i, w := range m.walsh {
+			d := math.Abs(math.Abs(float64(w)) - x)
+			if m.multiplicity != nil {
+				sum += float64(m.multiplicity[i]) * d * d * d
+			} else {
+				sum += d * d * d
+			}
+		}
+		cost += c.Spectral * sum / float64(int64(1)<<uint(2*m.n))
+	}
+	return cost
+}
+
+// searcher 保存一次搜索的状态.
+type searcher struct {
+	opts SearchOptions
+	n    int
+	size int // 2^n
+	rng  *rand.Rand
+	rsbf *rsbfSearch // RotationSymmetric 时的轨道级状态，否则为 nil
+
+	best        []uint64
+	bestCost    float64
+	iterations  int
+	evaluations int
+}
+
+// withDefaults 校验选项并填入缺省值.
+func (opts SearchOptions) withDefaults() (SearchOptions, error) {
+	if opts.N < 2 || opts.N > MaxSearchN {
+		return opts, fmt.Errorf("n must be in [2, %d], got %d", MaxSearchN, opts.N)
+	}
+	switch opts.Algorithm {
+	case "":
+		opts.Algorithm = SearchHillClimbing
+	case SearchHillClimbing, SearchAnnealing, SearchGenetic:
+	default:
+		return opts, fmt.Errorf("unsupported search algorithm %q, must be one of [%s, %s, %s]",
+			opts.Algorithm, SearchHillClimbing, SearchAnnealing, SearchGenetic)
+	}
+	if opts.Iterations < 0 || opts.Population < 0 || opts.Mutations < 0 {
+		return opts, errors.New("iterations, population and mutations must be non-negative")
+	}
+	if opts.Iterations == 0 {
+		opts.Iterations = DefaultSearchIterations
+		if opts.Algorithm == SearchGenetic {
+			opts.Iterations = DefaultSearchGenerations
+		}
+	}
+	if opts.Cost.IsZero() {
+		opts.Cost = DefaultSearchCost
+	}
+	if opts.Temperature < 0 || opts.CoolingRate < 0 || opts.CoolingRate > 1 {
+		return opts, errors.New("temperature must be non-negative and coolingRate must be in [0, 1]")
+	}
+	if opts.Temperature == 0 {
+		opts.Temperature = DefaultSearchTemperature
+	}
+	if opts.CoolingRate == 0 {
+		opts.CoolingRate = math.Pow(1e-3, 1/float64(opts.Iterations))
+	}
+	if opts.Population == 0 {
+		opts.Population = DefaultSearchPopulation
+	}
+	if opts.Population < 2 && opts.Algorithm == SearchGenetic {
+		return opts, errors.New("population must be at least 2")
+	}
+	if opts.Mutations == 0 {
+		opts.Mutations = 1
+	}
+	if opts.RotationSymmetric && opts.N > MaxRSBFMatrixN {
+		return opts, fmt.Errorf("rotationSymmetric search supports n <= %d, got %d", MaxRSBFMatrixN, opts.N)
+	}
+	if opts.Initial != nil {
+		if opts.Initial.n != opts.N {
+			return opts, fmt.Errorf("initial function must have %d variables, got %d", opts.N, opts.Initial.n)
+		}
+		if opts.Balanced && !opts.Initial.IsBalanced() {
+			return opts, errors.New("initial function must be balanced when balanced is set")
+		}
+		if opts.RotationSymmetric && !opts.Initial.IsRotationSymmetric() {
+			return opts, errors.New("initial function must be rotation symmetric when rotationSymmetric is set")
+		}
+	}
+	return opts, nil
+}
+
+// newSearcher 按校验后的选项创建搜索状态.
+func newSearcher(opts SearchOptions) (*searcher, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	s := &searcher{
+		opts: opts,
+		n:    opts.N,
+		size: 1 << uint(opts.N),
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+	}
+	if opts.RotationSymmetric {
+		if s.rsbf, err = newRSBFSearch(opts.N, opts.Balanced); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// cost 评估打包真值表 packed 的代价，每次使用新的 BooleanFunction 以免缓存失效.
+func (s *searcher) cost(packed []uint64) float64 {
+	s.evaluations++
+	f := &BooleanFunction{n: s.n, packedTruthTable: packed}
+	if s.opts.CostFunc != nil {
+		return s.opts.CostFunc(f)
+	}
+	return s.opts.Cost.Evaluate(f)
+}
+
+// stateCost 评估搜索状态当前函数的代价.
+func (s *searcher) stateCost(st *SearchState) float64 {
+	if s.opts.CostFunc != nil {
+		return s.cost(st.packed)
+	}
+	s.evaluations++
+	return s.opts.Cost.EvaluateState(st)
+}
+
+// randomPacked 返回随机函数；限定平衡时返回随机的平衡函数.
+func (s *searcher) randomPacked() []uint64 {
+	packed := make([]uint64, packedWords(s.n))
+	if s.opts.Balanced {
+		for _, x := range s.rng.Perm(s.size)[:s.size/2] {
+			packed[x>>6] |= 1 << uint(x&63)
+		}
+		return packed
+	}
+	for i := range packed {
+		packed[i] = s.rng.Uint64()
+	}
+	packed[len(packed)-1] &= lastWordMask(s.n)
+	return packed
+}
+
+// initialPacked 返回 opts.Initial 的副本，未指定时返回随机函数.
+func (s *searcher) initialPacked() []uint64 {
+	if s.opts.Initial == nil {
+		return s.randomPacked()
+	}
+	return append([]uint64(nil), s.opts.Initial.packedTruthTable...)
+}
+
+// randomIndexWithValue 随机返回一个取值为 v 的位置，调用方保证这样的位置存在.
+func (s *searcher) randomIndexWithValue(packed []uint64, v uint64) int {
+	for {
+		x := s.rng.Intn(s.size)
+		if packed[x>>6]>>uint(x&63)&1 == v {
+			return x
+		}
+	}
+}
+
+// pick 随机选择一次邻域操作的位置：不限定平衡时翻转 x（y = -1），
+// 限定平衡时交换一个 1 位 x 与一个 0 位 y.
+func (s *searcher) pick(packed []uint64) (x, y int) {
+	if !s.opts.Balanced {
+		return s.rng.Intn(s.size), -1
+	}
+	x = s.randomIndexWithValue(packed, 1)
+	y = s.randomIndexWithValue(packed, 0)
+	return x, y
+}
+
+// move 对 packed 做一次随机的邻域操作.
+func (s *searcher) move(packed []uint64) {
+	x, y := s.pick(packed)
+	flipBit(packed, x)
+	if y >= 0 {
+		flipBit(packed, y)
+	}
+}
+
+func flipBit(packed []uint64, x int) {
+	packed[x>>6] ^= 1 << uint(x&63)
+}
+
+// record 在 cost 更小时把 packed 记为目前最优.
+func (s *searcher) record(packed []uint64, cost float64) {
+	if s.best == nil || cost < s.bestCost {
+		s.best = append(s.best[:0], packed...)
+		s.bestCost = cost
+	}
+}
+
+// report 每完成约 1% 的迭代发送一次进度.
+func (s *searcher) report(ctx context.Context, done bool) {
+	step := s.opts.Iterations / 100
+	if step < 1 {
+		step = 1
+	}
+	if !done && (s.iterations%step != 0 || s.iterations == s.opts.Iterations) {
+		return // 最后一次由 done 报告
+	}
+	cost := s.bestCost
+	base := ProgressEvent{Stage: StageSearch, Metric: string(s.opts.Algorithm), Rows: s.opts.Iterations, Cost: &cost}
+	reportProgress(withProgressBase(ctx, base), s.iterations, done)
+}
+
+// localSearch 执行爬山或模拟退火：爬山接受代价不增的邻居（允许在平台上移动），
+// 退火另以 exp(-Δ/T) 的概率接受变差的邻居，T 每步乘以 CoolingRate.
+// 邻居在 SearchState 上增量生成与评估，被拒绝时撤销.
+func (s *searcher) localSearch(ctx context.Context) error {
+	st := NewSearchState(&BooleanFunction{n: s.n, packedTruthTable: s.initialPacked()})
+	curCost := s.stateCost(st)
+	s.record(st.packed, curCost)
+	temperature := s.opts.Temperature
+
+	for s.iterations < s.opts.Iterations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		if x, y := s.pick(st.packed); y < 0 {
+			err = st.Flip(x)
+		} else {
+			err = st.Swap(x, y)
+		}
+		if err != nil {
+			return err
+		}
+		cost := s.stateCost(st)
+		if s.accept(cost-curCost, temperature) {
+			curCost = cost
+			s.record(st.packed, cost)
+			st.Commit()
+		} else if err = st.Undo(); err != nil {
+			return err
+		}
+		temperature *= s.opts.CoolingRate
+		s.iterations++
+		s.report(ctx, false)
+	}
+	s.report(ctx, true)
+	return nil
+}
+
+// accept 判断是否接受代价变化为 delta 的邻居：爬山接受 delta ≤ 0，退火另以 exp(-delta/T) 的概率接受.
+func (s *searcher) accept(delta, temperature float64) bool {
+	if delta <= 0 {
+		return true
+	}
+	return s.opts.Algorithm == SearchAnnealing && s.rng.Float64() < math.Exp(-delta/temperature)
+}
+
+// individual 是遗传算法种群中的一个函数.
+type individual struct {
+	packed []uint64
+	cost   float64
+}
+
+// genetic 执行遗传算法：每代保留最优个体，其余个体由锦标赛选出的两个父代单点交叉，
+// 限定平衡时修复为平衡函数，再做 Mutations 次邻域操作得到.
+func (s *searcher) genetic(ctx context.Context) error {
+	pop := make([]individual, s.opts.Population)
+	for i := range pop {
+		packed := s.randomPacked()
+		if i == 0 {
+			packed = s.initialPacked()
+		}
+		pop[i] = individual{packed: packed, cost: s.cost(packed)}
+		s.record(packed, pop[i].cost)
+	}
+
+	next := make([]individual, len(pop))
+	for s.iterations < s.opts.Iterations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next[0] = individual{packed: append([]uint64(nil), s.best...), cost: s.bestCost}
+		for i := 1; i < len(next); i++ {
+			child := s.crossover(s.tournament(pop).packed, s.tournament(pop).packed)
+			if s.opts.Balanced {
+				s.rebalance(child)
+			}
+			for k := 0; k < s.opts.Mutations; k++ {
+				s.move(child)
+			}
+			next[i] = individual{packed: child, cost: s.cost(child)}
+			s.record(child, next[i].cost)
+		}
+		pop, next = next, pop
+		s.iterations++
+		s.report(ctx, false)
+	}
+	s.report(ctx, true)
+	return nil
+}
+
+// tournament 随机抽取 3 个个体，返回其中代价最小者.
+func (s *searcher) tournament(pop []individual) individual {
+	best := pop[s.rng.Intn(len(pop))]
+	for k := 1; k < 3; k++ {
+		if c := pop[s.rng.Intn(len(pop))]; c.cost < best.cost {
+			best = c
+		}
+	}
+	return best
+}
+
+// crossover 返回单点交叉的子代：前 cut 位来自 a，其余来自 b，cut ∈ [1, 2^n).
+func (s *searcher) crossover(a, b []uint64) []uint64 {
+	cut := 1 + s.rng.Intn(s.size-1)
+	child := append([]uint64(nil), b...)
+	w := cut >> 6
+	copy(child, a[:w])
+	if r := uint(cut & 63); r != 0 {
+		mask := uint64(1)<<r - 1
+		child[w] = a[w]&mask | b[w]&^mask
+	}
+	return child
+}
+
+// rebalance 随机翻转多数取值的位，直到重量为 2^{n-1}.
+func (s *searcher) rebalance(packed []uint64) {
+	weight := 0
+	for _, w := range packed {
+		weight += bits.OnesCount64(w)
+	}
+	for ; weight > s.size/2; weight-- {
+		flipBit(packed, s.randomIndexWithValue(packed, 1))
+	}
+	for ; weight < s.size/2; weight++ {
+		flipBit(packed, s.randomIndexWithValue(packed, 0))
+	}
+}
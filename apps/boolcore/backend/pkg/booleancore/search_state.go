@@ -0,0 +1,232 @@
+package booleancore
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// 搜索状态：局部搜索每一步只翻转或交换真值表的一两位，
+// 从头计算 Walsh 谱与自相关谱都是 O(n·2^n)，而一位的变化可以在 O(2^n) 内增量更新：
+//
+//	翻转 x（σ = (-1)^{f(x)} 为翻转前的符号）:
+//	  W(ω) ← W(ω) − 2σ·(-1)^{ω·x}
+//	  C(a) ← C(a) − 4σ·(-1)^{f(x⊕a)}，a ≠ 0（C(0) = 2^n 不变）
+//
+// 更新的同一趟循环里顺带维护 max|W|、max_{a≠0}|C(a)| 与 Σ_{a≠0}|C(a)|，
+// 因此非线性度、绝对指标与透明度阶都是 O(1) 读取。
+// 每次 Flip/Swap 都记入历史，Undo 按相反顺序撤销；接受一步后调用 Commit 清空历史。
+
+// SearchState 是可变的搜索状态，不是并发安全的.
+type SearchState struct {
+	n      int
+	packed []uint64
+	walsh  []int64
+	ac     []int64
+
+	maxAbsWalsh int64 // max_ω |W(ω)|
+	maxAbsAC    int64 // max_{a≠0} |C(a)|
+	sumAbsAC    int64 // Σ_{a≠0} |C(a)|
+
+	history []searchMove
+}
+
+// searchMove 是一次 Flip（y = -1）或 Swap.
+type searchMove struct {
+	x, y int
+}
+
+// NewSearchState 以 f 为初始函数创建搜索状态，f 本身不会被修改.
+func NewSearchState(f *BooleanFunction) *SearchState {
+	st := &SearchState{
+		n:      f.n,
+		packed: append([]uint64(nil), f.packedTruthTable...),
+		walsh:  append([]int64(nil), f.walsh()...),
+		ac:     append([]int64(nil), f.autocorrelation()...),
+	}
+	st.maxAbsWalsh = maxAbsValue(st.walsh, 0)
+	st.maxAbsAC = maxAbsValue(st.ac, 1)
+	for _, v := range st.ac[1:] {
+		st.sumAbsAC += abs64(v)
+	}
+	return st
+}
+
+// N 返回变量个数.
+func (st *SearchState) N() int { return st.n }
+
+// Value 返回当前函数在 x 处的取值，x 越界时返回 0.
+func (st *SearchState) Value(x int) byte {
+	if x < 0 || x >= len(st.walsh) {
+		return 0
+	}
+	return byte(st.bit(x))
+}
+
+// Flip 翻转 x 处的取值.
+func (st *SearchState) Flip(x int) error {
+	if x < 0 || x >= len(st.walsh) {
+		return fmt.Errorf("index %d out of range [0, %d)", x, len(st.walsh))
+	}
+	st.flip(x)
+	st.history = append(st.history, searchMove{x: x, y: -1})
+	return nil
+}
+
+// Swap 交换 x 与 y 处的取值，两处取值必须不同（交换相同的值没有意义），汉明重量保持不变.
+func (st *SearchState) Swap(x, y int) error {
+	size := len(st.walsh)
+	if x < 0 || x >= size || y < 0 || y >= size {
+		return fmt.Errorf("indices %d, %d out of range [0, %d)", x, y, size)
+	}
+	if st.bit(x) == st.bit(y) {
+		return fmt.Errorf("swap requires different values at %d and %d", x, y)
+	}
+	st.swap(x, y)
+	st.history = append(st.history, searchMove{x: x, y: y})
+	return nil
+}
+
+// Undo 撤销最近一次尚未提交的 Flip 或 Swap.
+func (st *SearchState) Undo() error {
+	if len(st.history) == 0 {
+		return errors.New("nothing to undo")
+	}
+	m := st.history[len(st.history)-1]
+	st.history = st.history[:len(st.history)-1]
+	if m.y >= 0 {
+		st.swap(m.x, m.y) // 交换后两处取值仍然不同，再交换一次即还原
+	} else {
+		st.flip(m.x)
+	}
+	return nil
+}
+
+// Commit 清空撤销历史，之前的修改不能再撤销.
+func (st *SearchState) Commit() {
+	st.history = st.history[:0]
+}
+
+// Nonlinearity 返回当前函数的非线性度.
+func (st *SearchState) Nonlinearity() int64 {
+	return (1 << (st.n - 1)) - st.maxAbsWalsh/2
+}
+
+// AbsoluteIndicator 返回当前函数的绝对指标 max_{a≠0} |C(a)|.
+func (st *SearchState) AbsoluteIndicator() int64 {
+	return st.maxAbsAC
+}
+
+// TransparencyOrder 返回当前函数的透明度阶，定义同 BooleanFunction.TransparencyOrder.
+func (st *SearchState) TransparencyOrder() float64 {
+	length := len(st.ac)
+	if length <= 1 {
+		return 1.0
+	}
+	return 1.0 - float64(st.sumAbsAC)/float64(length*(length-1))
+}
+
+// HammingWeight 返回当前函数的汉明重量，由 W(0) = 2^n − 2·wt(f) 得到.
+func (st *SearchState) HammingWeight() int {
+	return (len(st.walsh) - int(st.walsh[0])) / 2
+}
+
+// WalshHadamardTransform 返回当前 Walsh 谱的副本.
+func (st *SearchState) WalshHadamardTransform() []int64 {
+	return append([]int64(nil), st.walsh...)
+}
+
+// Autocorrelation 返回当前自相关谱的副本.
+func (st *SearchState) Autocorrelation() []int64 {
+	return append([]int64(nil), st.ac...)
+}
+
+// Function 返回当前函数的快照，之后对状态的修改不会影响它.
+func (st *SearchState) Function() *BooleanFunction {
+	return &BooleanFunction{n: st.n, packedTruthTable: append([]uint64(nil), st.packed...)}
+}
+
+// --- 私有实现 ---
+
+func (st *SearchState) bit(x int) uint64 {
+	return st.packed[x>>6] >> uint(x&63) & 1
+}
+
+// sign 返回 (-1)^{f(x)}.
+func (st *SearchState) sign(x int) int64 {
+	return packedSign(st.packed, x)
+}
+
+// packedSign 返回打包真值表在 x 处的 (-1)^{f(x)}.
+func packedSign(packed []uint64, x int) int64 {
+	return 1 - 2*int64(packed[x>>6]>>uint(x&63)&1)
+}
+
+// parity 返回 (-1)^{a·b}.
+func parity(a, b int) int64 {
+	return 1 - 2*int64(bits.OnesCount64(uint64(a&b))&1)
+}
+
+// flip 翻转 x 并增量更新两个谱及其统计量.
+func (st *SearchState) flip(x int) {
+	walsh, ac, packed := st.walsh, st.ac, st.packed
+	sx := st.sign(x)
+	var maxW int64
+	for w := range walsh {
+		walsh[w] -= 2 * sx * parity(w, x)
+		maxW = max64(maxW, abs64(walsh[w]))
+	}
+	st.maxAbsWalsh = maxW
+
+	var maxC, sumC int64
+	for a := 1; a < len(ac); a++ {
+		ac[a] -= 4 * sx * packedSign(packed, x^a)
+		v := abs64(ac[a])
+		sumC += v
+		maxC = max64(maxC, v)
+	}
+	st.maxAbsAC = maxC
+	st.sumAbsAC = sumC
+
+	packed[x>>6] ^= 1 << uint(x&63)
+}
+
+// swap 同时翻转 x、y（x ≠ y），在一趟循环里完成两次翻转的更新.
+// 依次翻转时第二次更新看到的是第一次翻转后的取值，两者只在 a = x⊕y 处相互抵消，
+// 因此 C(x⊕y) 不变，其余位置的增量直接相加.
+func (st *SearchState) swap(x, y int) {
+	walsh, ac, packed := st.walsh, st.ac, st.packed
+	sx, sy := st.sign(x), st.sign(y)
+	var maxW int64
+	for w := range walsh {
+		walsh[w] -= 2 * (sx*parity(w, x) + sy*parity(w, y))
+		maxW = max64(maxW, abs64(walsh[w]))
+	}
+	st.maxAbsWalsh = maxW
+
+	var maxC, sumC int64
+	for a := 1; a < len(ac); a++ {
+		if a != x^y {
+			ac[a] -= 4 * (sx*packedSign(packed, x^a) + sy*packedSign(packed, y^a))
+		}
+		v := abs64(ac[a])
+		sumC += v
+		maxC = max64(maxC, v)
+	}
+	st.maxAbsAC = maxC
+	st.sumAbsAC = sumC
+
+	packed[x>>6] ^= 1 << uint(x&63)
+	packed[y>>6] ^= 1 << uint(y&63)
+}
+
+// abs64 与 max64 写成无分支形式，谱更新的循环里分支预测几乎总是失败.
+func abs64(v int64) int64 {
+	m := v >> 63
+	return (v ^ m) - m
+}
+
+func max64(a, b int64) int64 {
+	d := a - b
+	return a - d&(d>>63)
+}
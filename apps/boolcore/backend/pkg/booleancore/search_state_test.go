@@ -0,0 +1,139 @@
+package booleancore
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkSearchState 断言增量维护的谱与统计量和从头计算的结果一致
+func checkSearchState(t *testing.T, st *SearchState, step int) {
+	t.Helper()
+	f := st.Function()
+	if !reflect.DeepEqual(st.WalshHadamardTransform(), f.WalshHadamardTransform()) {
+		t.Fatalf("n=%d 第 %d 步: Walsh 谱不一致", st.N(), step)
+	}
+	if !reflect.DeepEqual(st.Autocorrelation(), f.Autocorrelation()) {
+		t.Fatalf("n=%d 第 %d 步: 自相关谱不一致", st.N(), step)
+	}
+	if st.Nonlinearity() != f.Nonlinearity() || st.AbsoluteIndicator() != f.AbsoluteIndicator() ||
+		st.TransparencyOrder() != f.TransparencyOrder() || st.HammingWeight() != f.HammingWeight() {
+		t.Fatalf("n=%d 第 %d 步: 统计量不一致 (%d, %d, %v, %d) vs (%d, %d, %v, %d)", st.N(), step,
+			st.Nonlinearity(), st.AbsoluteIndicator(), st.TransparencyOrder(), st.HammingWeight(),
+			f.Nonlinearity(), f.AbsoluteIndicator(), f.TransparencyOrder(), f.HammingWeight())
+	}
+	if cost := (SearchCost{Nonlinearity: 1, AbsoluteIndicator: 1, TransparencyOrder: 1, Imbalance: 1, Spectral: 1}); cost.EvaluateState(st) != cost.Evaluate(f) {
+		t.Fatalf("n=%d 第 %d 步: EvaluateState 与 Evaluate 不一致", st.N(), step)
+	}
+}
+
+// TestSearchStateIncremental 随机翻转、交换与撤销，逐步与从头计算的结果比较
+func TestSearchStateIncremental(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	for n := 1; n <= 8; n++ {
+		f := randomFunction(t, n, int64(n))
+		st := NewSearchState(f)
+		checkSearchState(t, st, 0)
+		size := 1 << uint(n)
+		for step := 1; step <= 200; step++ {
+			switch op := rng.Intn(4); {
+			case op == 0:
+				if err := st.Flip(rng.Intn(size)); err != nil {
+					t.Fatalf("Flip error: %v", err)
+				}
+			case op == 1 && st.HammingWeight() > 0 && st.HammingWeight() < size:
+				x, y := rng.Intn(size), rng.Intn(size)
+				for st.Value(x) != 1 {
+					x = rng.Intn(size)
+				}
+				for st.Value(y) != 0 {
+					y = rng.Intn(size)
+				}
+				if err := st.Swap(x, y); err != nil {
+					t.Fatalf("Swap error: %v", err)
+				}
+			case op == 2:
+				// 没有未提交的操作时返回错误，这里忽略
+				_ = st.Undo()
+			default:
+				st.Commit()
+			}
+			checkSearchState(t, st, step)
+		}
+		if !f.Equal(randomFunction(t, n, int64(n))) {
+			t.Errorf("n=%d: 初始函数不应被修改", n)
+		}
+	}
+}
+
+// TestSearchStateUndo 检查撤销的顺序与边界
+func TestSearchStateUndo(t *testing.T) {
+	f := randomFunction(t, 6, 3)
+	st := NewSearchState(f)
+	if err := st.Undo(); err == nil {
+		t.Error("没有可撤销的操作时应返回错误")
+	}
+	_ = st.Flip(5)
+	_ = st.Flip(9)
+	x, y := 0, 1
+	for st.Value(x) == st.Value(y) {
+		y++
+	}
+	if err := st.Swap(x, y); err != nil {
+		t.Fatalf("Swap error: %v", err)
+	}
+	if st.Function().Equal(f) {
+		t.Fatal("修改后函数应改变")
+	}
+	for i := 0; i < 3; i++ {
+		if err := st.Undo(); err != nil {
+			t.Fatalf("Undo error: %v", err)
+		}
+	}
+	if !st.Function().Equal(f) {
+		t.Error("全部撤销后应还原初始函数")
+	}
+	checkSearchState(t, st, 3)
+
+	_ = st.Flip(7)
+	st.Commit()
+	if err := st.Undo(); err == nil {
+		t.Error("提交后不能再撤销")
+	}
+
+	if err := st.Flip(64); err == nil {
+		t.Error("下标越界应返回错误")
+	}
+	if err := st.Swap(-1, 0); err == nil {
+		t.Error("下标越界应返回错误")
+	}
+	if err := st.Swap(x, x); err == nil {
+		t.Error("交换相同取值应返回错误")
+	}
+}
+
+// BenchmarkSearchStateSwap 比较增量更新与从头计算两个谱的开销（n=12）
+func BenchmarkSearchStateSwap(b *testing.B) {
+	f := randomFunction(b, 12, 1)
+	rng := rand.New(rand.NewSource(1))
+	b.Run("Incremental", func(b *testing.B) {
+		st := NewSearchState(f)
+		for i := 0; i < b.N; i++ {
+			x, y := rng.Intn(4096), rng.Intn(4096)
+			if st.Value(x) != st.Value(y) {
+				_ = st.Swap(x, y)
+				_ = st.Undo()
+			}
+		}
+	})
+	b.Run("Recompute", func(b *testing.B) {
+		tt := f.TruthTable()
+		for i := 0; i < b.N; i++ {
+			x, y := rng.Intn(4096), rng.Intn(4096)
+			tt[x], tt[y] = tt[y], tt[x]
+			g, _ := NewFromTruthTable(tt)
+			_ = g.Nonlinearity()
+			_ = g.AbsoluteIndicator()
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package booleancore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSearchAlgorithms 检查三种策略的确定性、平衡约束与搜索效果
+func TestSearchAlgorithms(t *testing.T) {
+	for _, alg := range []SearchAlgorithm{SearchHillClimbing, SearchAnnealing, SearchGenetic} {
+		opts := SearchOptions{N: 8, Algorithm: alg, Seed: 23, Balanced: true, Iterations: 3000}
+		if alg == SearchGenetic {
+			opts.Iterations = 100
+		}
+		r1, err := Search(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("%s: Search error: %v", alg, err)
+		}
+		r2, _ := Search(context.Background(), opts)
+		if !r1.Function.Equal(r2.Function) || r1.Cost != r2.Cost || r1.Evaluations != r2.Evaluations {
+			t.Errorf("%s: 相同种子应得到相同结果", alg)
+		}
+		if !r1.IsBalanced || !r1.Function.IsBalanced() {
+			t.Errorf("%s: 限定平衡时结果应平衡", alg)
+		}
+		if r1.Iterations != opts.Iterations {
+			t.Errorf("%s: 应完成 %d 次迭代, 实际 %d", alg, opts.Iterations, r1.Iterations)
+		}
+		if r1.Cost != DefaultSearchCost.Evaluate(r1.Function) {
+			t.Errorf("%s: 报告的代价 %v 与重新计算的不一致", alg, r1.Cost)
+		}
+		// 随机平衡 8 元函数的非线性度约为 100，搜索应明显更好
+		if r1.Nonlinearity < 108 || r1.Nonlinearity != r1.Function.Nonlinearity() ||
+			r1.AbsoluteIndicator != r1.Function.AbsoluteIndicator() {
+			t.Errorf("%s: nl=%d ac=%d", alg, r1.Nonlinearity, r1.AbsoluteIndicator)
+		}
+	}
+
+	// 不限定平衡时，Imbalance 权重足够大即可得到平衡函数
+	r, err := Search(context.Background(), SearchOptions{N: 6, Seed: 1, Iterations: 2000,
+		Cost: SearchCost{Nonlinearity: 1, Imbalance: 10}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if !r.IsBalanced || r.Nonlinearity < 24 {
+		t.Errorf("平衡软约束: nl=%d balanced=%v", r.Nonlinearity, r.IsBalanced)
+	}
+}
+
+// TestSearchInitialAndCostFunc 从已知函数出发搜索，并使用自定义代价函数
+func TestSearchInitialAndCostFunc(t *testing.T) {
+	// Data/布尔函数.md 中的 9 元结果：nl = 242, ac = 32, To = 0.9832
+	hex := strings.ReplaceAll("3340 b6a1 1821 f196 42a8 5e2b 7e2f 3c3c b65f a0d9 5ec9 db1e ab2b db36 6618 5ae0 "+
+		"087f 5fe6 e075 7106 212f c918 754c 40e8 a1bc cbfa 7140 32a8 9614 56e0 66e8 a801", " ", "")
+	f, err := NewFromHex(hex, 9)
+	if err != nil {
+		t.Fatalf("NewFromHex error: %v", err)
+	}
+	r, err := Search(context.Background(), SearchOptions{N: 9, Seed: 5, Iterations: 200, Initial: f,
+		Cost: SearchCost{Nonlinearity: 1, AbsoluteIndicator: 1}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if r.Nonlinearity-r.AbsoluteIndicator < 242-32 {
+		t.Errorf("结果不应差于初始函数: nl=%d ac=%d", r.Nonlinearity, r.AbsoluteIndicator)
+	}
+
+	// 自定义代价：最小化汉明重量，爬山应得到零函数
+	zero := func(g *BooleanFunction) float64 { return float64(g.HammingWeight()) }
+	r, err = Search(context.Background(), SearchOptions{N: 4, Seed: 2, Iterations: 2000, CostFunc: zero})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if r.Cost != 0 || r.Function.HammingWeight() != 0 {
+		t.Errorf("自定义代价: 期望零函数, 实际重量 %d", r.Function.HammingWeight())
+	}
+
+	bad := []SearchOptions{
+		{N: 1},
+		{N: MaxSearchN + 1},
+		{N: 6, Algorithm: "tabu"},
+		{N: 6, Iterations: -1},
+		{N: 6, CoolingRate: 2},
+		{N: 6, Algorithm: SearchGenetic, Population: 1},
+		{N: 6, Initial: f},
+		{N: 9, Balanced: true, Initial: f},
+	}
+	for _, opts := range bad {
+		if _, err := Search(context.Background(), opts); err == nil {
+			t.Errorf("非法选项 %+v 应返回错误", opts)
+		}
+	}
+}
+
+// TestSearchProgressAndCancel 检查进度报告与取消
+func TestSearchProgressAndCancel(t *testing.T) {
+	var events []ProgressEvent
+	ctx := WithProgress(context.Background(), func(ev ProgressEvent) { events = append(events, ev) })
+	if _, err := Search(ctx, SearchOptions{N: 6, Algorithm: SearchAnnealing, Seed: 1, Iterations: 500}); err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("应收到进度事件")
+	}
+	last := events[len(events)-1]
+	if last.Stage != StageSearch || last.Metric != string(SearchAnnealing) || !last.Done || last.Row != 500 || last.Rows != 500 {
+		t.Errorf("最后一个进度事件错误: %+v", last)
+	}
+
+	// 代价恰为 0 时也要出现在进度事件的 JSON 中
+	events = nil
+	zero := func(g *BooleanFunction) float64 { return float64(g.HammingWeight()) }
+	if _, err := Search(ctx, SearchOptions{N: 4, Seed: 2, Iterations: 2000, CostFunc: zero}); err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	last = events[len(events)-1]
+	if data, _ := json.Marshal(last); last.Cost == nil || *last.Cost != 0 || !strings.Contains(string(data), `"cost":0`) {
+		t.Errorf("代价为 0 的进度事件应包含 cost 字段: %s", data)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	ctx = WithProgress(cancelled, func(ev ProgressEvent) {
+		if ev.Row >= 10 {
+			cancel()
+		}
+	})
+	if _, err := Search(ctx, SearchOptions{N: 6, Algorithm: SearchGenetic, Iterations: 1000}); !errors.Is(err, context.Canceled) {
+		t.Errorf("取消后应返回 context.Canceled, 实际 %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package booleancore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 次级构造：由已有的小函数组合出更大的函数，并由输入函数的性质推出结果的理论值.
+// 涉及两组变量 (x, y) 的构造中 x 占输入的低位、y 占高位，即真值表下标为 x | y<<n，
+// 新增的单个变量总是最高位 x_n。
+//
+//   - DirectSum：h(x, y) = f(x) ⊕ g(y)，W_h(a, b) = W_f(a)·W_g(b)；
+//   - IndirectSum：Carlet 的间接和 h = f1(x) ⊕ g1(y) ⊕ (f1 ⊕ f2)(x)·(g1 ⊕ g2)(y)；
+//   - Concatenate：h(x, x_n) = (1 ⊕ x_n)f0(x) ⊕ x_n f1(x)，即真值表首尾相接；
+//   - Siegenthaler：f(x) 与 f(x ⊕ 1) ⊕ ε 的串联，弹性阶加一、非线性度翻倍；
+//   - Restrict：固定一个变量得到的 n-1 元子函数。
+//
+// 每个构造都有对应的 Predict 函数，返回结果的精确值或上下界（PredictedValue），
+// VerifyPredictions 再用 Nonlinearity、CorrelationImmunity 等实测值逐项核对。
+
+// BoundKind 表示理论值的类型.
+type BoundKind string
+
+const (
+	BoundExact BoundKind = "exact" // 实测值应等于理论值
+	BoundLower BoundKind = "lower" // 实测值应不小于理论值
+	BoundUpper BoundKind = "upper" // 实测值应不大于理论值
+)
+
+// PredictedValue 是构造结果某个属性的理论值，Property 取 PropNonlinearity、PropCorrelationImmunity、
+// PropResiliencyOrder、PropAlgebraicDegree 之一.
+type PredictedValue struct {
+	Property string    `json:"property"`
+	Value    int64     `json:"value"`
+	Bound    BoundKind `json:"bound"`
+}
+
+// PropertyCheck 是理论值与实测值的对比，Holds 表示实测值满足理论值.
+type PropertyCheck struct {
+	PredictedValue
+	Measured int64 `json:"measured"`
+	Holds    bool  `json:"holds"`
+}
+
+// DirectSum 返回 f.N()+g.N() 元函数 h(x, y) = f(x) ⊕ g(y).
+func DirectSum(f, g *BooleanFunction) (*BooleanFunction, error) {
+	if err := checkConstructedN(f.n + g.n); err != nil {
+		return nil, err
+	}
+	ft, gt := f.TruthTable(), g.TruthTable()
+	low := 1<<uint(f.n) - 1
+	return newFromPredicate(f.n+g.n, func(z int) byte {
+		return ft[z&low] ^ gt[z>>uint(f.n)]
+	}), nil
+}
+
+// PredictDirectSum 返回直和 f(x) ⊕ g(y) 的理论值.
+// 由 W_h(a, b) = W_f(a)·W_g(b)，非线性度、相关免疫阶、弹性阶与代数次数都是精确值：
+// NL_h = 2^n NL_g + 2^m NL_f - 2 NL_f NL_g，res_h = res_f + res_g + 1（-1 表示不平衡时同样成立）。
+func PredictDirectSum(f, g *BooleanFunction) []PredictedValue {
+	nlf, nlg := f.Nonlinearity(), g.Nonlinearity()
+	return []PredictedValue{
+		{PropNonlinearity, int64(1)<<uint(f.n)*nlg + int64(1)<<uint(g.n)*nlf - 2*nlf*nlg, BoundExact},
+		{PropCorrelationImmunity, int64(directSumCorrelationImmunity(f, g)), BoundExact},
+		{PropResiliencyOrder, int64(f.ResiliencyOrder() + g.ResiliencyOrder() + 1), BoundExact},
+		{PropAlgebraicDegree, int64(max(f.AlgebraicDegree(), g.AlgebraicDegree())), BoundExact},
+	}
+}
+
+// IndirectSum 返回 Carlet 的间接和 h(x, y) = f1(x) ⊕ g1(y) ⊕ (f1 ⊕ f2)(x)·(g1 ⊕ g2)(y).
+// f1、f2 为 n 元函数，g1、g2 为 m 元函数，结果有 n+m 个变量：g1(y) = g2(y) 时 h = f1 ⊕ g1，否则 h = f2 ⊕ g1。
+func IndirectSum(f1, f2, g1, g2 *BooleanFunction) (*BooleanFunction, error) {
+	if f1.n != f2.n || g1.n != g2.n {
+		return nil, fmt.Errorf("number of variables mismatch: f1, f2 have %d, %d and g1, g2 have %d, %d", f1.n, f2.n, g1.n, g2.n)
+	}
+	if err := checkConstructedN(f1.n + g1.n); err != nil {
+		return nil, err
+	}
+	f1t, f2t, g1t, g2t := f1.TruthTable(), f2.TruthTable(), g1.TruthTable(), g2.TruthTable()
+	low := 1<<uint(f1.n) - 1
+	return newFromPredicate(f1.n+g1.n, func(z int) byte {
+		x, y := z&low, z>>uint(f1.n)
+		return f1t[x] ^ g1t[y] ^ (f1t[x]^f2t[x])&(g1t[y]^g2t[y])
+	}), nil
+}
+
+// PredictIndirectSum 返回间接和的理论值（都是界）.
+// W_h(a, b) = ½W_{f1}(a)(W_{g1} + W_{g2})(b) + ½W_{f2}(a)(W_{g1} - W_{g2})(b)，因此
+// f1、f2 为 t 阶弹性、g1、g2 为 s 阶弹性时 h 为 t+s+1 阶弹性，且 max|W_h| ≤ max|W_{f_i}|·max|W_{g_j}|。
+func PredictIndirectSum(f1, f2, g1, g2 *BooleanFunction) []PredictedValue {
+	n := f1.n + g1.n
+	maxF := max(maxAbsValue(f1.walsh(), 0), maxAbsValue(f2.walsh(), 0))
+	maxG := max(maxAbsValue(g1.walsh(), 0), maxAbsValue(g2.walsh(), 0))
+	res := min(f1.ResiliencyOrder(), f2.ResiliencyOrder()) + min(g1.ResiliencyOrder(), g2.ResiliencyOrder()) + 1
+	degree := max(f1.AlgebraicDegree(), g1.AlgebraicDegree())
+	df, _ := f1.Xor(f2)
+	dg, _ := g1.Xor(g2)
+	if df.HammingWeight() != 0 && dg.HammingWeight() != 0 {
+		degree = max(degree, df.AlgebraicDegree()+dg.AlgebraicDegree())
+	}
+	return []PredictedValue{
+		{PropNonlinearity, int64(1)<<uint(n-1) - maxF*maxG/2, BoundLower},
+		{PropResiliencyOrder, int64(res), BoundLower},
+		{PropAlgebraicDegree, int64(degree), BoundUpper},
+	}
+}
+
+// Concatenate 返回 n+1 元函数 h(x, x_n) = (1 ⊕ x_n)f0(x) ⊕ x_n f1(x)，真值表为 f0 与 f1 首尾相接.
+func Concatenate(f0, f1 *BooleanFunction) (*BooleanFunction, error) {
+	if f0.n != f1.n {
+		return nil, fmt.Errorf("number of variables mismatch: %d vs %d", f0.n, f1.n)
+	}
+	if err := checkConstructedN(f0.n + 1); err != nil {
+		return nil, err
+	}
+	t0, t1 := f0.TruthTable(), f1.TruthTable()
+	return newFromPredicate(f0.n+1, func(z int) byte {
+		if z>>uint(f0.n) == 0 {
+			return t0[z]
+		}
+		return t1[z&(1<<uint(f0.n)-1)]
+	}), nil
+}
+
+// PredictConcatenate 返回串联的理论值（都是界）.
+// W_h(u, u_n) = W_{f0}(u) + (-1)^{u_n} W_{f1}(u)，所以 NL_h ≥ NL_{f0} + NL_{f1}，
+// 弹性阶不小于两者的较小值；wt(f0) = wt(f1) 时相关免疫阶也不小于两者的较小值。
+func PredictConcatenate(f0, f1 *BooleanFunction) []PredictedValue {
+	d, _ := f0.Xor(f1)
+	degree := f0.AlgebraicDegree()
+	if d.HammingWeight() != 0 {
+		degree = max(degree, d.AlgebraicDegree()+1)
+	}
+	predicted := []PredictedValue{
+		{PropNonlinearity, f0.Nonlinearity() + f1.Nonlinearity(), BoundLower},
+		{PropResiliencyOrder, int64(min(f0.ResiliencyOrder(), f1.ResiliencyOrder())), BoundLower},
+		{PropAlgebraicDegree, int64(degree), BoundUpper},
+	}
+	if f0.HammingWeight() == f1.HammingWeight() {
+		predicted = append(predicted, PredictedValue{
+			PropCorrelationImmunity, int64(min(f0.CorrelationImmunity(), f1.CorrelationImmunity())), BoundLower,
+		})
+	}
+	return predicted
+}
+
+// Siegenthaler 返回 n+1 元函数 h(x, x_n) = (1 ⊕ x_n)f(x) ⊕ x_n(f(x ⊕ 1) ⊕ ε)，其中 x ⊕ 1 为 x 的逐位取反，
+// ε ≡ t (mod 2)、t 为 f 的弹性阶（f 不平衡时 t = -1）.
+// 由 W_h(u, u_n) = W_f(u)(1 + (-1)^{u_n + ε + wt(u)})，h 是 t+1 阶弹性函数，非线性度为 2NL_f，代数次数与 f 相同（f 为常数时为 1）。
+func Siegenthaler(f *BooleanFunction) (*BooleanFunction, error) {
+	if err := checkConstructedN(f.n + 1); err != nil {
+		return nil, err
+	}
+	eps := byte(f.ResiliencyOrder()) & 1
+	tt := f.TruthTable()
+	all := 1<<uint(f.n) - 1
+	return newFromPredicate(f.n+1, func(z int) byte {
+		if z>>uint(f.n) == 0 {
+			return tt[z]
+		}
+		return tt[^z&all] ^ eps
+	}), nil
+}
+
+// PredictSiegenthaler 返回 Siegenthaler 构造的理论值（都是精确值）.
+func PredictSiegenthaler(f *BooleanFunction) []PredictedValue {
+	return []PredictedValue{
+		{PropNonlinearity, 2 * f.Nonlinearity(), BoundExact},
+		{PropResiliencyOrder, int64(f.ResiliencyOrder() + 1), BoundExact},
+		{PropAlgebraicDegree, int64(max(f.AlgebraicDegree(), 1)), BoundExact},
+	}
+}
+
+// Restrict 返回固定 x_varIndex = value 得到的 n-1 元子函数，高于 varIndex 的变量依次降一位.
+func (f *BooleanFunction) Restrict(varIndex int, value byte) (*BooleanFunction, error) {
+	if f.n < 2 {
+		return nil, errors.New("restriction requires at least 2 variables")
+	}
+	if varIndex < 0 || varIndex >= f.n {
+		return nil, fmt.Errorf("variable index %d out of range [0, %d)", varIndex, f.n)
+	}
+	if value > 1 {
+		return nil, fmt.Errorf("value must be 0 or 1, got %d", value)
+	}
+	tt := f.TruthTable()
+	low := 1<<uint(varIndex) - 1
+	return newFromPredicate(f.n-1, func(x int) byte {
+		return tt[x&low|int(value)<<uint(varIndex)|(x&^low)<<1]
+	}), nil
+}
+
+// PredictRestrict 返回子函数的理论值（都是界）.
+// W_{f_v}(u) = ½(W_f(u, 0) + (-1)^v W_f(u, 1))，所以 NL ≥ NL_f - 2^{n-2}，
+// 相关免疫阶与弹性阶至多下降 1，代数次数不增。
+func PredictRestrict(f *BooleanFunction) []PredictedValue {
+	return []PredictedValue{
+		{PropNonlinearity, f.Nonlinearity() - int64(1)<<uint(f.n-2), BoundLower},
+		{PropCorrelationImmunity, int64(f.CorrelationImmunity() - 1), BoundLower},
+		{PropResiliencyOrder, int64(max(f.ResiliencyOrder()-1, -1)), BoundLower},
+		{PropAlgebraicDegree, int64(f.AlgebraicDegree()), BoundUpper},
+	}
+}
+
+// VerifyPredictions 计算 h 的实测值并与理论值逐项核对.
+func VerifyPredictions(h *BooleanFunction, predicted []PredictedValue) ([]PropertyCheck, error) {
+	checks := make([]PropertyCheck, 0, len(predicted))
+	for _, p := range predicted {
+		var measured int64
+		switch p.Property {
+		case PropNonlinearity:
+			measured = h.Nonlinearity()
+		case PropCorrelationImmunity:
+			measured = int64(h.CorrelationImmunity())
+		case PropResiliencyOrder:
+			measured = int64(h.ResiliencyOrder())
+		case PropAlgebraicDegree:
+			measured = int64(h.AlgebraicDegree())
+		default:
+			return nil, fmt.Errorf("unsupported property for prediction: %q", p.Property)
+		}
+		holds := measured == p.Value
+		switch p.Bound {
+		case BoundLower:
+			holds = measured >= p.Value
+		case BoundUpper:
+			holds = measured <= p.Value
+		}
+		checks = append(checks, PropertyCheck{PredictedValue: p, Measured: measured, Holds: holds})
+	}
+	return checks, nil
+}
+
+// --- 私有实现 ---
+
+// checkConstructedN 检查构造结果的变量个数不超过 MaxConstructedN.
+func checkConstructedN(n int) error {
+	if n > MaxConstructedN {
+		return fmt.Errorf("result would have %d variables, at most %d are supported", n, MaxConstructedN)
+	}
+	return nil
+}
+
+// directSumCorrelationImmunity 返回直和 f(x) ⊕ g(y) 的相关免疫阶.
+// W_h(a, b) ≠ 0 当且仅当 W_f(a) ≠ 0 且 W_g(b) ≠ 0，设 d_f 为使 W_f(a) ≠ 0 的最小非零重量（即 CI_f + 1），
+// 则最小非零重量取 d_f + d_g，以及 f 不平衡时的 d_g、g 不平衡时的 d_f 中的最小者。
+func directSumCorrelationImmunity(f, g *BooleanFunction) int {
+	n := f.n + g.n
+	// CI 等于变量个数表示除 W(0) 外谱全为 0，此时不存在非零重量的谱值
+	df, dg := f.CorrelationImmunity()+1, g.CorrelationImmunity()+1
+	if df > f.n {
+		df = n + 1
+	}
+	if dg > g.n {
+		dg = n + 1
+	}
+	d := df + dg
+	if !f.IsBalanced() {
+		d = min(d, dg)
+	}
+	if !g.IsBalanced() {
+		d = min(d, df)
+	}
+	return min(d-1, n)
+}
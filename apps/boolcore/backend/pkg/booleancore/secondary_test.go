@@ -0,0 +1,124 @@
+package booleancore
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkPredictions 断言 h 满足全部理论值
+func checkPredictions(t *testing.T, name string, h *BooleanFunction, predicted []PredictedValue) {
+	t.Helper()
+	checks, err := VerifyPredictions(h, predicted)
+	if err != nil {
+		t.Fatalf("%s: VerifyPredictions error: %v", name, err)
+	}
+	for _, c := range checks {
+		if !c.Holds {
+			t.Errorf("%s: %s 理论值 %d（%s）, 实测 %d", name, c.Property, c.Value, c.Bound, c.Measured)
+		}
+	}
+}
+
+// TestSecondaryConstructionPredictions 在随机输入与弹性输入上核对各构造的理论值
+func TestSecondaryConstructionPredictions(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	// 1 阶弹性的 4 元函数与 2 阶弹性的 5 元函数，由 Siegenthaler 构造从平衡函数 x0 ⊕ x1x2 得到
+	base, _ := NewFromANF(3, "x0 + x1*x2")
+	res1, _ := Siegenthaler(base)
+	res2, _ := Siegenthaler(res1)
+	inputs := []*BooleanFunction{base, res1, res2}
+	for seed := int64(0); seed < 8; seed++ {
+		inputs = append(inputs, randomFunction(t, 2+int(seed)%4, seed))
+	}
+	for _, f := range inputs {
+		g := inputs[rng.Intn(len(inputs))]
+		h, err := DirectSum(f, g)
+		if err != nil {
+			t.Fatalf("DirectSum error: %v", err)
+		}
+		checkPredictions(t, "DirectSum", h, PredictDirectSum(f, g))
+
+		s, err := Siegenthaler(f)
+		if err != nil {
+			t.Fatalf("Siegenthaler error: %v", err)
+		}
+		checkPredictions(t, "Siegenthaler", s, PredictSiegenthaler(f))
+
+		f1 := randomFunction(t, f.N(), rng.Int63())
+		c, err := Concatenate(f, f1)
+		if err != nil {
+			t.Fatalf("Concatenate error: %v", err)
+		}
+		checkPredictions(t, "Concatenate", c, PredictConcatenate(f, f1))
+
+		g2 := randomFunction(t, g.N(), rng.Int63())
+		ind, err := IndirectSum(f, f1, g, g2)
+		if err != nil {
+			t.Fatalf("IndirectSum error: %v", err)
+		}
+		checkPredictions(t, "IndirectSum", ind, PredictIndirectSum(f, f1, g, g2))
+
+		for i := 0; i < f.N() && f.N() >= 2; i++ {
+			r, err := f.Restrict(i, byte(rng.Intn(2)))
+			if err != nil {
+				t.Fatalf("Restrict error: %v", err)
+			}
+			checkPredictions(t, "Restrict", r, PredictRestrict(f))
+		}
+	}
+	if res2.N() != 5 || res2.ResiliencyOrder() != 2 || res2.AlgebraicDegree() != 2 || res2.Nonlinearity() != 8 {
+		t.Errorf("两次 Siegenthaler 构造后应得到 5 元 2 阶弹性、2 次、非线性度 8 的函数, 实际 n=%d res=%d deg=%d NL=%d",
+			res2.N(), res2.ResiliencyOrder(), res2.AlgebraicDegree(), res2.Nonlinearity())
+	}
+}
+
+// TestSecondaryConstructionStructure 检查构造与限制的互逆关系及参数检查
+func TestSecondaryConstructionStructure(t *testing.T) {
+	f0 := randomFunction(t, 5, 1)
+	f1 := randomFunction(t, 5, 2)
+	h, _ := Concatenate(f0, f1)
+	for v, want := range []*BooleanFunction{f0, f1} {
+		r, err := h.Restrict(5, byte(v))
+		if err != nil || !r.Equal(want) {
+			t.Errorf("Concatenate 后固定 x5 = %d 应还原 f%d", v, v)
+		}
+	}
+
+	// 两个 bent 函数的直和仍是 bent 函数
+	a, _ := NewFromANF(4, "x0*x1 + x2*x3")
+	b, _ := NewFromANF(2, "x0*x1 + x1")
+	ab, _ := DirectSum(a, b)
+	if ab.N() != 6 || !ab.IsBent() {
+		t.Error("bent 函数的直和应为 bent 函数")
+	}
+	r, _ := ab.Restrict(0, 1)
+	if want, _ := NewFromANF(5, "x0 + x1*x2 + x3*x4 + x4"); !r.Equal(want) {
+		t.Errorf("固定 x0 = 1 的子函数错误: %s", r.AlgebraicNormalForm())
+	}
+
+	// g1 = g2 时间接和退化为 f1 ⊕ g1
+	ind, _ := IndirectSum(f0, f1, b, b)
+	direct, _ := DirectSum(f0, b)
+	if !ind.Equal(direct) {
+		t.Error("g1 = g2 时间接和应等于 f1 与 g1 的直和")
+	}
+
+	if _, err := Concatenate(f0, a); err == nil {
+		t.Error("变量个数不同应返回错误")
+	}
+	if _, err := IndirectSum(f0, a, b, b); err == nil {
+		t.Error("f1、f2 变量个数不同应返回错误")
+	}
+	if _, err := DirectSum(randomFunction(t, 12, 3), randomFunction(t, 13, 4)); err == nil {
+		t.Error("结果超出 MaxConstructedN 应返回错误")
+	}
+	if _, err := f0.Restrict(5, 0); err == nil {
+		t.Error("变量下标越界应返回错误")
+	}
+	if _, err := f0.Restrict(0, 2); err == nil {
+		t.Error("取值不是 0 或 1 应返回错误")
+	}
+	if _, err := VerifyPredictions(f0, []PredictedValue{{Property: PropIsBent}}); err == nil {
+		t.Error("不支持的属性应返回错误")
+	}
+}
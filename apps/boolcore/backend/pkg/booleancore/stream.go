@@ -0,0 +1,328 @@
+package booleancore
+
+import (
+	"context"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// 流式（分块）谱计算：n = 28~32 时完整的 []int64 Walsh 谱需要 2~32 GiB，
+// 这里的方法在给定内存预算内分块计算，只保留统计结果，不物化整个谱。
+//
+// 把输入与频率拆成高低两部分 x = (xh, xl)、a = (ah, al)，低位部分共 m 位，2^m 个值放得进预算：
+//
+//	W(ah, ·) = WHT_m(g_ah),   g_ah(xl) = Σ_xh (-1)^{ah·xh} s(xh, xl),   s(x) = (-1)^{f(x)}
+//
+// 每个 ah 一趟：先从打包真值表折叠出 g_ah，再做 2^m 点的 FWHT，得到谱的一个块。
+// 自相关同理，记 T_u 为第 u 块的 2^m 点 WHT，则
+//
+//	r(bh, ·) = 2^{-m} · WHT_m( Σ_u T_u · T_{u⊕bh} )
+//
+// 两种计算的总代价都是 O(2^{n-m} · 2^n · m)，内存预算越大趟数越少。
+// 各趟之间互不依赖，由多个 worker 并行执行。
+
+// DefaultStreamMemoryBudget 是 StreamOptions.MemoryBudget 为 0 时使用的内存预算（256 MiB）.
+const DefaultStreamMemoryBudget = 256 << 20
+
+// StreamOptions 配置流式谱计算.
+type StreamOptions struct {
+	// MemoryBudget 是工作缓冲区的总字节数上限（不含函数自身的打包真值表，n=32 时为 512 MiB）.
+	// 0 表示使用 DefaultStreamMemoryBudget.
+	MemoryBudget int64
+	// Workers 是并行的 worker 数，0 表示 runtime.GOMAXPROCS(0).
+	Workers int
+}
+
+// NonlinearityStream 在内存预算内计算非线性度，结果与 Nonlinearity 相同.
+func (f *BooleanFunction) NonlinearityStream(ctx context.Context, opts StreamOptions) (int64, error) {
+	var mu sync.Mutex
+	var maxAbs int64
+	err := f.streamWalsh(ctx, opts, func(_ int, block []int64) {
+		local := maxAbsValue(block, 0)
+		mu.Lock()
+		if local > maxAbs {
+			maxAbs = local
+		}
+		mu.Unlock()
+	})
+	if err != nil {
+		return -1, err
+	}
+	return int64(1)<<(f.n-1) - maxAbs/2, nil
+}
+
+// AbsoluteWalshSpectrumStream 在内存预算内计算绝对 Walsh 谱的频率分布，结果与 AbsoluteWalshSpectrum 相同.
+func (f *BooleanFunction) AbsoluteWalshSpectrumStream(ctx context.Context, opts StreamOptions) (map[int64]int, error) {
+	var mu sync.Mutex
+	distribution := make(map[int64]int)
+	err := f.streamWalsh(ctx, opts, func(_ int, block []int64) {
+		local := absDistribution(block)
+		mu.Lock()
+		for v, c := range local {
+			distribution[v] += c
+		}
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distribution, nil
+}
+
+// AbsoluteAutocorrelationStream 在内存预算内计算绝对自相关谱的频率分布，结果与 AbsoluteAutocorrelation 相同.
+func (f *BooleanFunction) AbsoluteAutocorrelationStream(ctx context.Context, opts StreamOptions) (map[int64]int, error) {
+	var mu sync.Mutex
+	distribution := make(map[int64]int)
+	err := f.streamAutocorrelation(ctx, opts, func(_ int, block []int64) {
+		local := absDistribution(block)
+		mu.Lock()
+		for v, c := range local {
+			distribution[v] += c
+		}
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distribution, nil
+}
+
+// AbsoluteIndicatorStream 在内存预算内计算绝对指标，结果与 AbsoluteIndicator 相同.
+func (f *BooleanFunction) AbsoluteIndicatorStream(ctx context.Context, opts StreamOptions) (int64, error) {
+	var mu sync.Mutex
+	var maxAbs int64
+	err := f.streamAutocorrelation(ctx, opts, func(top int, block []int64) {
+		skip := 0
+		if top == 0 {
+			skip = 1 // 跳过 a=0 的位置
+		}
+		local := maxAbsValue(block, skip)
+		mu.Lock()
+		if local > maxAbs {
+			maxAbs = local
+		}
+		mu.Unlock()
+	})
+	if err != nil {
+		return -1, err
+	}
+	return maxAbs, nil
+}
+
+// --- 私有实现
+
+// streamPlan 根据内存预算确定低位部分的位数 m 与 worker 数.
+// buffers 是每个 worker 需要的 2^m 个 int64 缓冲区个数.
+func (f *BooleanFunction) streamPlan(opts StreamOptions, buffers int) (m, workers int) {
+	budget := opts.MemoryBudget
+	if budget <= 0 {
+		budget = DefaultStreamMemoryBudget
+	}
+	workers = opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// 至少取 min(n, 6) 位，使每一块都由整字组成；预算不足时也不低于此值
+	minM := min(f.n, 6)
+	m = f.n
+	for m > minM && int64(workers)*int64(buffers)*8<<uint(m) > budget {
+		m--
+	}
+	for workers > 1 && int64(workers)*int64(buffers)*8<<uint(m) > budget {
+		workers--
+	}
+	if passes := 1 << uint(f.n-m); workers > passes {
+		workers = passes
+	}
+	return m, workers
+}
+
+// runPasses 将 [0, passes) 分配给 workers 个 worker，每个 worker 调用 newPass 取得自己的处理函数.
+func runPasses(ctx context.Context, passes, workers int, newPass func() func(top int) error) error {
+	next := make(chan int)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pass := newPass()
+			for top := range next {
+				if err := pass(top); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for top := 0; top < passes; top++ {
+		select {
+		case next <- top:
+		case err = <-errs:
+			break feed
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return err
+}
+
+// streamWalsh 逐块计算 Walsh 谱，对每个高位频率 ah 调用 visit(ah, W(ah, ·)).
+// visit 可能被多个 worker 并发调用，block 在 visit 返回后会被复用.
+func (f *BooleanFunction) streamWalsh(ctx context.Context, opts StreamOptions, visit func(top int, block []int64)) error {
+	m, workers := f.streamPlan(opts, 1)
+	blockLen := 1 << uint(m)
+	numBlocks := 1 << uint(f.n-m)
+
+	return runPasses(ctx, numBlocks, workers, func() func(int) error {
+		g := make([]int64, blockLen)
+		return func(ah int) error {
+			// g(xl) = Σ_xh σ(xh) s(xh, xl)，σ(xh) = (-1)^{ah·xh}；先累加 σ·f 再换算成 ±1
+			for i := range g {
+				g[i] = 0
+			}
+			var sigmaSum int64
+			for xh := 0; xh < numBlocks; xh++ {
+				if xh&1023 == 0 {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+				}
+				sign := int64(1 - 2*(bits.OnesCount(uint(ah&xh))&1))
+				sigmaSum += sign
+				f.foldBlockOnes(xh, m, g, sign)
+			}
+			for i := range g {
+				g[i] = sigmaSum - 2*g[i]
+			}
+			if err := fwhtInplaceCtx(ctx, g); err != nil {
+				return err
+			}
+			visit(ah, g)
+			return nil
+		}
+	})
+}
+
+// foldBlockOnes 对第 xh 块中取值为 1 的每个位置 xl 执行 acc[xl] += sign.
+func (f *BooleanFunction) foldBlockOnes(xh, m int, acc []int64, sign int64) {
+	if m < 6 { // 只有 n < 6 时出现，此时全函数就是唯一的块
+		v := f.packedTruthTable[0]
+		for v != 0 {
+			acc[bits.TrailingZeros64(v)] += sign
+			v &= v - 1
+		}
+		return
+	}
+	wordsPerBlock := 1 << uint(m-6)
+	words := f.packedTruthTable[xh*wordsPerBlock : (xh+1)*wordsPerBlock]
+	for w, v := range words {
+		base := w << 6
+		for v != 0 {
+			acc[base+bits.TrailingZeros64(v)] += sign
+			v &= v - 1
+		}
+	}
+}
+
+// blockWalsh 将第 u 块的 2^m 点 WHT 写入 out.
+func (f *BooleanFunction) blockWalsh(u, m int, out []int64) {
+	for i := range out {
+		out[i] = 0
+	}
+	f.foldBlockOnes(u, m, out, 1)
+	for i := range out {
+		out[i] = 1 - 2*out[i]
+	}
+	fwhtInplace(out)
+}
+
+// streamAutocorrelation 逐块计算自相关谱，对每个高位平移 bh 调用 visit(bh, r(bh, ·)).
+// visit 可能被多个 worker 并发调用，block 在 visit 返回后会被复用.
+func (f *BooleanFunction) streamAutocorrelation(ctx context.Context, opts StreamOptions, visit func(top int, block []int64)) error {
+	m, workers := f.streamPlan(opts, 3)
+	blockLen := 1 << uint(m)
+	numBlocks := 1 << uint(f.n-m)
+
+	return runPasses(ctx, numBlocks, workers, func() func(int) error {
+		acc := make([]int64, blockLen)
+		tu := make([]int64, blockLen)
+		tv := make([]int64, blockLen)
+		return func(bh int) error {
+			for i := range acc {
+				acc[i] = 0
+			}
+			// u 与 u⊕bh 的乘积对称，bh ≠ 0 时每对只算一次再乘 2
+			for u := 0; u < numBlocks; u++ {
+				v := u ^ bh
+				if v < u {
+					continue
+				}
+				if u&255 == 0 {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+				}
+				f.blockWalsh(u, m, tu)
+				if v == u {
+					for i, t := range tu {
+						acc[i] += t * t
+					}
+					continue
+				}
+				f.blockWalsh(v, m, tv)
+				for i := range tu {
+					acc[i] += 2 * tu[i] * tv[i]
+				}
+			}
+			if err := fwhtInplaceCtx(ctx, acc); err != nil {
+				return err
+			}
+			for i := range acc {
+				acc[i] >>= uint(m) // 每一项都是 2^m 的整数倍
+			}
+			visit(bh, acc)
+			return nil
+		}
+	})
+}
+
+// maxAbsValue 返回 values[skip:] 中的最大绝对值.
+func maxAbsValue(values []int64, skip int) int64 {
+	var maxAbs int64
+	for _, v := range values[skip:] {
+		if v < 0 {
+			v = -v
+		}
+		if v > maxAbs {
+			maxAbs = v
+		}
+	}
+	return maxAbs
+}
+
+// absDistribution 统计绝对值的频率分布.
+func absDistribution(values []int64) map[int64]int {
+	distribution := make(map[int64]int)
+	for _, v := range values {
+		if v < 0 {
+			v = -v
+		}
+		distribution[v]++
+	}
+	return distribution
+}
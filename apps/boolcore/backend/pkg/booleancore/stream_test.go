@@ -0,0 +1,67 @@
+package booleancore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestStreamMatchesFullSpectrum 验证不同内存预算下流式结果与完整谱的结果一致
+func TestStreamMatchesFullSpectrum(t *testing.T) {
+	budgets := []StreamOptions{
+		{},                                  // 默认预算，一趟完成
+		{MemoryBudget: 1, Workers: 1},       // 预算不足时退化到最小块 2^6
+		{MemoryBudget: 8 << 10, Workers: 3}, // 多趟多 worker
+	}
+	for _, n := range []int{3, 6, 9, 13} {
+		bf := randomFunction(t, n, int64(200+n))
+		for _, opts := range budgets {
+			ctx := context.Background()
+
+			nl, err := bf.NonlinearityStream(ctx, opts)
+			if err != nil || nl != bf.Nonlinearity() {
+				t.Errorf("n=%d %+v: NonlinearityStream = %d, %v; 期望 %d", n, opts, nl, err, bf.Nonlinearity())
+			}
+			walsh, err := bf.AbsoluteWalshSpectrumStream(ctx, opts)
+			if err != nil || !reflect.DeepEqual(walsh, bf.AbsoluteWalshSpectrum()) {
+				t.Errorf("n=%d %+v: AbsoluteWalshSpectrumStream 与完整谱不一致 (%v)", n, opts, err)
+			}
+			ac, err := bf.AbsoluteAutocorrelationStream(ctx, opts)
+			if err != nil || !reflect.DeepEqual(ac, bf.AbsoluteAutocorrelation()) {
+				t.Errorf("n=%d %+v: AbsoluteAutocorrelationStream 与完整谱不一致 (%v)", n, opts, err)
+			}
+			delta, err := bf.AbsoluteIndicatorStream(ctx, opts)
+			if err != nil || delta != bf.AbsoluteIndicator() {
+				t.Errorf("n=%d %+v: AbsoluteIndicatorStream = %d, %v; 期望 %d", n, opts, delta, err, bf.AbsoluteIndicator())
+			}
+		}
+	}
+}
+
+// TestStreamPlanRespectsBudget 验证块大小与 worker 数满足内存预算
+func TestStreamPlanRespectsBudget(t *testing.T) {
+	bf := &BooleanFunction{n: 30}
+	opts := StreamOptions{MemoryBudget: 64 << 20, Workers: 8}
+	m, workers := bf.streamPlan(opts, 3)
+	if used := int64(workers) * 3 * 8 << uint(m); used > opts.MemoryBudget {
+		t.Errorf("m=%d workers=%d 使用 %d 字节，超过预算 %d", m, workers, used, opts.MemoryBudget)
+	}
+	if m != 18 || workers != 8 {
+		t.Errorf("期望 m=18 workers=8, 实际 m=%d workers=%d", m, workers)
+	}
+}
+
+// TestStreamCancel 验证流式计算可以被取消
+func TestStreamCancel(t *testing.T) {
+	bf := randomFunction(t, 16, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := StreamOptions{MemoryBudget: 4 << 10}
+	if _, err := bf.AbsoluteIndicatorStream(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("AbsoluteIndicatorStream 期望 context.Canceled, 实际 %v", err)
+	}
+	if _, err := bf.NonlinearityStream(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("NonlinearityStream 期望 context.Canceled, 实际 %v", err)
+	}
+}
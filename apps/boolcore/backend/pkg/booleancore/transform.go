@@ -1,18 +1,41 @@
 package booleancore
 
 import (
-	"math"
+	"context"
+	"fmt"
+	"math/bits"
 	"runtime"
 	"sync"
 )
 
 // WalshHadamardTransform 计算函数的沃尔什-哈达玛变换谱.
-// 结果会被缓存. 优化返回类型为 int64.
+// 结果会被缓存，返回的是缓存的副本. 优化返回类型为 int64.
 func (f *BooleanFunction) WalshHadamardTransform() []int64 {
-	if f.walshSpectrum != nil {
-		return f.walshSpectrum // 使用缓存
+	s, _ := f.WalshHadamardTransformCtx(context.Background()) // Background 不会被取消
+	return s
+}
+
+// WalshHadamardTransformCtx 是可取消的 WHT，每一级蝶形运算前检查 ctx.
+// 仅在完整计算成功后写入缓存，被取消时返回 ctx.Err()。
+func (f *BooleanFunction) WalshHadamardTransformCtx(ctx context.Context) ([]int64, error) {
+	s, err := f.walshCtx(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return append([]int64(nil), s...), nil
+}
+
+// walsh 返回缓存的 Walsh 谱，包内共享只读，不得修改.
+func (f *BooleanFunction) walsh() []int64 {
+	s, _ := f.walshCtx(context.Background())
+	return s
+}
 
+func (f *BooleanFunction) walshCtx(ctx context.Context) ([]int64, error) {
+	return f.walshSpectrum.get(ctx, f.computeWalsh)
+}
+
+func (f *BooleanFunction) computeWalsh(ctx context.Context) ([]int64, error) {
 	length := 1 << f.n
 	// s[x] = (-1)^{f(x)}
 	s := make([]int64, length)
@@ -26,9 +49,10 @@ func (f *BooleanFunction) WalshHadamardTransform() []int64 {
 		}
 	}
 
-	fwhtInplace(s)      // 执行变换
-	f.walshSpectrum = s // 缓存结果
-	return f.walshSpectrum
+	if err := fwhtInplaceCtx(ctx, s); err != nil { // 执行变换
+		return nil, err
+	}
+	return s, nil
 }
 
 // WalshHadamardTransformParallel FWHT 的并行版本.
@@ -48,13 +72,8 @@ func (f *BooleanFunction) WalshHadamardTransformParallel(workers int) []int64 {
 	return s
 }
 
-// Autocorrelation 计算函数的自相关谱.
-// 采用 float64 中间计算保证精度和范围，并增加缓存
-func (f *BooleanFunction) Autocorrelation() []int64 {
-	if f.autocorrelationSpectrum != nil {
-		return f.autocorrelationSpectrum
-	}
-
+// WalshHadamardTransformParallelCtx 是 WalshHadamardTransformParallel 的可取消版本.
+func (f *BooleanFunction) WalshHadamardTransformParallelCtx(ctx context.Context, workers int) ([]int64, error) {
 	length := 1 << f.n
 	s := make([]int64, length)
 	for i := 0; i < length; i++ {
@@ -65,27 +84,137 @@ func (f *BooleanFunction) Autocorrelation() []int64 {
 			s[i] = -1
 		}
 	}
+	if err := parallelFWHTInplaceCtx(ctx, s, workers); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
-	fwhtInplace(s)
+// Autocorrelation 计算函数的自相关谱.
+// 结果是精确的整数（见 computeAutocorrelation），并增加缓存，返回的是缓存的副本
+func (f *BooleanFunction) Autocorrelation() []int64 {
+	ac, _ := f.AutocorrelationCtx(context.Background())
+	return ac
+}
 
-	// 平方操作，转换为 float64 以防止溢出并保证精度
-	sSquaredFloat := make([]float64, length)
-	for i := 0; i < length; i++ {
-		sSquaredFloat[i] = float64(s[i]) * float64(s[i])
+// AutocorrelationCtx 是 Autocorrelation 的可取消版本，FWHT 的每一级之前都检查 ctx.
+func (f *BooleanFunction) AutocorrelationCtx(ctx context.Context) ([]int64, error) {
+	ac, err := f.autocorrelationCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int64(nil), ac...), nil
+}
+
+// autocorrelation 返回缓存的自相关谱，包内共享只读，不得修改.
+func (f *BooleanFunction) autocorrelation() []int64 {
+	ac, _ := f.autocorrelationCtx(context.Background())
+	return ac
+}
+
+func (f *BooleanFunction) autocorrelationCtx(ctx context.Context) ([]int64, error) {
+	return f.autocorrelationSpectrum.get(ctx, f.computeAutocorrelation)
+}
+
+// exactAutocorrelationMaxN 是 int64 路径保证精确的最大 n.
+//
+// 自相关谱 r = 2^{-n} · WHT(W²)。由 Parseval 等式 Σ W(a)² = 2^{2n}，
+// 对 W² 做 FWHT 时每个中间值都是若干 ±W(a)² 之和，绝对值不超过 2^{2n}，
+// 因此 n <= 31 时全部中间结果都落在 int64 内，运算是精确的；
+// 更大的 n 改用 128 位整数的 FWHT。
+// 旧的 float64 实现在 2^{2n} 超过 2^53（n >= 27）后会丢失精度。
+const exactAutocorrelationMaxN = 31
+
+// computeAutocorrelation 由缓存的 Walsh 谱精确计算自相关谱，复杂度 O(n·2^n).
+func (f *BooleanFunction) computeAutocorrelation(ctx context.Context) ([]int64, error) {
+	wht, err := f.walshCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if f.n > exactAutocorrelationMaxN {
+		return autocorrelationInt128(ctx, wht, f.n)
+	}
+
+	res := make([]int64, len(wht))
+	for i, w := range wht {
+		res[i] = w * w
+	}
+	if err := fwhtInplaceCtx(ctx, res); err != nil {
+		return nil, err
+	}
+	// 每一项都是 2^n 的整数倍，算术右移即精确除法
+	for i := range res {
+		res[i] >>= uint(f.n)
+	}
+	return res, nil
+}
+
+// autocorrelationInt128 是 n > 31 时的精确路径：以 (hi, lo) 表示的 128 位补码整数做 FWHT，
+// 中间值不超过 2^{2n}，n <= 62 时不会溢出；最终结果 |r(a)| <= 2^n 可放回 int64.
+func autocorrelationInt128(ctx context.Context, wht []int64, n int) ([]int64, error) {
+	length := len(wht)
+	hi := make([]uint64, length)
+	lo := make([]uint64, length)
+	for i, w := range wht {
+		if w < 0 {
+			w = -w
+		}
+		hi[i], lo[i] = bits.Mul64(uint64(w), uint64(w))
 	}
 
-	// 在 float64 上再次 FWHT (相当于逆变换)
-	fwhtFloatInplace(sSquaredFloat)
+	for step := 1; step < length; step <<= 1 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i := 0; i < length; i += step << 1 {
+			for j := i; j < i+step; j++ {
+				k := j + step
+				sumLo, carry := bits.Add64(lo[j], lo[k], 0)
+				sumHi, _ := bits.Add64(hi[j], hi[k], carry)
+				diffLo, borrow := bits.Sub64(lo[j], lo[k], 0)
+				diffHi, _ := bits.Sub64(hi[j], hi[k], borrow)
+				hi[j], lo[j] = sumHi, sumLo
+				hi[k], lo[k] = diffHi, diffLo
+			}
+		}
+	}
 
-	// 归一化并转换回 int64
 	res := make([]int64, length)
-	nFloat := float64(length)
-	for i := 0; i < length; i++ {
-		res[i] = int64(math.Round(sSquaredFloat[i] / nFloat))
+	shift := uint(n)
+	for i := range res {
+		// 128 位算术右移 n 位后取低 64 位
+		res[i] = int64(lo[i]>>shift | hi[i]<<(64-shift))
 	}
+	return res, nil
+}
 
-	f.autocorrelationSpectrum = res // 缓存结果
-	return res
+// AutocorrelationAt 直接计算单点自相关值 r(a) = Σ_x (-1)^{f(x)⊕f(x⊕a)}，
+// 在打包的字上逐字异或计数，复杂度 O(2^n/64)，不需要也不会填充整个谱的缓存.
+func (f *BooleanFunction) AutocorrelationAt(a int) (int64, error) {
+	length := 1 << f.n
+	if a < 0 || a >= length {
+		return 0, fmt.Errorf("shift %d out of range (must be in [0, 2^%d))", a, f.n)
+	}
+	words := f.packedTruthTable
+	wordShift := a >> 6
+	inWord := a & 63
+	diff := 0
+	for w, v := range words {
+		diff += bits.OnesCount64(v ^ permuteWordXor(words[w^wordShift], inWord))
+	}
+	return int64(length - 2*diff), nil
+}
+
+// permuteWordXor 返回字 v 在下标变换 j -> j⊕mask（mask < 64）下的重排.
+func permuteWordXor(v uint64, mask int) uint64 {
+	for i := 0; i < 6; i++ {
+		if (mask>>i)&1 == 1 {
+			shift := uint(1) << uint(i)
+			m := variableMasks[i]
+			v = (v&m)>>shift | (v&^m)<<shift
+		}
+	}
+	return v
 }
 
 // --- 私有实现 ( int64 改为 int) ---
@@ -104,10 +233,13 @@ func fwhtInplace(data []int64) {
 	}
 }
 
-// 浮点数版本用于计算可能超过2的64位整数的函数
-func fwhtFloatInplace(data []float64) {
+// fwhtInplaceCtx 与 fwhtInplace 相同，但在每一级蝶形运算之前检查 ctx 是否已取消.
+func fwhtInplaceCtx(ctx context.Context, data []int64) error {
 	n := len(data)
 	for step := 1; step < n; step <<= 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for i := 0; i < n; i += step << 1 {
 			for j := 0; j < step; j++ {
 				a := data[i+j]
@@ -117,19 +249,27 @@ func fwhtFloatInplace(data []float64) {
 			}
 		}
 	}
+	return nil
 }
 
 func parallelFWHTInplace(data []int64, workers int) {
+	_ = parallelFWHTInplaceCtx(context.Background(), data, workers) // Background 不会被取消
+}
+
+// parallelFWHTInplaceCtx 在每一级并行蝶形运算之前检查 ctx.
+func parallelFWHTInplaceCtx(ctx context.Context, data []int64, workers int) error {
 	n := len(data)
 	if workers <= 1 {
-		fwhtInplace(data)
-		return
+		return fwhtInplaceCtx(ctx, data)
 	}
 	maxWorkers := runtime.GOMAXPROCS(0)
 	if workers > maxWorkers {
 		workers = maxWorkers
 	}
 	for step := 1; step < n; step <<= 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		blockSize := step << 1
 		numBlocks := n / blockSize
 		wg := sync.WaitGroup{}
@@ -150,6 +290,7 @@ func parallelFWHTInplace(data []int64, workers int) {
 		}
 		wg.Wait()
 	}
+	return nil
 }
 
 // Fast Möbius Transform 计算 ANF、逆变换
@@ -0,0 +1,312 @@
+package booleancore
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// VectorialBooleanFunction 代表一个 n×m 的向量布尔函数（S 盒）F: F2^n -> F2^m.
+// 查找表的索引规则与 BooleanFunction 的真值表一致（x0 为最低位），
+// 输出 F(x) 的第 i 位对应第 i 个坐标函数 f_i。
+// 坐标函数沿用 BooleanFunction 的位打包存储，分量函数 v·F 直接在打包字上异或得到。
+type VectorialBooleanFunction struct {
+	n           int
+	m           int
+	lut         []int              // 查找表, lut[x] = F(x)
+	coordinates []*BooleanFunction // 坐标函数 f_0 ... f_{m-1}
+}
+
+// NewVectorialFromLUT 通过查找表创建一个 n×m 的向量布尔函数.
+// 查找表长度必须为 2 的幂，每个值必须落在 [0, 2^m) 内。输入切片会被复制。
+func NewVectorialFromLUT(lut []int, m int) (*VectorialBooleanFunction, error) {
+	length := len(lut)
+	if length < 2 || length&(length-1) != 0 {
+		return nil, errors.New("lookup table length must be a power of 2 and at least 2")
+	}
+	if m <= 0 || m > 30 {
+		return nil, fmt.Errorf("m must be between 1 and 30, got %d", m)
+	}
+	for x, y := range lut {
+		if y < 0 || y >= 1<<m {
+			return nil, fmt.Errorf("lookup table value %d at index %d out of range for m=%d", y, x, m)
+		}
+	}
+
+	n := bits.TrailingZeros(uint(length))
+	words := (length + 63) / 64
+	coordinates := make([]*BooleanFunction, m)
+	for i := 0; i < m; i++ {
+		coordinates[i] = &BooleanFunction{n: n, packedTruthTable: make([]uint64, words)}
+	}
+	for x, y := range lut {
+		for i := 0; i < m; i++ {
+			if (y>>i)&1 == 1 {
+				coordinates[i].packedTruthTable[x>>6] |= 1 << uint(x&63)
+			}
+		}
+	}
+
+	return &VectorialBooleanFunction{
+		n:           n,
+		m:           m,
+		lut:         append([]int(nil), lut...),
+		coordinates: coordinates,
+	}, nil
+}
+
+// NewVectorialFromCoordinates 通过 m 个坐标函数创建向量布尔函数，coords[i] 对应输出的第 i 位.
+// 所有坐标函数的变量个数必须相同。
+func NewVectorialFromCoordinates(coords []*BooleanFunction) (*VectorialBooleanFunction, error) {
+	if len(coords) == 0 {
+		return nil, errors.New("at least one coordinate function is required")
+	}
+	if len(coords) > 30 {
+		return nil, fmt.Errorf("at most 30 coordinate functions are supported, got %d", len(coords))
+	}
+	for i, c := range coords {
+		if c == nil {
+			return nil, fmt.Errorf("coordinate function %d is nil", i)
+		}
+	}
+	n := coords[0].N()
+	for i, c := range coords {
+		if c.N() != n {
+			return nil, fmt.Errorf("coordinate function %d has %d variables, expected %d", i, c.N(), n)
+		}
+	}
+
+	length := 1 << n
+	lut := make([]int, length)
+	for i, c := range coords {
+		for x := 0; x < length; x++ {
+			if (c.packedTruthTable[x>>6]>>uint(x&63))&1 == 1 {
+				lut[x] |= 1 << i
+			}
+		}
+	}
+	return NewVectorialFromLUT(lut, len(coords))
+}
+
+// --- 基础方法 ---
+
+// N 返回输入位数 n.
+func (vf *VectorialBooleanFunction) N() int { return vf.n }
+
+// M 返回输出位数 m.
+func (vf *VectorialBooleanFunction) M() int { return vf.m }
+
+// LookupTable 返回查找表的副本.
+func (vf *VectorialBooleanFunction) LookupTable() []int {
+	return append([]int(nil), vf.lut...)
+}
+
+// Coordinate 返回第 i 个坐标函数 f_i.
+func (vf *VectorialBooleanFunction) Coordinate(i int) (*BooleanFunction, error) {
+	if i < 0 || i >= vf.m {
+		return nil, fmt.Errorf("coordinate index %d out of range (m=%d)", i, vf.m)
+	}
+	return vf.coordinates[i], nil
+}
+
+// Component 返回分量函数 v·F = ⊕_{i: v_i=1} f_i，v 必须非零.
+func (vf *VectorialBooleanFunction) Component(v int) (*BooleanFunction, error) {
+	if v <= 0 || v >= 1<<vf.m {
+		return nil, fmt.Errorf("component mask %d out of range (must be in [1, 2^%d))", v, vf.m)
+	}
+	return vf.component(v), nil
+}
+
+// IsPermutation 判断 n = m 时 F 是否为置换.
+func (vf *VectorialBooleanFunction) IsPermutation() bool {
+	if vf.n != vf.m {
+		return false
+	}
+	seen := make([]bool, 1<<vf.m)
+	for _, y := range vf.lut {
+		if seen[y] {
+			return false
+		}
+		seen[y] = true
+	}
+	return true
+}
+
+// --- 差分性质 ---
+
+// DDT 计算差分分布表 DDT[a][b] = #{x | F(x) ⊕ F(x⊕a) = b}.
+func (vf *VectorialBooleanFunction) DDT() [][]int {
+	length := 1 << vf.n
+	ddt := make([][]int, length)
+	for a := 0; a < length; a++ {
+		row := make([]int, 1<<vf.m)
+		for x := 0; x < length; x++ {
+			row[vf.lut[x]^vf.lut[x^a]]++
+		}
+		ddt[a] = row
+	}
+	return ddt
+}
+
+// DifferentialUniformity 计算差分均匀度 δ_F = max_{a≠0, b} DDT[a][b].
+// 注意与单输出函数的 BooleanFunction.DifferentialUniformity 不同，这里是 S 盒意义下的定义。
+func (vf *VectorialBooleanFunction) DifferentialUniformity() int {
+	length := 1 << vf.n
+	counts := make([]int, 1<<vf.m)
+	maxCount := 0
+	for a := 1; a < length; a++ {
+		for i := range counts {
+			counts[i] = 0
+		}
+		for x := 0; x < length; x++ {
+			b := vf.lut[x] ^ vf.lut[x^a]
+			counts[b]++
+			if counts[b] > maxCount {
+				maxCount = counts[b]
+			}
+		}
+	}
+	return maxCount
+}
+
+// BCT 计算回旋镖连接表（Boomerang Connectivity Table）:
+// BCT[a][b] = #{x | F^{-1}(F(x)⊕b) ⊕ F^{-1}(F(x⊕a)⊕b) = a}.
+// 仅对置换有定义。
+func (vf *VectorialBooleanFunction) BCT() ([][]int, error) {
+	if !vf.IsPermutation() {
+		return nil, errors.New("BCT is only defined for permutations (n = m and bijective)")
+	}
+	length := 1 << vf.n
+	inverse := make([]int, length)
+	for x, y := range vf.lut {
+		inverse[y] = x
+	}
+
+	bct := make([][]int, length)
+	for a := 0; a < length; a++ {
+		row := make([]int, length)
+		for b := 0; b < length; b++ {
+			count := 0
+			for x := 0; x < length; x++ {
+				if inverse[vf.lut[x]^b]^inverse[vf.lut[x^a]^b] == a {
+					count++
+				}
+			}
+			row[b] = count
+		}
+		bct[a] = row
+	}
+	return bct, nil
+}
+
+// BoomerangUniformity 计算回旋镖均匀度 max_{a≠0, b≠0} BCT[a][b].
+func (vf *VectorialBooleanFunction) BoomerangUniformity() (int, error) {
+	bct, err := vf.BCT()
+	if err != nil {
+		return -1, err
+	}
+	maxCount := 0
+	for a := 1; a < len(bct); a++ {
+		for b := 1; b < len(bct[a]); b++ {
+			if bct[a][b] > maxCount {
+				maxCount = bct[a][b]
+			}
+		}
+	}
+	return maxCount, nil
+}
+
+// --- 线性性质 ---
+
+// LAT 计算线性逼近表 LAT[a][b] = #{x | a·x = b·F(x)} - 2^(n-1).
+// 对 b ≠ 0 有 LAT[a][b] = W_{b·F}(a) / 2，分量 Walsh 谱复用 fwhtInplace 计算。
+func (vf *VectorialBooleanFunction) LAT() [][]int64 {
+	length := 1 << vf.n
+	lat := make([][]int64, length)
+	for a := range lat {
+		lat[a] = make([]int64, 1<<vf.m)
+	}
+	lat[0][0] = int64(length / 2)
+
+	for b := 1; b < 1<<vf.m; b++ {
+		wht := vf.componentWalsh(b)
+		for a := 0; a < length; a++ {
+			lat[a][b] = wht[a] / 2
+		}
+	}
+	return lat
+}
+
+// Linearity 计算线性度 L(F) = max_{a, b≠0} |W_{b·F}(a)|.
+func (vf *VectorialBooleanFunction) Linearity() int64 {
+	var maxAbs int64 = 0
+	for b := 1; b < 1<<vf.m; b++ {
+		for _, v := range vf.componentWalsh(b) {
+			if v < 0 {
+				v = -v
+			}
+			if v > maxAbs {
+				maxAbs = v
+			}
+		}
+	}
+	return maxAbs
+}
+
+// Nonlinearity 计算非线性度 NL(F) = 2^(n-1) - L(F)/2，即所有分量函数非线性度的最小值.
+func (vf *VectorialBooleanFunction) Nonlinearity() int64 {
+	return (1 << (vf.n - 1)) - vf.Linearity()/2
+}
+
+// --- 代数性质 ---
+
+// ComponentDegrees 返回所有非零分量函数 v·F 的代数次数，下标 v-1 对应分量 v.
+func (vf *VectorialBooleanFunction) ComponentDegrees() []int {
+	degrees := make([]int, (1<<vf.m)-1)
+	for v := 1; v < 1<<vf.m; v++ {
+		degrees[v-1] = vf.component(v).AlgebraicDegree()
+	}
+	return degrees
+}
+
+// AlgebraicDegree 计算 F 的代数次数，即所有坐标函数代数次数的最大值.
+func (vf *VectorialBooleanFunction) AlgebraicDegree() int {
+	maxDegree := 0
+	for _, c := range vf.coordinates {
+		if d := c.AlgebraicDegree(); d > maxDegree {
+			maxDegree = d
+		}
+	}
+	return maxDegree
+}
+
+// MinComponentDegree 返回所有非零分量函数中的最小代数次数.
+func (vf *VectorialBooleanFunction) MinComponentDegree() int {
+	minDegree := vf.n
+	for _, d := range vf.ComponentDegrees() {
+		if d < minDegree {
+			minDegree = d
+		}
+	}
+	return minDegree
+}
+
+// --- 私有实现 ---
+
+// component 在打包字上异或坐标函数得到分量函数 v·F.
+func (vf *VectorialBooleanFunction) component(v int) *BooleanFunction {
+	packed := make([]uint64, len(vf.coordinates[0].packedTruthTable))
+	for i := 0; i < vf.m; i++ {
+		if (v>>i)&1 == 1 {
+			for w, word := range vf.coordinates[i].packedTruthTable {
+				packed[w] ^= word
+			}
+		}
+	}
+	return &BooleanFunction{n: vf.n, packedTruthTable: packed}
+}
+
+// componentWalsh 计算分量函数 v·F 的 Walsh 谱，直接复用 BooleanFunction 的打包存储与 fwhtInplace.
+func (vf *VectorialBooleanFunction) componentWalsh(v int) []int64 {
+	return vf.component(v).walsh()
+}
@@ -0,0 +1,108 @@
+package booleancore
+
+import (
+	"testing"
+)
+
+// presentSBox 是 PRESENT 分组密码的 4 位 S 盒，差分均匀度为 4，线性度为 8.
+var presentSBox = []int{0xC, 0x5, 0x6, 0xB, 0x9, 0x0, 0xA, 0xD, 0x3, 0xE, 0xF, 0x8, 0x4, 0x7, 0x1, 0x2}
+
+// TestVectorialPresentSBox 使用 PRESENT S 盒验证 DDT/LAT/BCT 及派生指标
+func TestVectorialPresentSBox(t *testing.T) {
+	sbox, err := NewVectorialFromLUT(presentSBox, 4)
+	if err != nil {
+		t.Fatalf("NewVectorialFromLUT error: %v", err)
+	}
+
+	if du := sbox.DifferentialUniformity(); du != 4 {
+		t.Errorf("差分均匀度不匹配: 期望 4, 实际 %d", du)
+	}
+	if l := sbox.Linearity(); l != 8 {
+		t.Errorf("线性度不匹配: 期望 8, 实际 %d", l)
+	}
+	if nl := sbox.Nonlinearity(); nl != 4 {
+		t.Errorf("非线性度不匹配: 期望 4, 实际 %d", nl)
+	}
+	if deg := sbox.AlgebraicDegree(); deg != 3 {
+		t.Errorf("代数次数不匹配: 期望 3, 实际 %d", deg)
+	}
+
+	// DDT 每行之和为 2^n，且 DDT[0][0] = 2^n
+	ddt := sbox.DDT()
+	if ddt[0][0] != 16 {
+		t.Errorf("DDT[0][0] 应为 16, 实际 %d", ddt[0][0])
+	}
+	for a, row := range ddt {
+		sum := 0
+		for _, v := range row {
+			sum += v
+		}
+		if sum != 16 {
+			t.Errorf("DDT 第 %d 行之和应为 16, 实际 %d", a, sum)
+		}
+	}
+
+	// LAT 与分量函数的 Walsh 谱一致
+	lat := sbox.LAT()
+	for b := 1; b < 16; b++ {
+		comp, err := sbox.Component(b)
+		if err != nil {
+			t.Fatalf("Component(%d) error: %v", b, err)
+		}
+		wht := comp.WalshHadamardTransform()
+		for a := 0; a < 16; a++ {
+			if 2*lat[a][b] != wht[a] {
+				t.Fatalf("LAT[%d][%d]=%d 与 W/2=%d 不一致", a, b, lat[a][b], wht[a]/2)
+			}
+		}
+	}
+
+	// BCT 首行首列为 2^n，且逐项不小于 DDT
+	bct, err := sbox.BCT()
+	if err != nil {
+		t.Fatalf("BCT error: %v", err)
+	}
+	for a := 0; a < 16; a++ {
+		if bct[a][0] != 16 || bct[0][a] != 16 {
+			t.Errorf("BCT 第 %d 行/列边界应为 16, 实际 %d/%d", a, bct[a][0], bct[0][a])
+		}
+		for b := 0; b < 16; b++ {
+			if bct[a][b] < ddt[a][b] {
+				t.Errorf("BCT[%d][%d]=%d 小于 DDT=%d", a, b, bct[a][b], ddt[a][b])
+			}
+		}
+	}
+}
+
+// TestVectorialFromCoordinates 验证坐标函数构造与查找表构造一致
+func TestVectorialFromCoordinates(t *testing.T) {
+	sbox, err := NewVectorialFromLUT(presentSBox, 4)
+	if err != nil {
+		t.Fatalf("NewVectorialFromLUT error: %v", err)
+	}
+	coords := make([]*BooleanFunction, 4)
+	for i := range coords {
+		coords[i], err = sbox.Coordinate(i)
+		if err != nil {
+			t.Fatalf("Coordinate(%d) error: %v", i, err)
+		}
+	}
+	rebuilt, err := NewVectorialFromCoordinates(coords)
+	if err != nil {
+		t.Fatalf("NewVectorialFromCoordinates error: %v", err)
+	}
+	for x, y := range rebuilt.LookupTable() {
+		if y != presentSBox[x] {
+			t.Fatalf("查找表第 %d 项不一致: 期望 %d, 实际 %d", x, presentSBox[x], y)
+		}
+	}
+	if _, err := NewVectorialFromCoordinates([]*BooleanFunction{nil, coords[1]}); err == nil {
+		t.Error("坐标函数为 nil 时应返回错误")
+	}
+
+	// 非置换的 S 盒没有 BCT
+	notPerm, _ := NewVectorialFromLUT([]int{0, 1, 1, 0}, 1)
+	if _, err := notPerm.BCT(); err == nil {
+		t.Error("非置换应返回 BCT 错误")
+	}
+}